@@ -3,9 +3,19 @@ module github.com/nayotta/metathings-component-camera
 go 1.12
 
 require (
-	github.com/golang/protobuf v1.3.2
+	github.com/golang/protobuf v1.5.2
+	github.com/mwitkow/go-proto-validators v0.1.0
 	github.com/nayotta/metathings v1.1.13
 	github.com/nayotta/viper v1.0.2
+	github.com/pion/interceptor v0.1.29
+	github.com/pion/rtp v1.8.7
+	github.com/pion/webrtc/v3 v3.3.6
 	github.com/sirupsen/logrus v1.4.2
+	github.com/spf13/viper v1.5.0
+	golang.org/x/image v0.0.0-20190802002840-cff245a6509b
 	google.golang.org/grpc v1.23.0
 )
+
+// metathings protos are registered by protobuf v1.3 runtime,
+// newer runtime is only required by tests of dependencies.
+replace github.com/golang/protobuf => github.com/golang/protobuf v1.3.2
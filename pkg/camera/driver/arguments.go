@@ -0,0 +1,109 @@
+package camera_driver
+
+import (
+	"regexp"
+	"strings"
+)
+
+// split_arguments splits s into arguments like posix shell,
+// but without any expansion, only quotes and backslash are supported.
+// Like posix shell, backslash in double quotes escapes only `$`, "`", `"`, `\` and newline,
+// before other characters it is kept, and backslash newline is removed as line continuation.
+func split_arguments(s string) ([]string, error) {
+	var args []string
+	var buf strings.Builder
+	var quote rune
+	var escape, in_arg bool
+
+	for _, c := range s {
+		switch {
+		case escape && quote == '"':
+			switch c {
+			case '$', '`', '"', '\\':
+				buf.WriteRune(c)
+			case '\n':
+			default:
+				buf.WriteRune('\\')
+				buf.WriteRune(c)
+			}
+			escape = false
+		case escape:
+			if c != '\n' {
+				buf.WriteRune(c)
+				in_arg = true
+			}
+			escape = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				buf.WriteRune(c)
+			}
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else if c == '\\' {
+				escape = true
+			} else {
+				buf.WriteRune(c)
+			}
+		case c == '\\':
+			escape = true
+		case c == '\'' || c == '"':
+			quote = c
+			in_arg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if in_arg {
+				args = append(args, buf.String())
+				buf.Reset()
+				in_arg = false
+			}
+		default:
+			buf.WriteRune(c)
+			in_arg = true
+		}
+	}
+
+	if escape {
+		return nil, ErrUnterminatedEscape
+	}
+
+	if quote != 0 {
+		return nil, ErrUnterminatedQuote
+	}
+
+	if in_arg {
+		args = append(args, buf.String())
+	}
+
+	return args, nil
+}
+
+func split_arguments_slice(xs []string) ([]string, error) {
+	var ys []string
+
+	for _, x := range xs {
+		args, err := split_arguments(x)
+		if err != nil {
+			return nil, err
+		}
+		ys = append(ys, args...)
+	}
+
+	return ys, nil
+}
+
+// password may contain `/` and `@`, redacted up to the last `@` of argument.
+var redact_url_password_regexp = regexp.MustCompile(`([A-Za-z][A-Za-z0-9+.-]*://[^:/@\s]*:)\S*@`)
+
+const REDACTED_PASSWORD = "xxxxx"
+
+// RedactArguments returns a copy of args with url passwords redacted,
+// used for logging.
+func RedactArguments(args []string) []string {
+	ys := make([]string, len(args))
+	for i, x := range args {
+		ys[i] = redact_url_password_regexp.ReplaceAllString(x, "${1}"+REDACTED_PASSWORD+"@")
+	}
+	return ys
+}
@@ -0,0 +1,297 @@
+package camera_driver
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+/*
+ * Buffer: keep rolling buffer of encoded segments for pre-event capture,
+ *   by `segment` muxer of ffmpeg framework, framework keeps running while buffer configured.
+ * Options:
+ *   driver:
+ *   ...
+ *     buffer:
+ *       [ path: <dir> ]  // directory of buffer segments, default `<work dir>/camera-buffer`,
+ *                        // or `/dev/shm/camera-buffer` if work dir not set and tmpfs available,
+ *                        // segments `<index>.ts` are removed when framework launched, other files are kept.
+ *       [ segment_time: <duration> ]  // buffer segment duration, event clip is rounded to segments, default `2s`.
+ *       [ duration: <duration> ]  // max pre-event duration, default `30s`.
+ *       [ pre: <duration> ]  // default pre-event duration, default `duration`.
+ *       [ post: <duration> ]  // default post-event duration, default `10s`.
+ *       [ max_post: <duration> ]  // max post-event duration, default `1m`.
+ *       [ format: <format> ]  // event clip format, `matroska`(default) or `mp4`.
+ *       [ chunk_size: <size> ]  // object size of event clip parts, default `4M`.
+ *   ...
+ */
+
+const (
+	BUFFER_DEFAULT_SEGMENT_TIME = 2 * time.Second
+	BUFFER_DEFAULT_DURATION     = 30 * time.Second
+	BUFFER_DEFAULT_POST         = 10 * time.Second
+	BUFFER_DEFAULT_MAX_POST     = 1 * time.Minute
+
+	// framework output label of buffer output.
+	BUFFER_OUTPUT_LABEL = "_buffer"
+
+	buffer_segment_ext = ".ts"
+	// segment file name written by framework, other files in directory are ignored and kept.
+	buffer_segment_pattern = "[0-9][0-9][0-9][0-9]" + buffer_segment_ext
+)
+
+type BufferConfig struct {
+	Path        string
+	SegmentTime time.Duration
+	Duration    time.Duration
+	Pre         time.Duration
+	Post        time.Duration
+	MaxPost     time.Duration
+	Format      string
+	ChunkSize   int64
+}
+
+// wrap returns segment count of ring, covers pre and post event duration,
+// and segments being written or copied.
+func (c *BufferConfig) wrap() int {
+	return int((c.Duration+c.MaxPost)/c.SegmentTime) + 3
+}
+
+// apply_output adds buffer output to framework option.
+// NOTE: fw should be a cloned option, it will be modified.
+func (c *BufferConfig) apply_output(fw *CameraDriverOption) {
+	k := "outputs." + BUFFER_OUTPUT_LABEL
+
+	fw.Set(k+".format", "segment")
+	fw.Set(k+".file", filepath.Join(c.Path, "%04d"+buffer_segment_ext))
+	fw.Set(k+".options.segment_format", "mpegts")
+	fw.Set(k+".options.segment_time", strconv.FormatFloat(c.SegmentTime.Seconds(), 'f', -1, 64))
+	fw.Set(k+".options.segment_wrap", strconv.Itoa(c.wrap()))
+	fw.Set(k+".options.reset_timestamps", "1")
+}
+
+func is_buffer_segment(info os.FileInfo) bool {
+	ok, _ := filepath.Match(buffer_segment_pattern, info.Name())
+	return ok && !info.IsDir()
+}
+
+// clean removes segments of last framework, they are not continuous with new ones.
+func (c *BufferConfig) clean() {
+	infos, err := ioutil.ReadDir(c.Path)
+	if err != nil {
+		return
+	}
+
+	for _, info := range infos {
+		if is_buffer_segment(info) {
+			os.Remove(filepath.Join(c.Path, info.Name()))
+		}
+	}
+}
+
+// completed_segments returns finished segments modified after since, ordered by modify time,
+// the newest segment is being written.
+func (c *BufferConfig) completed_segments(since time.Time) ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(c.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var segs []os.FileInfo
+	for _, info := range infos {
+		if !is_buffer_segment(info) {
+			continue
+		}
+		segs = append(segs, info)
+	}
+
+	if len(segs) == 0 {
+		return nil, nil
+	}
+
+	sort_file_infos_by_mod_time(segs)
+	segs = segs[:len(segs)-1]
+
+	var xs []os.FileInfo
+	for _, seg := range segs {
+		if seg.ModTime().After(since) {
+			xs = append(xs, seg)
+		}
+	}
+
+	return xs, nil
+}
+
+func copy_file(src, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// buffer_event captures segments of buffer around trigger time into dir.
+type buffer_event struct {
+	cfg *BufferConfig
+	dir string
+
+	files    []string
+	last_mod time.Time
+	start_at time.Time
+	end_at   time.Time
+}
+
+func new_buffer_event(cfg *BufferConfig, dir string, trigger_at time.Time, pre time.Duration) *buffer_event {
+	return &buffer_event{
+		cfg: cfg,
+		dir: dir,
+		// segments end after pre-event begin contain pre-event frames.
+		last_mod: trigger_at.Add(-pre),
+	}
+}
+
+// capture copies completed segments after last captured one before overwritten by ring,
+// stops after segment ends after until, zero until means no limit.
+func (e *buffer_event) capture(until time.Time) error {
+	segs, err := e.cfg.completed_segments(e.last_mod)
+	if err != nil {
+		return err
+	}
+
+	for _, seg := range segs {
+		file := filepath.Join(e.dir, fmt.Sprintf("%06d%v", len(e.files), buffer_segment_ext))
+		if err = copy_file(filepath.Join(e.cfg.Path, seg.Name()), file); err != nil {
+			return err
+		}
+
+		if len(e.files) == 0 {
+			e.start_at = seg.ModTime().Add(-e.cfg.SegmentTime)
+		}
+		e.files = append(e.files, file)
+		e.last_mod = seg.ModTime()
+		e.end_at = seg.ModTime()
+
+		if !until.IsZero() && !seg.ModTime().Before(until) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (e *buffer_event) write_concat_list(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "ffconcat version 1.0"); err != nil {
+		return err
+	}
+
+	for _, file := range e.files {
+		if _, err := fmt.Fprintf(w, "file %v\n", ffconcat_quote(file)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func event_object(trigger_at time.Time, label string) string {
+	obj := "events/" + trigger_at.UTC().Format("20060102T150405.000Z")
+	if label != "" {
+		obj += "-" + label
+	}
+
+	return obj
+}
+
+func new_buffer_config(opt *CameraDriverOption, work_dir string) (*BufferConfig, error) {
+	if opt == nil {
+		return nil, nil
+	}
+
+	c := &BufferConfig{
+		Path:        filepath.Join(os.TempDir(), "camera-buffer"),
+		SegmentTime: BUFFER_DEFAULT_SEGMENT_TIME,
+		Duration:    BUFFER_DEFAULT_DURATION,
+		Post:        BUFFER_DEFAULT_POST,
+		MaxPost:     BUFFER_DEFAULT_MAX_POST,
+		Format:      RECORD_FORMAT_MATROSKA,
+		ChunkSize:   RECORD_DEFAULT_CHUNK_SIZE,
+	}
+
+	if work_dir != "" {
+		c.Path = filepath.Join(work_dir, "camera-buffer")
+	} else if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+		c.Path = "/dev/shm/camera-buffer"
+	}
+
+	if val := opt.GetString("path"); val != "" {
+		c.Path = val
+	}
+
+	if opt.IsSet("segment_time") {
+		if c.SegmentTime = opt.GetDuration("segment_time"); c.SegmentTime < time.Second {
+			return nil, new_invalid_config_error("buffer.segment_time")
+		}
+	}
+
+	if opt.IsSet("duration") {
+		if c.Duration = opt.GetDuration("duration"); c.Duration < c.SegmentTime {
+			return nil, new_invalid_config_error("buffer.duration")
+		}
+	}
+
+	c.Pre = c.Duration
+	if opt.IsSet("pre") {
+		if c.Pre = opt.GetDuration("pre"); c.Pre < 0 || c.Pre > c.Duration {
+			return nil, new_invalid_config_error("buffer.pre")
+		}
+	}
+
+	if opt.IsSet("max_post") {
+		if c.MaxPost = opt.GetDuration("max_post"); c.MaxPost < 0 {
+			return nil, new_invalid_config_error("buffer.max_post")
+		}
+	}
+
+	if opt.IsSet("post") {
+		if c.Post = opt.GetDuration("post"); c.Post < 0 {
+			return nil, new_invalid_config_error("buffer.post")
+		}
+	}
+
+	if c.Post > c.MaxPost {
+		return nil, new_invalid_config_error("buffer.post")
+	}
+
+	if val := opt.GetString("format"); val != "" {
+		if _, ok := record_file_exts[val]; !ok {
+			return nil, new_invalid_config_error("buffer.format")
+		}
+		c.Format = val
+	}
+
+	if val := opt.GetString("chunk_size"); val != "" {
+		size, err := parse_size(val)
+		if err != nil || size <= 0 {
+			return nil, new_invalid_config_error("buffer.chunk_size")
+		}
+		c.ChunkSize = size
+	}
+
+	return c, nil
+}
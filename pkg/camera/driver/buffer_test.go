@@ -0,0 +1,50 @@
+package camera_driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBufferCleanKeepsOtherFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	segs := []string{"0000.ts", "0001.ts", "0042.ts"}
+	others := []string{"video.ts", "12345.ts", "0003.mp4", "notes.txt"}
+	for _, name := range append(segs, others...) {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &BufferConfig{Path: dir}
+
+	// the newest segment is being written.
+	completed, err := cfg.completed_segments(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(completed) != len(segs)-1 {
+		t.Errorf("completed segments %v, want %v", len(completed), len(segs)-1)
+	}
+
+	cfg.clean()
+
+	for _, name := range segs {
+		if _, err = os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("segment %v not removed", name)
+		}
+	}
+
+	for _, name := range others {
+		if _, err = os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("file %v removed: %v", name, err)
+		}
+	}
+}
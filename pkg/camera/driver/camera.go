@@ -1,8 +1,13 @@
 package camera_driver
 
 import (
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -20,6 +25,29 @@ func (o *CameraDriverOption) Sub(key string) *CameraDriverOption {
 	return &CameraDriverOption{sub}
 }
 
+// Clone deep copies option, changes on clone will not leak into origin.
+func (o *CameraDriverOption) Clone() *CameraDriverOption {
+	v := viper.New()
+	for _, k := range o.AllKeys() {
+		v.Set(k, o.Get(k))
+	}
+
+	return &CameraDriverOption{v}
+}
+
+// CloneExcept deep copies option without key and its children.
+func (o *CameraDriverOption) CloneExcept(key string) *CameraDriverOption {
+	v := viper.New()
+	for _, k := range o.AllKeys() {
+		if k == key || strings.HasPrefix(k, key+".") {
+			continue
+		}
+		v.Set(k, o.Get(k))
+	}
+
+	return &CameraDriverOption{v}
+}
+
 func (o *CameraDriverOption) NextKeys() []string {
 	m := map[string]bool{}
 	for _, k := range o.AllKeys() {
@@ -39,10 +67,39 @@ func (o *CameraDriverOption) NextKeys() []string {
 	for x := range m {
 		a = append(a, x)
 	}
+	sort_labels(a)
 
 	return a
 }
 
+// sort_labels sorts labels, numeric labels in numeric order before others.
+func sort_labels(a []string) {
+	sort.Slice(a, func(i, j int) bool {
+		x, xerr := strconv.Atoi(a[i])
+		y, yerr := strconv.Atoi(a[j])
+
+		switch {
+		case xerr == nil && yerr == nil:
+			return x < y
+		case xerr == nil:
+			return true
+		case yerr == nil:
+			return false
+		default:
+			return a[i] < a[j]
+		}
+	})
+}
+
+// default_work_dir returns base directory of default working directories, system temp directory if not set.
+func default_work_dir(dir string) string {
+	if dir == "" {
+		return os.TempDir()
+	}
+
+	return dir
+}
+
 type CameraDriverState struct {
 	state string
 }
@@ -51,15 +108,239 @@ func (s *CameraDriverState) String() string {
 	return s.state
 }
 
+// Started reports camera is started, streaming or waiting for device plugged back.
+func (s *CameraDriverState) Started() bool {
+	return s == CAMERA_DRIVER_STATE_ON || s == CAMERA_DRIVER_STATE_DISCONNECTED
+}
+
 var (
 	CAMERA_DRIVER_STATE_ON  = &CameraDriverState{state: "on"}
 	CAMERA_DRIVER_STATE_OFF = &CameraDriverState{state: "off"}
+	// started camera waits for device plugged back, resumed automatically.
+	CAMERA_DRIVER_STATE_DISCONNECTED = &CameraDriverState{state: "disconnected"}
 )
 
+type CameraDriverOutput struct {
+	Label     string
+	Url       string
+	LiveId    string
+	Playbacks map[string]string
+	// File is framework output file, differs from published url if url masks secret.
+	File string
+}
+
+// CameraDriverStartOption overrides driver config for one session,
+// zero value fields keep the configured value, output overrides the first output only.
+type CameraDriverStartOption struct {
+	FrameSize string
+	FrameRate int
+	BitRate   string
+	Codec     string
+	Output    string
+	Audio     *bool
+}
+
+type CameraDriverStatus struct {
+	State          *CameraDriverState
+	Outputs        []*CameraDriverOutput
+	Recording      bool
+	Pid            int
+	StartAt        time.Time
+	RestartCount   int
+	LastError      error
+	LastExitReason string
+	Stats          *FrameworkStats
+	Logs           []string
+	Schedule       *CameraDriverScheduleStatus
+}
+
+// CameraDriverScheduleStatus is status of scheduled camera driver,
+// zero next transition time means no more transition.
+type CameraDriverScheduleStatus struct {
+	Override         bool
+	NextTransitionAt time.Time
+	NextState        *CameraDriverState
+}
+
+func (s *CameraDriverStatus) Uptime() time.Duration {
+	if s.State != CAMERA_DRIVER_STATE_ON || s.StartAt.IsZero() {
+		return 0
+	}
+
+	return time.Since(s.StartAt)
+}
+
+// CameraDriverSnapshotOption overrides snapshot config for one snapshot,
+// zero value fields keep the configured value.
+type CameraDriverSnapshotOption struct {
+	Format    string
+	FrameSize string
+	Quality   int
+	Store     *bool
+}
+
+type CameraDriverSnapshot struct {
+	Content   []byte
+	Format    string
+	Object    string
+	Timestamp time.Time
+}
+
 type CameraDriver interface {
-	Start() error
+	Start(opt *CameraDriverStartOption) ([]*CameraDriverOutput, error)
 	Stop() error
 	State() *CameraDriverState
+	Status() *CameraDriverStatus
+}
+
+// CameraDriverWrapper is implemented by camera driver wraps another camera driver,
+// optional interfaces of wrapped driver are found by `AsCameraDriver`.
+type CameraDriverWrapper interface {
+	Unwrap() CameraDriver
+}
+
+// AsCameraDriver finds the first driver in wrapper chain implements interface of target,
+// target should be pointer to interface, like `errors.As`.
+func AsCameraDriver(drv CameraDriver, target interface{}) bool {
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Interface {
+		panic("target must be a non-nil pointer to interface")
+	}
+	typ := val.Elem().Type()
+
+	for drv != nil {
+		if reflect.TypeOf(drv).Implements(typ) {
+			val.Elem().Set(reflect.ValueOf(drv))
+			return true
+		}
+
+		w, ok := drv.(CameraDriverWrapper)
+		if !ok {
+			break
+		}
+		drv = w.Unwrap()
+	}
+
+	return false
+}
+
+// CameraDriverSnapshotter is implemented by camera driver supports snapshot.
+type CameraDriverSnapshotter interface {
+	Snapshot(opt *CameraDriverSnapshotOption) (*CameraDriverSnapshot, error)
+}
+
+// CameraDriverRecorder is implemented by camera driver supports local recording.
+type CameraDriverRecorder interface {
+	StartRecording() error
+	StopRecording() error
+}
+
+type CameraDriverRecording struct {
+	Name      string
+	StartAt   time.Time
+	EndAt     time.Time
+	Size      int64
+	Format    string
+	Recording bool
+}
+
+// CameraDriverClip is clip uploaded as part objects under object,
+// parts are ordered, `<object>/manifest` object lists parts.
+type CameraDriverClip struct {
+	Object  string
+	Parts   []string
+	Size    int64
+	Format  string
+	StartAt time.Time
+	EndAt   time.Time
+}
+
+// CameraDriverRecordingStore is implemented by camera driver supports local recording.
+type CameraDriverRecordingStore interface {
+	// zero begin or end means unbounded.
+	ListRecordings(begin, end time.Time) ([]*CameraDriverRecording, error)
+	GetRecording(name string) (*CameraDriverRecording, error)
+	ExportClip(begin, end time.Time) (*CameraDriverClip, error)
+}
+
+// CameraDriverEventOption overrides buffer config for one event,
+// nil durations keep the configured value.
+type CameraDriverEventOption struct {
+	Label string
+	Pre   *time.Duration
+	Post  *time.Duration
+}
+
+// CameraDriverEvent is event clip published as part objects under object,
+// like `CameraDriverClip`, start and end time are estimated when triggered.
+type CameraDriverEvent struct {
+	Object    string
+	Label     string
+	TriggerAt time.Time
+	StartAt   time.Time
+	EndAt     time.Time
+}
+
+// CameraDriverEventTrigger is implemented by camera driver supports pre-event buffer.
+type CameraDriverEventTrigger interface {
+	TriggerEvent(opt *CameraDriverEventOption) (*CameraDriverEvent, error)
+}
+
+// MotionRegion is region in normalized coordinates, 0 to 1 of frame width and height.
+type MotionRegion struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+type CameraDriverMotionEvent struct {
+	Id        string
+	Timestamp time.Time
+	Score     float64
+	Region    MotionRegion
+	Object    string
+	Snapshot  string
+}
+
+// CameraDriverMotionDetector is implemented by camera driver supports motion detection.
+type CameraDriverMotionDetector interface {
+	// zero begin or end means unbounded.
+	ListMotionEvents(begin, end time.Time) ([]*CameraDriverMotionEvent, error)
+}
+
+// CameraDriverTimelapse is assembled timelapse sequence uploaded as clip.
+type CameraDriverTimelapse struct {
+	Sequence string
+	Frames   int
+	Clip     *CameraDriverClip
+}
+
+// CameraDriverTimelapser is implemented by camera driver captures timelapse.
+type CameraDriverTimelapser interface {
+	// AssembleTimelapse closes current sequence, assembles and uploads it.
+	AssembleTimelapse() (*CameraDriverTimelapse, error)
+}
+
+// CameraDriverStreamKeyRotator is implemented by camera driver publishes stream by secret url.
+type CameraDriverStreamKeyRotator interface {
+	// RotateStreamKey restarts streaming with new stream key and signatures, returns new outputs.
+	RotateStreamKey() ([]*CameraDriverOutput, error)
+}
+
+type CameraDriverWebrtcSession struct {
+	Id     string
+	Label  string
+	Answer string
+}
+
+// CameraDriverWebrtcNegotiator is implemented by camera driver sends stream to browsers by webrtc.
+type CameraDriverWebrtcNegotiator interface {
+	// NegotiateWebrtc creates session of webrtc output by sdp offer, returns sdp answer,
+	// label is optional when there is only one webrtc output.
+	NegotiateWebrtc(label, offer string) (*CameraDriverWebrtcSession, error)
+	// CloseWebrtc closes session.
+	CloseWebrtc(id string) error
 }
 
 type CameraDriverFactory func(opt *CameraDriverOption, args ...interface{}) (CameraDriver, error)
@@ -81,5 +362,14 @@ func NewCameraDriver(name string, opt *CameraDriverOption, args ...interface{})
 		return nil, ErrInvalidCameraDriver
 	}
 
-	return fty(opt, args...)
+	drv, err := fty(opt, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if sched := opt.Sub("schedule"); sched != nil {
+		return NewScheduledCameraDriver(drv, sched, args...)
+	}
+
+	return drv, nil
 }
@@ -3,13 +3,34 @@ package camera_driver
 import (
 	"errors"
 	"fmt"
+
+	webrtc "github.com/nayotta/metathings-component-camera/pkg/camera/webrtc"
 )
 
 var (
-	ErrInvalidCameraDriver = errors.New("invalid camera driver")
-	ErrInvalidFramework    = errors.New("invalid framework")
-	ErrNotStartable        = errors.New("not startable")
-	ErrNotStoppable        = errors.New("not stoppable")
+	ErrInvalidCameraDriver    = errors.New("invalid camera driver")
+	ErrInvalidFramework       = errors.New("invalid framework")
+	ErrNotStartable           = errors.New("not startable")
+	ErrNotStoppable           = errors.New("not stoppable")
+	ErrUnterminatedQuote      = errors.New("unterminated quote")
+	ErrUnterminatedEscape     = errors.New("unterminated escape")
+	ErrInvalidFrameSize       = errors.New("invalid frame size")
+	ErrInvalidSnapshotFormat  = errors.New("invalid snapshot format")
+	ErrInvalidSnapshotQuality = errors.New("invalid snapshot quality")
+	ErrSnapshotUnavailable    = errors.New("snapshot unavailable")
+	ErrSnapshotTimeout        = errors.New("snapshot timeout")
+	ErrRecordingNotFound      = errors.New("recording not found")
+	ErrInvalidClipRange       = errors.New("invalid clip range")
+	ErrInvalidEventDuration   = errors.New("invalid event duration")
+	ErrBufferUnavailable      = errors.New("buffer unavailable")
+	ErrMotionUnavailable      = errors.New("motion detection unavailable")
+	ErrTimelapseEmpty         = errors.New("no timelapse frames")
+	ErrNotStreaming           = errors.New("not streaming")
+	ErrWebrtcOutputNotFound   = errors.New("webrtc output not found")
+	ErrInvalidWebrtcOffer     = webrtc.ErrInvalidOffer
+	ErrWebrtcSessionNotFound  = webrtc.ErrSessionNotFound
+	ErrTooManyWebrtcSessions  = webrtc.ErrTooManySessions
+	ErrInvalidMjpegFrame      = errors.New("invalid mjpeg frame")
 )
 
 func new_invalid_config_error(key string) error {
@@ -1,9 +1,8 @@
 package camera_driver
 
 import (
-	"context"
 	"fmt"
-	"os/exec"
+	"sort"
 	"strings"
 	"sync"
 
@@ -19,22 +18,35 @@ import (
  *     framework:
  *       name: ffmpeg
  *       [ binary: ffmpeg ]  // ffmpeg binary file path.
- *       inputs:
+ *       [ log_lines: <count> ]  // recent stderr lines kept in memory, default 64.
+ *       [ error_lines: <count> ]  // last stderr lines attached to exit error, default 8.
+ *       [ stop_timeout: <duration> ]  // wait exit after interrupt before kill, default `5s`, 0 kills at once.
+ *       inputs:  // multiple inputs, like separate video and audio sources.
  *         0:
- *           format: <format>  // input file format, like `v4l2`.
+ *           format: <format>  // input file format, like `v4l2`, `alsa`.
  *           file: <path>  // file path, like `/dev/video0`, set from camera driver.
  *           [ frame_size: <width>x<height> ]  // frame size, like `640x480`.
  *           [ frame_rate: <rate> ]  // frame rate, like `30`.
- *       outputs:
+ *       outputs:  // multiple outputs are muxed by `tee` muxer, encoded once.
  *         0:
- *           format: <format>  // output file format, like `flv`.
+ *           format: <format>  // output file format, like `flv`, `rtp` carries video only.
  *           [ file: <path> ]  // file path, like `rtmp://rtmp-server:port/path`.
+ *           [ options: ]  // muxer options, like `update: 1` for `image2`, `segment_time: 600` for `segment`.
+ *           [ video: ]  // output with own video or audio section is encoded separately.
+ *             [ codec: ]  // same as video codec below.
+ *             [ frame_size: <width>x<height> ]  // scale video.
+ *             [ frame_rate: <rate> ]  // like `30` or `1/5`.
+ *             [ pixel_format: <format> ]  // like `gray`.
+ *             [ quality: <1-100> ]  // image quality for `mjpeg` codec.
+ *             [ frames: <count> ]  // stop after frames.
+ *           [ audio: ]  // same as audio below, audio disabled if absent.
  *       video:
  *         codec:
  *           name: <codec>  // video codec, like `h264_omx` for raspberry pi.
  *           [ bit_rate: <rate> ]  // video bitrate, like `2000k`.
- *           [ extra: [ ... ] ]  // list of extra arguments for codec.
+ *           [ extra: [ ... ] ]  // list of extra arguments for codec, split like shell words without expansion.
  *       audio:
+ *         [ disable: <bool> ]  // disable audio, same as no audio section.
  *         codec:
  *           name: <codec>  // audio codec, like `copy` for copy rtsp to rtmp
  *   ...
@@ -45,203 +57,310 @@ const (
 	FFMPEG_FRAMEWORK_DEAFULT_BINARY = `ffmpeg`
 )
 
-func (f *FFmpegFramework) parse_ffmpeg_command() (string, error) {
-	var cmd_str string
+// ffmpeg_binary returns ffmpeg binary of driver for concat and cut,
+// binary of framework is used if framework is ffmpeg.
+func ffmpeg_binary(opt *CameraDriverOption) string {
+	if opt.GetString("framework.name") == "ffmpeg" {
+		if val := opt.GetString("framework.binary"); val != "" {
+			return val
+		}
+	}
+
+	return FFMPEG_FRAMEWORK_DEAFULT_BINARY
+}
+
+var ffmpeg_tee_slave_escaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, `'`, `\'`)
+
+func escape_ffmpeg_tee_slave(file string) string {
+	return ffmpeg_tee_slave_escaper.Replace(file)
+}
 
-	if val := f.opt.GetString("binary"); val != "" {
-		cmd_str = val
+// ffmpeg_video_only_formats carry one video stream, audio is not muxed.
+var ffmpeg_video_only_formats = map[string]bool{
+	"rtp": true,
+}
+
+// is_custom_output reports output has own encoding settings,
+// custom outputs are encoded separately instead of sharing default encoding.
+func is_custom_output(output *FrameworkOption) bool {
+	return output.IsSet("video") || output.IsSet("audio")
+}
+
+var ffmpeg_tee_option_escaper = strings.NewReplacer(`\`, `\\`, `:`, `\:`, `[`, `\[`, `]`, `\]`, `|`, `\|`)
+
+// parse_ffmpeg_output_options returns sorted muxer options of output.
+func parse_ffmpeg_output_options(output *FrameworkOption) ([]string, map[string]string) {
+	options := output.GetStringMapString("options")
+
+	var keys []string
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, options
+}
+
+// parse_ffmpeg_codec returns codec arguments, stream is `v` or `a`.
+func parse_ffmpeg_codec(prefix, stream string, codec *FrameworkOption) ([]string, error) {
+	var args []string
+
+	if codec == nil {
+		return nil, new_invalid_config_error(prefix + ".codec")
+	}
+
+	if val := codec.GetString("name"); val != "" {
+		args = append(args, "-c:"+stream, val)
 	} else {
-		cmd_str = FFMPEG_FRAMEWORK_DEAFULT_BINARY
+		return nil, new_invalid_config_error(prefix + ".codec.name")
 	}
 
-	cmd_str += " -y"
+	if val := codec.GetString("bit_rate"); val != "" {
+		args = append(args, "-b:"+stream, val)
+	}
 
-	// INPUTS
-	inputs := f.opt.Sub("inputs")
-	if inputs == nil {
-		return "", new_invalid_config_error("inputs")
+	if val := codec.GetStringSlice("extra"); val != nil {
+		extra, err := split_arguments_slice(val)
+		if err != nil {
+			return nil, new_invalid_config_error(prefix + ".codec.extra")
+		}
+		args = append(args, extra...)
 	}
 
-	for _, k := range inputs.NextKeys() {
-		input := inputs.Sub(k)
+	return args, nil
+}
 
-		if val := input.GetString("format"); val != "" {
-			cmd_str += " -f " + val
-		} else {
-			return "", new_invalid_config_error(fmt.Sprintf("inputs.%v.format", k))
+func parse_ffmpeg_audio(prefix string, audio *FrameworkOption) ([]string, error) {
+	if audio == nil || audio.GetBool("disable") {
+		// disable audio
+		return []string{"-an"}, nil
+	}
+
+	return parse_ffmpeg_codec(prefix, "a", audio.Sub("codec"))
+}
+
+func parse_ffmpeg_custom_output(k string, output *FrameworkOption) ([]string, error) {
+	var args []string
+	prefix := fmt.Sprintf("outputs.%v", k)
+
+	if video := output.Sub("video"); video != nil {
+		if video.IsSet("codec") {
+			codec_args, err := parse_ffmpeg_codec(prefix+".video", "v", video.Sub("codec"))
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, codec_args...)
 		}
 
-		if val := input.GetString("file"); val != "" {
-			cmd_str += " -i \"" + val + "\""
-		} else {
-			return "", new_invalid_config_error(fmt.Sprintf("inputs.%v.file", k))
+		var filters []string
+		if val := video.GetString("frame_rate"); val != "" {
+			filters = append(filters, "fps="+val)
 		}
 
-		if val := input.GetString("frame_size"); val != "" {
-			cmd_str += " -s " + val
+		if val := video.GetString("frame_size"); val != "" {
+			filters = append(filters, "scale="+strings.Replace(val, "x", ":", 1))
 		}
 
-		if val := input.GetString("frame_rate"); val != "" {
-			cmd_str += " -r " + val
+		if len(filters) > 0 {
+			args = append(args, "-vf", strings.Join(filters, ","))
 		}
+
+		if val := video.GetString("pixel_format"); val != "" {
+			args = append(args, "-pix_fmt", val)
+		}
+
+		if val := video.GetInt("quality"); val > 0 {
+			// map quality 1-100 to qscale 31-2.
+			args = append(args, "-q:v", fmt.Sprintf("%v", 2+(100-val)*29/100))
+		}
+
+		if val := video.GetInt("frames"); val > 0 {
+			args = append(args, "-frames:v", fmt.Sprintf("%v", val))
+		}
+	} else {
+		args = append(args, "-vn")
 	}
 
-	// VIDEO
-	video := f.opt.Sub("video")
-	if video == nil {
-		return "", new_invalid_config_error("video")
+	audio_args, err := parse_ffmpeg_audio(prefix+".audio", output.Sub("audio"))
+	if err != nil {
+		return nil, err
 	}
+	args = append(args, audio_args...)
 
-	video_codec := video.Sub("codec")
-	if video_codec == nil {
-		return "", new_invalid_config_error("video.codec")
+	keys, options := parse_ffmpeg_output_options(output)
+	for _, key := range keys {
+		args = append(args, "-"+key, options[key])
 	}
 
-	if val := video_codec.GetString("name"); val != "" {
-		cmd_str += " -c:v " + val
+	args = append(args, "-f", output.GetString("format"), output.GetString("file"))
+
+	return args, nil
+}
+
+// ParseFFmpegArguments returns ffmpeg argument vector for option,
+// the first element is ffmpeg binary.
+func ParseFFmpegArguments(opt *FrameworkOption) ([]string, error) {
+	var args []string
+
+	if val := opt.GetString("binary"); val != "" {
+		args = append(args, val)
 	} else {
-		return "", new_invalid_config_error("video.codec.name")
+		args = append(args, FFMPEG_FRAMEWORK_DEAFULT_BINARY)
 	}
 
-	if val := video_codec.GetString("bit_rate"); val != "" {
-		cmd_str += " -b:v " + val
-	}
+	args = append(args, "-y")
 
-	if val := video_codec.GetStringSlice("extra"); val != nil {
-		cmd_str += " " + strings.Join(val, " ")
+	// INPUTS
+	inputs := opt.Sub("inputs")
+	if inputs == nil {
+		return nil, new_invalid_config_error("inputs")
 	}
 
-	// AUDIO
-	audio := f.opt.Sub("audio")
-	if audio == nil {
-		// disable audio
-		cmd_str += " -an"
-	} else {
-		audio_codec := audio.Sub("codec")
-		if audio_codec == nil {
-			return "", new_invalid_config_error("audio.codec")
-		}
+	input_keys := inputs.NextKeys()
+	for _, k := range input_keys {
+		input := inputs.Sub(k)
 
-		if val := audio_codec.GetString("name"); val != "" {
-			cmd_str += " -c:a " + val
+		if val := input.GetString("format"); val != "" {
+			args = append(args, "-f", val)
 		} else {
-			return "", new_invalid_config_error("audio.codec.name")
+			return nil, new_invalid_config_error(fmt.Sprintf("inputs.%v.format", k))
+		}
+
+		// input options apply to the next `-i` only.
+		if val := input.GetString("frame_size"); val != "" {
+			args = append(args, "-s", val)
 		}
 
-		if val := audio_codec.GetStringSlice("extra"); val != nil {
-			cmd_str += strings.Join(val, " ")
+		if val := input.GetString("frame_rate"); val != "" {
+			args = append(args, "-r", val)
+		}
+
+		if val := input.GetString("file"); val != "" {
+			args = append(args, "-i", val)
+		} else {
+			return nil, new_invalid_config_error(fmt.Sprintf("inputs.%v.file", k))
 		}
 	}
 
 	// OUTPUTS
-	outputs := f.opt.Sub("outputs")
+	outputs := opt.Sub("outputs")
 	if outputs == nil {
-		return "", new_invalid_config_error("outputs")
+		return nil, new_invalid_config_error("outputs")
 	}
 
+	var defaults []*FrameworkOption
+	var custom_args []string
 	for _, k := range outputs.NextKeys() {
 		output := outputs.Sub(k)
 
-		if val := output.GetString("format"); val != "" {
-			cmd_str += " -f " + val
-		} else {
-			return "", new_invalid_config_error(fmt.Sprintf("outputs.%v.format", k))
+		if output.GetString("format") == "" {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.format", k))
 		}
 
-		if val := output.GetString("file"); val != "" {
-			cmd_str += " \"" + val + "\""
-		} else {
-			return "", new_invalid_config_error(fmt.Sprintf("outputs.%v.file", k))
+		if output.GetString("file") == "" {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.file", k))
 		}
-	}
-
-	return cmd_str, nil
-}
 
-type FFmpegFramework struct {
-	opt *FrameworkOption
-
-	logger log.FieldLogger
-	op_mtx *sync.Mutex
-	cmd    *exec.Cmd
-	cfn    context.CancelFunc
-	errchs []chan<- error
-}
+		if is_custom_output(output) {
+			output_args, err := parse_ffmpeg_custom_output(k, output)
+			if err != nil {
+				return nil, err
+			}
+			custom_args = append(custom_args, output_args...)
+			continue
+		}
 
-// NOTE: should be call after `op_mtx` locked!
-func (f *FFmpegFramework) send_to_waiting_channels(err error) {
-	for _, errch := range f.errchs {
-		errch <- err
+		defaults = append(defaults, output)
 	}
-}
-
-func (f *FFmpegFramework) Start() error {
-	f.op_mtx.Lock()
-	defer f.op_mtx.Unlock()
 
-	if f.cfn != nil {
-		f.logger.WithError(ErrNotStartable).Debugf("ffmpeg not startable")
-		return ErrNotStartable
+	if len(defaults) == 0 && len(custom_args) == 0 {
+		return nil, new_invalid_config_error("outputs")
 	}
 
-	cmd_str, err := f.parse_ffmpeg_command()
-	if err != nil {
-		f.logger.WithError(err).Debugf("failed to parse ffmpeg command")
-		return err
-	}
+	var audio_enabled bool
+	if len(defaults) > 0 {
+		// VIDEO
+		video := opt.Sub("video")
+		if video == nil {
+			return nil, new_invalid_config_error("video")
+		}
 
-	ctx := context.TODO()
-	ctx, f.cfn = context.WithCancel(ctx)
-	f.cmd = exec.CommandContext(ctx, "/bin/bash", "-c", cmd_str)
+		video_args, err := parse_ffmpeg_codec("video", "v", video.Sub("codec"))
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, video_args...)
 
-	err = f.cmd.Start()
-	if err != nil {
-		return err
+		// AUDIO
+		audio_args, err := parse_ffmpeg_audio("audio", opt.Sub("audio"))
+		if err != nil {
+			return nil, err
+		}
+		if len(defaults) == 1 && ffmpeg_video_only_formats[defaults[0].GetString("format")] {
+			audio_args = []string{"-an"}
+		}
+		args = append(args, audio_args...)
+		audio_enabled = audio_args[0] != "-an"
 	}
 
-	go func() {
-		err := f.cmd.Wait()
-
-		f.op_mtx.Lock()
-		defer f.op_mtx.Unlock()
+	switch len(defaults) {
+	case 0:
+	case 1:
+		keys, options := parse_ffmpeg_output_options(defaults[0])
+		for _, key := range keys {
+			args = append(args, "-"+key, options[key])
+		}
+		args = append(args, "-f", defaults[0].GetString("format"), defaults[0].GetString("file"))
+	default:
+		// encode once, mux to all outputs by tee muxer,
+		// failed output will not interrupt others.
+		if opt.GetString("video.codec.name") != "copy" {
+			args = append(args, "-flags", "+global_header")
+		}
 
-		if err != nil {
-			f.logger.WithError(err).Debugf("failed to wait command exit")
+		// tee muxer has no default codecs, streams are not selected automatically.
+		for i := range input_keys {
+			args = append(args, "-map", fmt.Sprintf("%v:v?", i))
+			if audio_enabled {
+				args = append(args, "-map", fmt.Sprintf("%v:a?", i))
+			}
 		}
 
-		f.send_to_waiting_channels(err)
-	}()
+		var slaves []string
+		for _, output := range defaults {
+			opts := []string{"f=" + output.GetString("format")}
+			if ffmpeg_video_only_formats[output.GetString("format")] {
+				opts = append(opts, "select=v")
+			}
+			keys, options := parse_ffmpeg_output_options(output)
+			for _, key := range keys {
+				opts = append(opts, key+"="+ffmpeg_tee_option_escaper.Replace(options[key]))
+			}
+			opts = append(opts, "onfail=ignore")
+			slaves = append(slaves, fmt.Sprintf("[%v]%v", strings.Join(opts, ":"), escape_ffmpeg_tee_slave(output.GetString("file"))))
+		}
+		args = append(args, "-f", "tee", strings.Join(slaves, "|"))
+	}
 
-	f.logger.WithField("cmd", cmd_str).Debugf("ffmpeg start")
+	args = append(args, custom_args...)
 
-	return nil
+	return args, nil
 }
 
-func (f *FFmpegFramework) Stop() error {
-	f.op_mtx.Lock()
-	defer f.op_mtx.Unlock()
-
-	if f.cfn == nil {
-		f.logger.WithError(ErrNotStoppable).Debugf("ffmpeg not stopable")
-		return ErrNotStoppable
-	}
-
-	f.cfn()
-	f.send_to_waiting_channels(nil)
-
-	f.logger.Debugf("ffmpeg stop")
+type FFmpegFramework struct {
+	*framework_process
 
-	return nil
+	opt *FrameworkOption
 }
 
-func (f *FFmpegFramework) Wait() <-chan error {
-	f.op_mtx.Lock()
-	defer f.op_mtx.Unlock()
-
-	errch := make(chan error)
-	f.errchs = append(f.errchs, errch)
+func (f *FFmpegFramework) Start() error {
+	args, err := ParseFFmpegArguments(f.opt)
+	if err != nil {
+		f.logger.WithError(err).Debugf("failed to parse ffmpeg arguments")
+		return err
+	}
 
-	return errch
+	return f.start(args)
 }
 
 func NewFFmpegFramework(opt *FrameworkOption, args ...interface{}) (Framework, error) {
@@ -252,10 +371,10 @@ func NewFFmpegFramework(opt *FrameworkOption, args ...interface{}) (Framework, e
 	})(args...)
 
 	frm := &FFmpegFramework{
-		opt:    opt,
-		op_mtx: new(sync.Mutex),
-		logger: logger,
+		framework_process: new_framework_process("ffmpeg", opt, logger),
+		opt:               opt,
 	}
+	frm.parse_progress = parse_ffmpeg_progress_line
 
 	frm.logger.Debugf("new ffmpeg framework")
 
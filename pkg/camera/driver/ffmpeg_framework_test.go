@@ -0,0 +1,130 @@
+package camera_driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func new_test_framework_option(t *testing.T, yaml string) *FrameworkOption {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatal(err)
+	}
+
+	return &FrameworkOption{v}
+}
+
+func TestParseFFmpegArgumentsInputs(t *testing.T) {
+	opt := new_test_framework_option(t, `
+inputs:
+  0:
+    format: v4l2
+    file: /dev/video0
+    frame_size: 1280x720
+    frame_rate: 30
+  1:
+    format: alsa
+    file: hw:1
+outputs:
+  0:
+    format: flv
+    file: rtmp://localhost/live/test
+video:
+  codec:
+    name: libx264
+audio:
+  codec:
+    name: aac
+`)
+
+	args, err := ParseFFmpegArguments(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"ffmpeg", "-y",
+		"-f", "v4l2", "-s", "1280x720", "-r", "30", "-i", "/dev/video0",
+		"-f", "alsa", "-i", "hw:1",
+		"-c:v", "libx264",
+		"-c:a", "aac",
+		"-f", "flv", "rtmp://localhost/live/test",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("arguments:\n%q\nwant:\n%q", args, want)
+	}
+}
+
+func TestParseFFmpegArgumentsTee(t *testing.T) {
+	opt := new_test_framework_option(t, `
+inputs:
+  0:
+    format: v4l2
+    file: /dev/video0
+  1:
+    format: alsa
+    file: hw:1
+outputs:
+  0:
+    format: flv
+    file: rtmp://localhost/live/test
+  1:
+    format: mpegts
+    file: /tmp/test.ts
+video:
+  codec:
+    name: libx264
+audio:
+  codec:
+    name: aac
+`)
+
+	args, err := ParseFFmpegArguments(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"ffmpeg", "-y",
+		"-f", "v4l2", "-i", "/dev/video0",
+		"-f", "alsa", "-i", "hw:1",
+		"-c:v", "libx264",
+		"-c:a", "aac",
+		"-flags", "+global_header",
+		"-map", "0:v?", "-map", "0:a?",
+		"-map", "1:v?", "-map", "1:a?",
+		"-f", "tee", "[f=flv:onfail=ignore]rtmp://localhost/live/test|[f=mpegts:onfail=ignore]/tmp/test.ts",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("arguments:\n%q\nwant:\n%q", args, want)
+	}
+}
+
+func TestParseFFmpegArgumentsInvalidInput(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		key   string
+	}{
+		{"file: /dev/video0", "inputs.0.format"},
+		{"format: v4l2", "inputs.0.file"},
+	} {
+		opt := new_test_framework_option(t, `
+inputs:
+  0:
+    `+c.input+`
+outputs:
+  0:
+    format: flv
+    file: rtmp://localhost/live/test
+`)
+
+		_, err := ParseFFmpegArguments(opt)
+		if err == nil || err.Error() != new_invalid_config_error(c.key).Error() {
+			t.Errorf("input %q: error %v, want invalid %v", c.input, err, c.key)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package camera_driver
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ffmpeg_progress_field_regexp = regexp.MustCompile(`([a-z_]+)=\s*(\S+)`)
+
+func is_ffmpeg_progress_line(line string) bool {
+	return strings.HasPrefix(line, "frame=") || (strings.Contains(line, "size=") && strings.Contains(line, "speed="))
+}
+
+// parse_ffmpeg_progress_line parses progress line of ffmpeg stderr,
+// like `frame=  123 fps= 30 q=28.0 size=    1024kB time=00:00:04.10 bitrate=2045.6kbits/s dup=0 drop=1 speed=1.01x`.
+// return nil if line is not a progress line.
+func parse_ffmpeg_progress_line(line string) *FrameworkStats {
+	if !is_ffmpeg_progress_line(line) {
+		return nil
+	}
+
+	st := &FrameworkStats{UpdateAt: time.Now()}
+
+	for _, m := range ffmpeg_progress_field_regexp.FindAllStringSubmatch(line, -1) {
+		key, val := m[1], m[2]
+		switch key {
+		case "frame":
+			st.Frame, _ = strconv.ParseInt(val, 10, 64)
+		case "fps":
+			st.Fps, _ = strconv.ParseFloat(val, 64)
+		case "bitrate":
+			st.BitRate, _ = strconv.ParseFloat(strings.TrimSuffix(val, "kbits/s"), 64)
+		case "size":
+			st.Size = val
+		case "time":
+			st.Time = val
+		case "dup":
+			st.DupFrames, _ = strconv.ParseInt(val, 10, 64)
+		case "drop":
+			st.DropFrames, _ = strconv.ParseInt(val, 10, 64)
+		case "speed":
+			st.Speed, _ = strconv.ParseFloat(strings.TrimSuffix(val, "x"), 64)
+		}
+	}
+
+	return st
+}
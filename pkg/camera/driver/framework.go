@@ -3,6 +3,7 @@ package camera_driver
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -39,14 +40,55 @@ func (o *FrameworkOption) NextKeys() []string {
 	for x := range m {
 		a = append(a, x)
 	}
+	sort_labels(a)
 
 	return a
 }
 
+const (
+	FRAMEWORK_DEFAULT_LOG_LINES    = 64
+	FRAMEWORK_DEFAULT_ERROR_LINES  = 8
+	FRAMEWORK_DEFAULT_STOP_TIMEOUT = 5 * time.Second
+)
+
+type FrameworkStats struct {
+	Frame      int64
+	Fps        float64
+	BitRate    float64 // kbits/s
+	Size       string
+	Time       string
+	DupFrames  int64
+	DropFrames int64
+	Speed      float64
+	UpdateAt   time.Time
+}
+
+type FrameworkStatus struct {
+	Pid     int
+	StartAt time.Time
+	Stats   *FrameworkStats
+	Logs    []string
+}
+
+// FrameworkExitError wraps framework exit error with last log lines.
+type FrameworkExitError struct {
+	Err  error
+	Logs []string
+}
+
+func (e *FrameworkExitError) Error() string {
+	if len(e.Logs) == 0 {
+		return e.Err.Error()
+	}
+
+	return e.Err.Error() + ": " + strings.Join(e.Logs, "; ")
+}
+
 type Framework interface {
 	Start() error
 	Stop() error
 	Wait() <-chan error
+	Status() *FrameworkStatus
 }
 
 type FrameworkFactory func(opt *FrameworkOption, args ...interface{}) (Framework, error)
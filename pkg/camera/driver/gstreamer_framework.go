@@ -0,0 +1,638 @@
+package camera_driver
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	opt_helper "github.com/nayotta/metathings/pkg/common/option"
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+ * Framework: gstreamer
+ * Options:
+ *   driver:
+ *   ...
+ *     framework:
+ *       name: gstreamer
+ *       [ binary: gst-launch-1.0 ]  // gst-launch binary file path.
+ *       [ log_lines: <count> ]  // recent output lines kept in memory, default 64.
+ *       [ error_lines: <count> ]  // last output lines attached to exit error, default 8.
+ *       [ stop_timeout: <duration> ]  // wait exit after interrupt before kill, default `5s`, 0 kills at once.
+ *       inputs:
+ *         0:
+ *           format: <format>  // input format, `v4l2`, `rtsp`, `file` or `test` for video, `alsa` or `pulse` for audio.
+ *           file: <path>  // file path, like `/dev/video0`, set from camera driver.
+ *           [ frame_size: <width>x<height> ]  // frame size, like `640x480`.
+ *           [ frame_rate: <rate> ]  // frame rate, like `30`.
+ *           [ caps: <caps> ]  // source caps, like `image/jpeg,width=1280,height=720` for mjpeg usb camera.
+ *           [ extra: [ ... ] ]  // list of raw pipeline fragments applied on decoded video, like `videoflip method=clockwise`.
+ *       outputs:
+ *         0:
+ *           format: <format>  // output file format, `flv`, `mp4`, `matroska`, `mpegts` or `rtp` of video only,
+ *                             // `image2`, `mpjpeg` or `rawvideo` for custom outputs.
+ *           [ file: <path> ]  // file path, like `rtmp://rtmp-server:port/path`.
+ *           [ options: ]  // muxer options, like `start_number: 1` for `image2`.
+ *           [ video: ]  // custom output settings, same as ffmpeg framework,
+ *                       // codec `mjpeg` and `png` are mapped to `jpegenc` and `pngenc`.
+ *       video:
+ *         codec:
+ *           name: <codec>  // encoder element, like `x264enc`, `omxh264enc`, `nvv4l2h264enc`, `vpuenc_h264`,
+ *                          // or `copy` for h264 rtsp input.
+ *           [ bit_rate: <rate> ]  // video bitrate, like `2000k`.
+ *           [ extra: [ ... ] ]  // list of encoder properties, like `tune=zerolatency`.
+ *       audio:
+ *         [ disable: <bool> ]  // disable audio, same as no audio section.
+ *         codec:
+ *           name: <codec>  // audio encoder element, like `voaacenc`, audio comes from `alsa` or `pulse` inputs.
+ *           [ extra: [ ... ] ]  // list of encoder properties.
+ *   ...
+ *
+ */
+
+const (
+	GSTREAMER_FRAMEWORK_DEFAULT_BINARY = `gst-launch-1.0`
+)
+
+type gstreamer_bit_rate_property struct {
+	name string
+	unit int64
+}
+
+// bitrate property of encoders, unit is bits of property value.
+var gstreamer_encoder_bit_rate_properties = map[string]gstreamer_bit_rate_property{
+	"x264enc":       {"bitrate", 1000},
+	"vaapih264enc":  {"bitrate", 1000},
+	"vpuenc_h264":   {"bitrate", 1000},
+	"omxh264enc":    {"target-bitrate", 1},
+	"nvv4l2h264enc": {"bitrate", 1},
+	"openh264enc":   {"bitrate", 1},
+}
+
+var gstreamer_muxers = map[string][]string{
+	"flv":      {"flvmux", "streamable=true"},
+	"mp4":      {"mp4mux"},
+	"matroska": {"matroskamux"},
+	"mpegts":   {"mpegtsmux"},
+	// video only, payloader for webrtc relay.
+	"rtp": {"rtph264pay", "config-interval=-1", "pt=96", "mtu=1200"},
+}
+
+// parse_bit_rate parses bitrate like `2000k` or `2M` to bits.
+func parse_bit_rate(s string) (int64, error) {
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		unit = 1000
+	case strings.HasSuffix(s, "m"), strings.HasSuffix(s, "M"):
+		unit = 1000000
+	}
+
+	if unit != 1 {
+		s = s[:len(s)-1]
+	}
+
+	val, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return val * unit, nil
+}
+
+type gstreamer_pipeline struct {
+	args []string
+}
+
+// chain appends elements linked by `!`, each element is a list of tokens.
+func (p *gstreamer_pipeline) chain(elements ...[]string) {
+	for i, elem := range elements {
+		if i > 0 {
+			p.args = append(p.args, "!")
+		}
+		p.args = append(p.args, elem...)
+	}
+}
+
+// link appends elements linked to last element of pipeline.
+func (p *gstreamer_pipeline) link(elements ...[]string) {
+	for _, elem := range elements {
+		p.args = append(p.args, "!")
+		p.args = append(p.args, elem...)
+	}
+}
+
+func gstreamer_element(name string, props ...string) []string {
+	return append([]string{name}, props...)
+}
+
+func gstreamer_raw_video_caps(input *FrameworkOption) string {
+	var caps []string
+
+	if val := input.GetString("frame_size"); val != "" {
+		ss := strings.SplitN(val, "x", 2)
+		if len(ss) == 2 {
+			caps = append(caps, "width="+ss[0], "height="+ss[1])
+		}
+	}
+
+	if val := input.GetString("frame_rate"); val != "" {
+		caps = append(caps, "framerate="+val+"/1")
+	}
+
+	if len(caps) == 0 {
+		return ""
+	}
+
+	return "video/x-raw," + strings.Join(caps, ",")
+}
+
+func gstreamer_sink(file string) ([]string, error) {
+	u, err := url.Parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "rtmp", "rtmps":
+		return gstreamer_element("rtmpsink", "location="+file), nil
+	case "udp", "rtp":
+		return gstreamer_element("udpsink", "host="+u.Hostname(), "port="+u.Port()), nil
+	default:
+		return gstreamer_element("filesink", "location="+file), nil
+	}
+}
+
+// parse_gstreamer_video_input appends video source of input into pipeline,
+// returns the pipeline outputs raw video or encoded h264.
+func parse_gstreamer_video_input(p *gstreamer_pipeline, k string, input *FrameworkOption, copy_video bool) (bool, error) {
+	file := input.GetString("file")
+	if file == "" {
+		return false, new_invalid_config_error(fmt.Sprintf("inputs.%v.file", k))
+	}
+
+	var elems [][]string
+	var need_scale bool
+
+	switch input.GetString("format") {
+	case "v4l2":
+		elems = append(elems, gstreamer_element("v4l2src", "device="+file))
+		if val := input.GetString("caps"); val != "" {
+			elems = append(elems, []string{val})
+			if strings.HasPrefix(val, "image/jpeg") {
+				elems = append(elems, gstreamer_element("jpegdec"))
+			}
+		} else if caps := gstreamer_raw_video_caps(input); caps != "" {
+			elems = append(elems, []string{caps})
+		}
+	case "rtsp":
+		elems = append(elems,
+			gstreamer_element("rtspsrc", "location="+file, "latency=0"),
+			gstreamer_element("rtph264depay"),
+			gstreamer_element("h264parse"),
+		)
+		if copy_video {
+			p.chain(elems...)
+			return false, nil
+		}
+		elems = append(elems, gstreamer_element("avdec_h264"))
+		need_scale = true
+	case "file":
+		elems = append(elems,
+			gstreamer_element("filesrc", "location="+file),
+			gstreamer_element("decodebin"),
+		)
+		need_scale = true
+	case "test":
+		elems = append(elems, gstreamer_element("videotestsrc", "is-live=true"))
+		if caps := gstreamer_raw_video_caps(input); caps != "" {
+			elems = append(elems, []string{caps})
+		}
+	default:
+		return false, new_invalid_config_error(fmt.Sprintf("inputs.%v.format", k))
+	}
+
+	if copy_video {
+		return false, new_invalid_config_error("video.codec.name")
+	}
+
+	elems = append(elems, gstreamer_element("videoconvert"))
+
+	if need_scale {
+		if caps := gstreamer_raw_video_caps(input); caps != "" {
+			elems = append(elems,
+				gstreamer_element("videoscale"),
+				gstreamer_element("videorate"),
+				[]string{caps},
+			)
+		}
+	}
+
+	for _, fragment := range input.GetStringSlice("extra") {
+		tokens, err := split_arguments(fragment)
+		if err != nil {
+			return false, new_invalid_config_error(fmt.Sprintf("inputs.%v.extra", k))
+		}
+		elems = append(elems, tokens)
+	}
+
+	p.chain(elems...)
+
+	return true, nil
+}
+
+func parse_gstreamer_audio_input(p *gstreamer_pipeline, k string, input *FrameworkOption) error {
+	file := input.GetString("file")
+	if file == "" {
+		return new_invalid_config_error(fmt.Sprintf("inputs.%v.file", k))
+	}
+
+	var src []string
+	switch input.GetString("format") {
+	case "alsa":
+		src = gstreamer_element("alsasrc", "device="+file)
+	case "pulse":
+		src = gstreamer_element("pulsesrc", "device="+file)
+	default:
+		return new_invalid_config_error(fmt.Sprintf("inputs.%v.format", k))
+	}
+
+	p.chain(src, gstreamer_element("audioconvert"), gstreamer_element("audioresample"))
+
+	return nil
+}
+
+func is_gstreamer_audio_input(input *FrameworkOption) bool {
+	switch input.GetString("format") {
+	case "alsa", "pulse":
+		return true
+	default:
+		return false
+	}
+}
+
+// gstreamer_video_encoders maps ffmpeg style codec names of custom outputs to elements.
+// empty element means raw video, no encoder.
+var gstreamer_video_encoders = map[string]string{
+	"mjpeg":    "jpegenc",
+	"png":      "pngenc",
+	"rawvideo": "",
+}
+
+var gstreamer_pixel_formats = map[string]string{
+	"gray":    "GRAY8",
+	"yuv420p": "I420",
+	"rgb24":   "RGB",
+}
+
+func gstreamer_frame_rate(rate string) string {
+	if strings.Contains(rate, "/") {
+		return rate
+	}
+
+	return rate + "/1"
+}
+
+func parse_gstreamer_custom_sink(k string, output *FrameworkOption) ([]string, error) {
+	file := output.GetString("file")
+	options := output.GetStringMapString("options")
+
+	switch output.GetString("format") {
+	case "rawvideo":
+		return gstreamer_element("filesink", "location="+file), nil
+	case "mpjpeg":
+		return gstreamer_element("filesink", "location="+file), nil
+	case "image2":
+		if strings.Contains(file, "%") {
+			sink := gstreamer_element("multifilesink", "location="+file)
+			if val, ok := options["start_number"]; ok {
+				sink = append(sink, "index="+val)
+			}
+			return sink, nil
+		}
+		// location without index is overwritten by each frame, like `-update 1` of ffmpeg.
+		return gstreamer_element("multifilesink", "location="+file), nil
+	default:
+		return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.format", k))
+	}
+}
+
+// parse_gstreamer_custom_output appends branch of custom output into pipeline,
+// branch starts from raw video tee `rt`, or decoded from encoded video tee `vt`.
+func parse_gstreamer_custom_output(p *gstreamer_pipeline, k string, output *FrameworkOption, raw bool) error {
+	video := output.Sub("video")
+	if video == nil {
+		return new_invalid_config_error(fmt.Sprintf("outputs.%v.video", k))
+	}
+
+	if raw {
+		p.chain([]string{"rt."}, gstreamer_element("queue"))
+	} else {
+		p.chain([]string{"vt."}, gstreamer_element("queue"), gstreamer_element("avdec_h264"), gstreamer_element("videoconvert"))
+	}
+
+	var caps []string
+	if val := video.GetString("frame_rate"); val != "" {
+		p.link(gstreamer_element("videorate"))
+		caps = append(caps, "framerate="+gstreamer_frame_rate(val))
+	}
+
+	if val := video.GetString("frame_size"); val != "" {
+		ss := strings.SplitN(val, "x", 2)
+		if len(ss) != 2 {
+			return new_invalid_config_error(fmt.Sprintf("outputs.%v.video.frame_size", k))
+		}
+		p.link(gstreamer_element("videoscale"))
+		caps = append(caps, "width="+ss[0], "height="+ss[1])
+	}
+
+	if val := video.GetString("pixel_format"); val != "" {
+		format, ok := gstreamer_pixel_formats[val]
+		if !ok {
+			return new_invalid_config_error(fmt.Sprintf("outputs.%v.video.pixel_format", k))
+		}
+		p.link(gstreamer_element("videoconvert"))
+		caps = append(caps, "format="+format)
+	}
+
+	if len(caps) > 0 {
+		p.link([]string{"video/x-raw," + strings.Join(caps, ",")})
+	}
+
+	if codec := video.Sub("codec"); codec != nil {
+		name := codec.GetString("name")
+		if name == "" {
+			return new_invalid_config_error(fmt.Sprintf("outputs.%v.video.codec.name", k))
+		}
+
+		if val, ok := gstreamer_video_encoders[name]; ok {
+			name = val
+		}
+
+		if name != "" {
+			enc := gstreamer_element(name)
+			if val := video.GetInt("quality"); val > 0 && name == "jpegenc" {
+				enc = append(enc, fmt.Sprintf("quality=%v", val))
+			}
+			p.link(enc)
+		}
+	}
+
+	sink, err := parse_gstreamer_custom_sink(k, output)
+	if err != nil {
+		return err
+	}
+
+	if output.GetString("format") == "mpjpeg" {
+		p.link(gstreamer_element("multipartmux", "boundary="+MJPEG_FRAMEWORK_BOUNDARY))
+	}
+	p.link(sink)
+
+	return nil
+}
+
+// ParseGStreamerArguments returns gst-launch argument vector for option,
+// the first element is gst-launch binary.
+func ParseGStreamerArguments(opt *FrameworkOption) ([]string, error) {
+	var args []string
+
+	if val := opt.GetString("binary"); val != "" {
+		args = append(args, val)
+	} else {
+		args = append(args, GSTREAMER_FRAMEWORK_DEFAULT_BINARY)
+	}
+
+	// send eos on interrupt to finish muxers.
+	args = append(args, "-e")
+
+	p := &gstreamer_pipeline{args: args}
+
+	// OUTPUTS
+	outputs := opt.Sub("outputs")
+	if outputs == nil {
+		return nil, new_invalid_config_error("outputs")
+	}
+
+	var default_outputs, custom_outputs []string
+	for _, k := range outputs.NextKeys() {
+		output := outputs.Sub(k)
+
+		if output.GetString("format") == "" {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.format", k))
+		}
+
+		if output.GetString("file") == "" {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.file", k))
+		}
+
+		if is_custom_output(output) {
+			custom_outputs = append(custom_outputs, k)
+		} else {
+			default_outputs = append(default_outputs, k)
+		}
+	}
+
+	if len(default_outputs) == 0 && len(custom_outputs) == 0 {
+		return nil, new_invalid_config_error("outputs")
+	}
+
+	// VIDEO
+	var copy_video bool
+	var video_enc []string
+	if len(default_outputs) > 0 {
+		video := opt.Sub("video")
+		if video == nil {
+			return nil, new_invalid_config_error("video")
+		}
+
+		video_codec := video.Sub("codec")
+		if video_codec == nil {
+			return nil, new_invalid_config_error("video.codec")
+		}
+
+		video_codec_name := video_codec.GetString("name")
+		if video_codec_name == "" {
+			return nil, new_invalid_config_error("video.codec.name")
+		}
+		copy_video = video_codec_name == "copy"
+
+		if !copy_video {
+			video_enc = gstreamer_element(video_codec_name)
+		}
+
+		if val := video_codec.GetString("bit_rate"); val != "" && !copy_video {
+			prop, ok := gstreamer_encoder_bit_rate_properties[video_codec_name]
+			if !ok {
+				return nil, new_invalid_config_error("video.codec.bit_rate")
+			}
+
+			bit_rate, err := parse_bit_rate(val)
+			if err != nil {
+				return nil, new_invalid_config_error("video.codec.bit_rate")
+			}
+
+			video_enc = append(video_enc, fmt.Sprintf("%v=%v", prop.name, bit_rate/prop.unit))
+		}
+
+		if val := video_codec.GetStringSlice("extra"); val != nil && !copy_video {
+			extra, err := split_arguments_slice(val)
+			if err != nil {
+				return nil, new_invalid_config_error("video.codec.extra")
+			}
+			video_enc = append(video_enc, extra...)
+		}
+	}
+
+	// AUDIO
+	var audio_enc []string
+	audio := opt.Sub("audio")
+	if len(default_outputs) > 0 && audio != nil && !audio.GetBool("disable") {
+		audio_codec := audio.Sub("codec")
+		if audio_codec == nil {
+			return nil, new_invalid_config_error("audio.codec")
+		}
+
+		if val := audio_codec.GetString("name"); val != "" && val != "copy" {
+			audio_enc = gstreamer_element(val)
+		} else {
+			return nil, new_invalid_config_error("audio.codec.name")
+		}
+
+		if val := audio_codec.GetStringSlice("extra"); val != nil {
+			extra, err := split_arguments_slice(val)
+			if err != nil {
+				return nil, new_invalid_config_error("audio.codec.extra")
+			}
+			audio_enc = append(audio_enc, extra...)
+		}
+	}
+
+	// INPUTS
+	inputs := opt.Sub("inputs")
+	if inputs == nil {
+		return nil, new_invalid_config_error("inputs")
+	}
+
+	var has_video, has_audio, raw bool
+	for _, k := range inputs.NextKeys() {
+		input := inputs.Sub(k)
+
+		if is_gstreamer_audio_input(input) {
+			if audio_enc == nil || has_audio {
+				continue
+			}
+
+			if err := parse_gstreamer_audio_input(p, k, input); err != nil {
+				return nil, err
+			}
+			p.link(audio_enc, gstreamer_element("queue"), gstreamer_element("tee", "name=at"))
+			has_audio = true
+			continue
+		}
+
+		if has_video {
+			continue
+		}
+
+		var err error
+		raw, err = parse_gstreamer_video_input(p, k, input, copy_video)
+		if err != nil {
+			return nil, err
+		}
+
+		if raw {
+			p.link(gstreamer_element("tee", "name=rt"))
+		} else {
+			p.link(gstreamer_element("tee", "name=vt"))
+		}
+		has_video = true
+	}
+
+	if !has_video {
+		return nil, new_invalid_config_error("inputs")
+	}
+
+	if raw && len(default_outputs) > 0 {
+		p.chain([]string{"rt."}, gstreamer_element("queue"), video_enc, gstreamer_element("h264parse"), gstreamer_element("tee", "name=vt"))
+	}
+
+	for i, k := range default_outputs {
+		output := outputs.Sub(k)
+
+		mux, ok := gstreamer_muxers[output.GetString("format")]
+		if !ok {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.format", k))
+		}
+
+		sink, err := gstreamer_sink(output.GetString("file"))
+		if err != nil {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.file", k))
+		}
+
+		mux_name := fmt.Sprintf("mux%d", i)
+		p.chain(
+			[]string{"vt."},
+			gstreamer_element("queue"),
+			append(gstreamer_element(mux[0], "name="+mux_name), mux[1:]...),
+			sink,
+		)
+
+		if has_audio && output.GetString("format") != "rtp" {
+			p.chain([]string{"at."}, gstreamer_element("queue"), []string{mux_name + "."})
+		}
+	}
+
+	for _, k := range custom_outputs {
+		if err := parse_gstreamer_custom_output(p, k, outputs.Sub(k), raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.args, nil
+}
+
+type GStreamerFramework struct {
+	*framework_process
+
+	opt *FrameworkOption
+}
+
+func (f *GStreamerFramework) Start() error {
+	args, err := ParseGStreamerArguments(f.opt)
+	if err != nil {
+		f.logger.WithError(err).Debugf("failed to parse gstreamer arguments")
+		return err
+	}
+
+	return f.start(args)
+}
+
+func NewGStreamerFramework(opt *FrameworkOption, args ...interface{}) (Framework, error) {
+	var logger log.FieldLogger
+
+	opt_helper.Setopt(opt_helper.SetoptConds{
+		"logger": opt_helper.ToLogger(&logger),
+	})(args...)
+
+	frm := &GStreamerFramework{
+		framework_process: new_framework_process("gstreamer", opt, logger),
+		opt:               opt,
+	}
+	// gst-launch reports pipeline errors on stdout.
+	frm.merge_stdout = true
+
+	frm.logger.Debugf("new gstreamer framework")
+
+	return frm, nil
+}
+
+var register_gstreamer_framework_once sync.Once
+
+func init() {
+	register_gstreamer_framework_once.Do(func() {
+		register_framework_factory("gstreamer", NewGStreamerFramework)
+	})
+}
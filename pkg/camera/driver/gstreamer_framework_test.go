@@ -0,0 +1,215 @@
+package camera_driver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const test_gstreamer_pipeline = `
+inputs:
+  0:
+    format: test
+    file: test
+    frame_size: 640x480
+    frame_rate: 30
+outputs:
+  0:
+    format: flv
+    file: rtmp://localhost/live/test
+video:
+  codec:
+    name: x264enc
+    bit_rate: 2000k
+    extra: [ "tune=zerolatency" ]
+`
+
+var test_gstreamer_arguments = []string{
+	"-e",
+	"videotestsrc", "is-live=true", "!", "video/x-raw,width=640,height=480,framerate=30/1", "!", "videoconvert", "!", "tee", "name=rt",
+	"rt.", "!", "queue", "!", "x264enc", "bitrate=2000", "tune=zerolatency", "!", "h264parse", "!", "tee", "name=vt",
+	"vt.", "!", "queue", "!", "flvmux", "name=mux0", "streamable=true", "!", "rtmpsink", "location=rtmp://localhost/live/test",
+}
+
+// new_test_gst_launch puts stub gst-launch on PATH, returns function to restore PATH,
+// stub records arguments and runs, reports pipeline error on stdout and fails,
+// or runs until stopped if `hold` file exists.
+func new_test_gst_launch(t *testing.T, dir string) func() {
+	new_test_binary(t, dir, GSTREAMER_FRAMEWORK_DEFAULT_BINARY, fmt.Sprintf(`
+for arg in "$@"; do echo "$arg"; done > %[1]v/args
+echo run >> %[1]v/runs
+if [ -f %[1]v/hold ]; then
+	exec sleep 10
+fi
+echo "Setting pipeline to PLAYING ..."
+echo "ERROR: from element /GstPipeline:pipeline0/GstRTMPSink:rtmpsink0: Could not open resource for writing."
+exit 1
+`, dir))
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+
+	return func() { os.Setenv("PATH", path) }
+}
+
+func read_test_lines(t *testing.T, file string) []string {
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n")
+}
+
+func TestGStreamerFrameworkExit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer new_test_gst_launch(t, dir)()
+
+	frmwrk, err := NewGStreamerFramework(new_test_framework_option(t, test_gstreamer_pipeline), "logger", new_test_logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = frmwrk.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err = <-frmwrk.Wait():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("gst-launch not exited")
+	}
+
+	if args := read_test_lines(t, filepath.Join(dir, "args")); !reflect.DeepEqual(args, test_gstreamer_arguments) {
+		t.Errorf("arguments:\n%q\nwant:\n%q", args, test_gstreamer_arguments)
+	}
+
+	exit_err, ok := err.(*FrameworkExitError)
+	if !ok {
+		t.Fatalf("error %v, want exit error", err)
+	}
+
+	// pipeline errors on stdout are attached to exit error.
+	if len(exit_err.Logs) == 0 || !strings.Contains(exit_err.Logs[len(exit_err.Logs)-1], "Could not open resource for writing") {
+		t.Errorf("exit error logs %q", exit_err.Logs)
+	}
+}
+
+func TestGStreamerFrameworkStop(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer new_test_gst_launch(t, dir)()
+	if err = ioutil.WriteFile(filepath.Join(dir, "hold"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	frmwrk, err := NewGStreamerFramework(new_test_framework_option(t, test_gstreamer_pipeline), "logger", new_test_logger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = frmwrk.Start(); err != nil {
+		t.Fatal(err)
+	}
+	errch := frmwrk.Wait()
+
+	if err = frmwrk.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err = <-errch:
+		if err != nil {
+			t.Errorf("stopped with error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("gst-launch not stopped")
+	}
+
+	if err = frmwrk.Start(); err != ErrNotStartable {
+		t.Errorf("restart stopped framework: %v, want %v", err, ErrNotStartable)
+	}
+}
+
+func TestGStreamerDriverRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer new_test_gst_launch(t, dir)()
+
+	fw := strings.Replace(strings.TrimPrefix(test_gstreamer_pipeline, "\n"), "\n", "\n  ", -1)
+	opt := new_test_driver_option(t, fmt.Sprintf(`
+work_dir: %v
+inputs:
+  0:
+    file: test
+outputs:
+  0:
+    file_prefix: rtmp://localhost/live
+restart:
+  policy: on-failure
+  initial_delay: 10ms
+  max_attempts: 2
+framework:
+  name: gstreamer
+  %v`, dir, fw))
+
+	drv, err := new_simple_camera_driver(opt, new_random_live_id_output, new_test_logger(), &test_driver_module{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs, err := drv.Start(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// framework exits with error, restarted twice, then driver gives up.
+	deadline := time.Now().Add(5 * time.Second)
+	for drv.State() != CAMERA_DRIVER_STATE_OFF && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	st := drv.Status()
+	if st.State != CAMERA_DRIVER_STATE_OFF {
+		t.Fatalf("state %v, want off", st.State)
+	}
+
+	if runs := read_test_lines(t, filepath.Join(dir, "runs")); len(runs) != 3 {
+		t.Errorf("gst-launch runs %v, want 3", len(runs))
+	}
+
+	if st.RestartCount != 2 {
+		t.Errorf("restart count %v, want 2", st.RestartCount)
+	}
+
+	if !strings.Contains(st.LastExitReason, "Could not open resource for writing") {
+		t.Errorf("last exit reason %q", st.LastExitReason)
+	}
+
+	// sinks of pipeline are output of driver and snapshot output.
+	args := read_test_lines(t, filepath.Join(dir, "args"))
+	sinks := map[string]bool{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "location=") {
+			sinks[strings.TrimPrefix(arg, "location=")] = true
+		}
+	}
+	if !sinks[outputs[0].File] || !sinks[drv.snap.live_file()] {
+		t.Errorf("sinks %v, want output %v and snapshot", sinks, outputs[0].File)
+	}
+}
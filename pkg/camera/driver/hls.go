@@ -0,0 +1,259 @@
+package camera_driver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+ * HLS: outputs of `hls` type are written as playlist and segments to local directory by ffmpeg framework,
+ *   served by embedded http server, see `http.go`.
+ * Options:
+ *   driver:
+ *   ...
+ *     outputs:
+ *       <label>:
+ *         type: hls
+ *         [ path: <path> ]  // url path prefix, default `/hls/<label>`, playlist is `<path>/<live id>/index.m3u8`.
+ *         [ dir: <dir> ]  // working directory of segments, default `<work dir>/metathings-camera/hls/<label>`.
+ *         [ segment_time: <duration> ]  // target segment duration, default `2s`.
+ *         [ list_size: <count> ]  // segments in playlist window, default 6.
+ *         [ segment_type: <type> ]  // `mpegts`(default) or `fmp4`.
+ *         [ playbacks: ]  // same as simple driver.
+ *   ...
+ *
+ * Live id is random for each start, playlist url is published to `hls/<label>` object,
+ * and `hls` object if it is the first output.
+ * Segments out of window are deleted by framework, playlist and segments are cleaned when streaming starts or stops,
+ * other files in directory are kept.
+ */
+
+const (
+	OUTPUT_TYPE_HLS = "hls"
+
+	HLS_DEFAULT_SEGMENT_TIME = 2 * time.Second
+	HLS_DEFAULT_LIST_SIZE    = 6
+
+	HLS_SEGMENT_TYPE_MPEGTS = "mpegts"
+	HLS_SEGMENT_TYPE_FMP4   = "fmp4"
+
+	hls_playlist_file = "index.m3u8"
+	hls_live_id_size  = 32
+)
+
+var hls_file_name_regexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// hls_framework_file_patterns match playlist and segments written by framework in working directory.
+var hls_framework_file_patterns = []string{"index.m3u8", "index.m3u8.tmp", "index*.ts", "index*.m4s", "init.mp4"}
+
+var hls_content_types = map[string]string{
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+	".m4s":  "video/iso.segment",
+	".mp4":  "video/mp4",
+}
+
+type HlsConfig struct {
+	Path        string
+	Dir         string
+	SegmentTime time.Duration
+	ListSize    int
+	SegmentType string
+}
+
+// hls_handler serves playlist and segments of current live id.
+type hls_handler struct {
+	cfg *HlsConfig
+
+	mtx     sync.Mutex
+	live_id string
+}
+
+func (h *hls_handler) current() string {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	return h.live_id
+}
+
+// ServeHTTP serves `<live id>/<file>` in working directory.
+func (h *hls_handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ss := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	live_id := h.current()
+	if len(ss) != 2 || live_id == "" || ss[0] != live_id || !hls_file_name_regexp.MatchString(ss[1]) {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := os.Open(filepath.Join(h.cfg.Dir, ss[1]))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	ext := path.Ext(ss[1])
+	if typ, ok := hls_content_types[ext]; ok {
+		w.Header().Set("Content-Type", typ)
+	}
+
+	// playlist and init segment are rewritten, media segments are immutable.
+	if ext == ".m3u8" || ss[1] == "init.mp4" {
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, immutable", int(h.cfg.window().Seconds())))
+	}
+
+	http.ServeContent(w, r, ss[1], info.ModTime(), f)
+}
+
+// window returns duration of segments kept in working directory.
+func (c *HlsConfig) window() time.Duration {
+	return 2 * time.Duration(c.ListSize) * c.SegmentTime
+}
+
+// clean removes playlist and segments of framework, directory is shared with other files maybe.
+func (c *HlsConfig) clean() {
+	infos, err := ioutil.ReadDir(c.Dir)
+	if err != nil {
+		return
+	}
+
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		for _, pattern := range hls_framework_file_patterns {
+			if ok, _ := filepath.Match(pattern, info.Name()); ok {
+				os.Remove(filepath.Join(c.Dir, info.Name()))
+				break
+			}
+		}
+	}
+}
+
+// prepare cleans working directory for new framework.
+func (h *hls_handler) prepare() error {
+	if err := os.MkdirAll(h.cfg.Dir, 0755); err != nil {
+		return err
+	}
+	h.cfg.clean()
+
+	return nil
+}
+
+// reset stops serving and removes playlist and segments.
+func (h *hls_handler) reset() {
+	h.mtx.Lock()
+	h.live_id = ""
+	h.mtx.Unlock()
+
+	h.cfg.clean()
+}
+
+// new_output serves playlist by new live id.
+func (h *hls_handler) new_output(srv *http_server, label string, cfg *CameraDriverOption) (*CameraDriverOutput, error) {
+	live_id := random_strings(hls_live_id_size)
+	u := srv.url(path.Join(h.cfg.Path, live_id, hls_playlist_file))
+
+	out := &CameraDriverOutput{
+		Label:  label,
+		Url:    u.String(),
+		LiveId: live_id,
+		File:   filepath.Join(h.cfg.Dir, hls_playlist_file),
+	}
+
+	ctx := &simple_camera_driver_output_context{
+		Label:    label,
+		LiveId:   live_id,
+		Url:      out.Url,
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Hostname: u.Hostname(),
+		Path:     u.Path,
+	}
+
+	var err error
+	if out.Playbacks, err = render_playbacks(label, cfg.GetStringMapString("playbacks"), ctx); err != nil {
+		return nil, err
+	}
+
+	h.mtx.Lock()
+	h.live_id = live_id
+	h.mtx.Unlock()
+
+	return out, nil
+}
+
+// apply_output sets hls muxer of output.
+// NOTE: fw should be a cloned option, it will be modified.
+func (c *HlsConfig) apply_output(fw *CameraDriverOption, label string) {
+	k := "outputs." + label
+
+	fw.Set(k+".format", "hls")
+	fw.Set(k+".options.hls_time", strconv.FormatFloat(c.SegmentTime.Seconds(), 'f', -1, 64))
+	fw.Set(k+".options.hls_list_size", strconv.Itoa(c.ListSize))
+	fw.Set(k+".options.hls_flags", "delete_segments+independent_segments")
+	fw.Set(k+".options.hls_segment_type", c.SegmentType)
+	// segment names are not reused after framework restarted.
+	fw.Set(k+".options.hls_start_number_source", "epoch")
+}
+
+func new_hls_config(label string, opt *CameraDriverOption, work_dir string) (*HlsConfig, error) {
+	key := fmt.Sprintf("outputs.%v", label)
+
+	c := &HlsConfig{
+		Path:        path.Clean("/" + opt.GetString("path")),
+		Dir:         opt.GetString("dir"),
+		SegmentTime: HLS_DEFAULT_SEGMENT_TIME,
+		ListSize:    HLS_DEFAULT_LIST_SIZE,
+		SegmentType: HLS_SEGMENT_TYPE_MPEGTS,
+	}
+
+	if c.Path == "/" {
+		c.Path = path.Join("/hls", label)
+	}
+
+	if c.Dir == "" {
+		c.Dir = filepath.Join(default_work_dir(work_dir), "metathings-camera", "hls", label)
+	}
+
+	if opt.IsSet("segment_time") {
+		if c.SegmentTime = opt.GetDuration("segment_time"); c.SegmentTime < 500*time.Millisecond {
+			return nil, new_invalid_config_error(key + ".segment_time")
+		}
+	}
+
+	if opt.IsSet("list_size") {
+		if c.ListSize = opt.GetInt("list_size"); c.ListSize < 1 {
+			return nil, new_invalid_config_error(key + ".list_size")
+		}
+	}
+
+	if val := opt.GetString("segment_type"); val != "" {
+		switch val {
+		case HLS_SEGMENT_TYPE_MPEGTS, HLS_SEGMENT_TYPE_FMP4:
+			c.SegmentType = val
+		default:
+			return nil, new_invalid_config_error(key + ".segment_type")
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,60 @@
+package camera_driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestHlsCleanKeepsOtherFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := &hls_handler{cfg: &HlsConfig{Dir: dir}}
+	if err = h.prepare(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		"index.m3u8", "index.m3u8.tmp", "index1600000000.ts", "index1600000001.m4s", "init.mp4",
+		"notes.txt", "video.ts", "other.m3u8",
+	} {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = os.Mkdir(filepath.Join(dir, "index0.ts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	h.reset()
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+
+	want := []string{"index0.ts", "notes.txt", "other.m3u8", "video.ts"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("files %v, want %v", names, want)
+	}
+
+	if err = h.prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("file removed by prepare: %v", err)
+	}
+}
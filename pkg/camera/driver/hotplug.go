@@ -0,0 +1,174 @@
+package camera_driver
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	v4l2 "github.com/nayotta/metathings-component-camera/pkg/camera/v4l2"
+)
+
+/*
+ * Hotplug: framework is stopped when device of input unplugged, and relaunched when plugged back,
+ *   started camera reports `disconnected` state meanwhile, and resumes streaming with same outputs.
+ *   Inputs with device file in `/dev` or `device` section are watched by polling.
+ * Options:
+ *   driver:
+ *   ...
+ *     [ hotplug: ]
+ *       [ disable: <bool> ]  // disable watching devices, exited framework is handled by restart policy only.
+ *       [ interval: <duration> ]  // interval of polling devices, default `1s`.
+ *   ...
+ *
+ * Camera started while device unplugged is disconnected until device plugged.
+ */
+
+const (
+	HOTPLUG_DEFAULT_INTERVAL = 1 * time.Second
+	// device node is removed asynchronously after device unplugged,
+	// framework exited with error waits it before checking devices.
+	HOTPLUG_SETTLE_DELAY = 200 * time.Millisecond
+)
+
+type HotplugConfig struct {
+	Interval time.Duration
+}
+
+// hotplug_watched reports input of driver is device could be unplugged.
+func hotplug_watched(drv_in *CameraDriverOption) bool {
+	return drv_in.Sub("device") != nil || strings.HasPrefix(drv_in.GetString("file"), "/dev/")
+}
+
+// devices_present reports devices of inputs are present, ambiguous devices are present and reported by launching.
+func (d *SimpleCameraDriver) devices_present() bool {
+	drv_ins := d.opt.Sub("inputs")
+	if drv_ins == nil {
+		return true
+	}
+
+	for _, k := range drv_ins.NextKeys() {
+		drv_in := drv_ins.Sub(k)
+
+		if sel := drv_in.Sub("device"); sel != nil {
+			if _, err := select_v4l2_device(sel, fmt.Sprintf("inputs.%v.device", k)); v4l2.IsDeviceNotFound(err) {
+				return false
+			}
+			continue
+		}
+
+		if !hotplug_watched(drv_in) {
+			continue
+		}
+
+		if _, err := os.Stat(drv_in.GetString("file")); os.IsNotExist(err) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// on_framework_exit disconnects instead of restarting framework if devices unplugged.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) on_framework_exit(err error) bool {
+	if d.devices_present() {
+		return false
+	}
+
+	d.disconnect()
+	return true
+}
+
+// disconnect stops framework until devices plugged back.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) disconnect() {
+	d.stop()
+
+	if d.disconnected {
+		return
+	}
+	d.disconnected = true
+	d.last_exit_reason = "device disconnected"
+	d.logger.Warningf("camera device disconnected")
+
+	if d.st == CAMERA_DRIVER_STATE_ON {
+		if err := d.mdl.PutObject("state", strings.NewReader(CAMERA_DRIVER_STATE_DISCONNECTED.String())); err != nil {
+			d.logger.WithError(err).Warningf("failed to write disconnected state")
+		}
+	}
+}
+
+// reconnect relaunches framework after devices plugged back.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) reconnect() {
+	d.disconnected = false
+	d.logger.Infof("camera device reconnected")
+
+	if !d.active() {
+		return
+	}
+
+	if d.st == CAMERA_DRIVER_STATE_ON {
+		if err := d.mdl.PutObject("state", strings.NewReader(CAMERA_DRIVER_STATE_ON.String())); err != nil {
+			d.logger.WithError(err).Warningf("failed to write on state")
+		}
+	}
+
+	d.relaunch_or_reset()
+}
+
+func (d *SimpleCameraDriver) watch_devices() {
+	ticker := time.NewTicker(d.hotplug.Interval)
+	defer ticker.Stop()
+
+	// devices are reconnected after present in two polls, permissions of new device node are set by udev later.
+	last_present := true
+	for {
+		<-ticker.C
+
+		present := d.devices_present()
+
+		d.op_mtx.Lock()
+		switch {
+		case !present && !d.disconnected && d.active():
+			d.disconnect()
+		case present && last_present && d.disconnected:
+			d.reconnect()
+		}
+		d.op_mtx.Unlock()
+
+		last_present = present
+	}
+}
+
+// new_hotplug_config returns nil if disabled or no input watched.
+func new_hotplug_config(opt *CameraDriverOption, drv_ins *CameraDriverOption) (*HotplugConfig, error) {
+	c := &HotplugConfig{
+		Interval: HOTPLUG_DEFAULT_INTERVAL,
+	}
+
+	if opt != nil {
+		if opt.GetBool("disable") {
+			return nil, nil
+		}
+
+		if opt.IsSet("interval") {
+			if c.Interval = opt.GetDuration("interval"); c.Interval <= 0 {
+				return nil, new_invalid_config_error("hotplug.interval")
+			}
+		}
+	}
+
+	if drv_ins == nil {
+		return nil, nil
+	}
+
+	for _, k := range drv_ins.NextKeys() {
+		if hotplug_watched(drv_ins.Sub(k)) {
+			return c, nil
+		}
+	}
+
+	return nil, nil
+}
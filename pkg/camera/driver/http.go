@@ -0,0 +1,159 @@
+package camera_driver
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+ * HTTP: embedded http server for outputs served from device, like `hls` and `mjpeg`.
+ * Options:
+ *   driver:
+ *   ...
+ *     http:
+ *       [ address: <host>:<port> ]  // listen address, default `:8080`.
+ *       [ host: <host> ]  // host of published urls, default the first non-loopback address.
+ *       [ cors_origin: <origin> ]  // `Access-Control-Allow-Origin` of responses, default `*`, `-` disables it.
+ *   ...
+ */
+
+const (
+	HTTP_DEFAULT_ADDRESS     = ":8080"
+	HTTP_DEFAULT_CORS_ORIGIN = "*"
+)
+
+type HttpConfig struct {
+	Address    string
+	Host       string
+	CorsOrigin string
+}
+
+// http_server routes requests to handlers by the longest path prefix.
+type http_server struct {
+	cfg    *HttpConfig
+	ln     net.Listener
+	logger log.FieldLogger
+
+	mtx      sync.Mutex
+	handlers map[string]http.Handler
+}
+
+func (s *http_server) handle(prefix string, h http.Handler) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.handlers[prefix] = h
+}
+
+func (s *http_server) remove(prefix string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	delete(s.handlers, prefix)
+}
+
+func (s *http_server) lookup(p string) (string, http.Handler) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var prefix string
+	var h http.Handler
+	for k, x := range s.handlers {
+		if strings.HasPrefix(p, k) && len(k) > len(prefix) {
+			prefix, h = k, x
+		}
+	}
+
+	return prefix, h
+}
+
+func (s *http_server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.CorsOrigin != "-" {
+		w.Header().Set("Access-Control-Allow-Origin", s.cfg.CorsOrigin)
+	}
+
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	prefix, h := s.lookup(r.URL.Path)
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	http.StripPrefix(prefix, h).ServeHTTP(w, r)
+}
+
+func (s *http_server) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+// url returns published url of path.
+func (s *http_server) url(p string) *url.URL {
+	return &url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.port())),
+		Path:   p,
+	}
+}
+
+func (c *HttpConfig) new_server(logger log.FieldLogger) (*http_server, error) {
+	ln, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &http_server{
+		cfg:      c,
+		ln:       ln,
+		logger:   logger,
+		handlers: map[string]http.Handler{},
+	}
+
+	go func() {
+		if err := http.Serve(ln, s); err != nil {
+			logger.WithError(err).Warningf("failed to serve http")
+		}
+	}()
+
+	return s, nil
+}
+
+func new_http_config(opt *CameraDriverOption) (*HttpConfig, error) {
+	if opt == nil {
+		return nil, nil
+	}
+
+	c := &HttpConfig{
+		Address:    HTTP_DEFAULT_ADDRESS,
+		Host:       opt.GetString("host"),
+		CorsOrigin: HTTP_DEFAULT_CORS_ORIGIN,
+	}
+
+	if val := opt.GetString("address"); val != "" {
+		if _, _, err := net.SplitHostPort(val); err != nil {
+			return nil, new_invalid_config_error("http.address")
+		}
+		c.Address = val
+	}
+
+	if c.Host == "" {
+		c.Host = default_output_host()
+	}
+
+	if val := opt.GetString("cors_origin"); val != "" {
+		c.CorsOrigin = val
+	}
+
+	return c, nil
+}
@@ -0,0 +1,76 @@
+package camera_driver
+
+import (
+	"bytes"
+	"sync"
+)
+
+// log_ring keeps recent log lines in a bounded ring buffer.
+type log_ring struct {
+	mtx   *sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+func (r *log_ring) Push(line string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if len(r.lines) == 0 {
+		return
+	}
+
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Tail returns last n lines in order, all lines if n <= 0.
+func (r *log_ring) Tail(n int) []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var lines []string
+	if r.full {
+		lines = append(lines, r.lines[r.next:]...)
+	}
+	lines = append(lines, r.lines[:r.next]...)
+
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return lines
+}
+
+func new_log_ring(size int) *log_ring {
+	if size < 0 {
+		size = 0
+	}
+
+	return &log_ring{
+		mtx:   new(sync.Mutex),
+		lines: make([]string, size),
+	}
+}
+
+// scan_log_lines is a bufio.SplitFunc, split on '\r' or '\n',
+// progress lines of ffmpeg are terminated by '\r'.
+func scan_log_lines(data []byte, at_eof bool) (advance int, token []byte, err error) {
+	if at_eof && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+
+	if at_eof {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
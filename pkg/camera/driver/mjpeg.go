@@ -0,0 +1,548 @@
+package camera_driver
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+ * MJPEG: outputs of `mjpeg` type are served as `multipart/x-mixed-replace` stream by embedded http server,
+ *   see `http.go`, viewers play by `<img src="...">` without video decoder.
+ * Options:
+ *   driver:
+ *   ...
+ *     outputs:
+ *       <label>:
+ *         type: mjpeg
+ *         [ path: <path> ]  // url path, default `/mjpeg/<label>`.
+ *         [ frame_rate: <rate> ]  // max frame rate, default `5`.
+ *         [ frame_size: <width>x<height> ]  // scale frames, default input size.
+ *         [ quality: <1-100> ]  // jpeg quality, default 75.
+ *         [ idle_timeout: <duration> ]  // stop encoding after the last viewer left, default `10s`.
+ *         [ dir: <dir> ]  // directory of frame fifo, default `<work dir>/metathings-camera/mjpeg/<label>`.
+ *         [ auth: ]  // basic auth of viewers, anonymous if absent.
+ *           username: <name>
+ *           [ password: <password> ]
+ *           [ password_env: <name> ]  // environment variable of password, overrides `password`.
+ *         [ playbacks: ]  // same as simple driver.
+ *   ...
+ *
+ * Framework writes `mpjpeg` to fifo only when viewers connected, fifo is read while streaming, frames are copied to all viewers,
+ * slow viewer skips frames. Framework is relaunched when the first viewer came or encoding stopped,
+ * other outputs are interrupted shortly like recording started or stopped.
+ * Url is published to `mjpeg/<label>` object, and `mjpeg` object if it is the first output.
+ */
+
+const (
+	OUTPUT_TYPE_MJPEG = "mjpeg"
+
+	MJPEG_DEFAULT_FRAME_RATE   = "5"
+	MJPEG_DEFAULT_QUALITY      = 75
+	MJPEG_DEFAULT_IDLE_TIMEOUT = 10 * time.Second
+
+	// boundary of `mpjpeg` muxer of ffmpeg, set to `multipartmux` of gstreamer.
+	MJPEG_FRAMEWORK_BOUNDARY = "ffmpeg"
+
+	mjpeg_boundary       = "mjpegframe"
+	mjpeg_max_frame_size = 16 << 20
+	mjpeg_reopen_delay   = 1 * time.Second
+	mjpeg_wake_interval  = 100 * time.Millisecond
+)
+
+type MjpegAuth struct {
+	Username string
+	Password string
+}
+
+type MjpegConfig struct {
+	Path        string
+	Dir         string
+	FrameRate   string
+	FrameSize   string
+	Quality     int
+	IdleTimeout time.Duration
+	Auth        *MjpegAuth
+}
+
+func (c *MjpegConfig) fifo() string {
+	return filepath.Join(c.Dir, "mjpeg.fifo")
+}
+
+// apply_output sets mpjpeg output encoded separately.
+// NOTE: fw should be a cloned option, it will be modified.
+func (c *MjpegConfig) apply_output(fw *CameraDriverOption, label string) {
+	k := "outputs." + label
+
+	fw.Set(k+".format", "mpjpeg")
+	fw.Set(k+".video.codec.name", "mjpeg")
+	fw.Set(k+".video.frame_rate", c.FrameRate)
+	fw.Set(k+".video.quality", c.Quality)
+	if c.FrameSize != "" {
+		fw.Set(k+".video.frame_size", c.FrameSize)
+	}
+}
+
+// mjpeg_hub reads frames from fifo and copies them to viewers while streaming,
+// demand changed callback is called when the first viewer came or idle timeout.
+type mjpeg_hub struct {
+	cfg       *MjpegConfig
+	logger    log.FieldLogger
+	on_demand func()
+
+	mtx     sync.Mutex
+	enabled bool
+	demand  bool
+	idle    *time.Timer
+	viewers map[chan []byte]struct{}
+	// reading fifo is stopped by closing stop, done is closed after reading exited.
+	stop chan struct{}
+	done chan struct{}
+	fifo *os.File
+}
+
+// active reports frames are demanded by viewers, framework should encode output.
+func (h *mjpeg_hub) active() bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	return h.demand
+}
+
+func (h *mjpeg_hub) authorized(r *http.Request) bool {
+	if h.cfg.Auth == nil {
+		return true
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	u := subtle.ConstantTimeCompare([]byte(username), []byte(h.cfg.Auth.Username))
+	p := subtle.ConstantTimeCompare([]byte(password), []byte(h.cfg.Auth.Password))
+	return u&p == 1
+}
+
+func (h *mjpeg_hub) join() (chan []byte, bool) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if !h.enabled {
+		return nil, false
+	}
+
+	if h.idle != nil {
+		h.idle.Stop()
+		h.idle = nil
+	}
+
+	ch := make(chan []byte, 1)
+	h.viewers[ch] = struct{}{}
+
+	if !h.demand {
+		h.demand = true
+		go h.on_demand()
+	}
+
+	return ch, true
+}
+
+func (h *mjpeg_hub) leave(ch chan []byte) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if _, ok := h.viewers[ch]; !ok {
+		return
+	}
+	delete(h.viewers, ch)
+
+	if len(h.viewers) > 0 || !h.demand {
+		return
+	}
+
+	var idle *time.Timer
+	idle = time.AfterFunc(h.cfg.IdleTimeout, func() {
+		h.mtx.Lock()
+		defer h.mtx.Unlock()
+
+		if h.idle != idle || len(h.viewers) > 0 {
+			return
+		}
+
+		h.idle = nil
+		h.demand = false
+		go h.on_demand()
+	})
+	h.idle = idle
+}
+
+// ServeHTTP streams frames until viewer left or streaming stopped.
+func (h *mjpeg_hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "" && r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="camera"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	ch, ok := h.join()
+	if !ok {
+		http.Error(w, ErrNotStreaming.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer h.leave(ch)
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpeg_boundary)
+	w.Header().Set("Cache-Control", "no-cache, no-store, private")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case frame, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", mjpeg_boundary, len(frame)); err != nil {
+				return
+			}
+			if _, err := w.Write(frame); err != nil {
+				return
+			}
+			if _, err := io.WriteString(w, "\r\n"); err != nil {
+				return
+			}
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// broadcast sends frame to viewers, viewer still writing last frame gets the latest one.
+func (h *mjpeg_hub) broadcast(frame []byte) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	for ch := range h.viewers {
+		select {
+		case ch <- frame:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- frame:
+			default:
+			}
+		}
+	}
+}
+
+// enable accepts viewers and starts reading fifo for new streaming.
+func (h *mjpeg_hub) enable() {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.enabled = true
+
+	if h.stop == nil {
+		h.stop = make(chan struct{})
+		h.done = make(chan struct{})
+		go h.run(h.stop, h.done)
+	}
+}
+
+// close_reading stops reading fifo, reader blocked in opening fifo is woken until exited.
+// NOTE: should be call after `mtx` locked!
+func (h *mjpeg_hub) close_reading() {
+	if h.stop == nil {
+		return
+	}
+
+	close(h.stop)
+	h.stop = nil
+
+	if h.fifo != nil {
+		h.fifo.Close()
+		h.fifo = nil
+	}
+
+	file, done := h.cfg.fifo(), h.done
+	go func() {
+		for {
+			wake_fifo_reader(file)
+
+			select {
+			case <-done:
+				return
+			case <-time.After(mjpeg_wake_interval):
+			}
+		}
+	}()
+}
+
+// reset disconnects viewers, stops accepting them and reading fifo.
+func (h *mjpeg_hub) reset() {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.close_reading()
+
+	if h.idle != nil {
+		h.idle.Stop()
+		h.idle = nil
+	}
+
+	for ch := range h.viewers {
+		close(ch)
+	}
+	h.viewers = map[chan []byte]struct{}{}
+	h.enabled = false
+	h.demand = false
+}
+
+// new_output accepts viewers, file of output is frame fifo.
+func (h *mjpeg_hub) new_output(srv *http_server, label string, cfg *CameraDriverOption) (*CameraDriverOutput, error) {
+	u := srv.url(h.cfg.Path)
+
+	out := &CameraDriverOutput{
+		Label:  label,
+		Url:    u.String(),
+		LiveId: path.Base(h.cfg.Path),
+		File:   h.cfg.fifo(),
+	}
+
+	ctx := &simple_camera_driver_output_context{
+		Label:    label,
+		LiveId:   out.LiveId,
+		Url:      out.Url,
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Hostname: u.Hostname(),
+		Path:     u.Path,
+	}
+
+	var err error
+	if out.Playbacks, err = render_playbacks(label, cfg.GetStringMapString("playbacks"), ctx); err != nil {
+		return nil, err
+	}
+
+	h.enable()
+
+	return out, nil
+}
+
+// wake_fifo_reader unblocks reader waiting in opening fifo, reader reads EOF.
+func wake_fifo_reader(file string) {
+	f, err := os.OpenFile(file, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err == nil {
+		f.Close()
+	}
+}
+
+// opened keeps fifo to be closed by stopping, returns false if stopped.
+func (h *mjpeg_hub) opened(stop chan struct{}, f *os.File) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	select {
+	case <-stop:
+		return false
+	default:
+	}
+
+	h.fifo = f
+	return true
+}
+
+// read_fifo reads frames until framework closed fifo or reading stopped.
+func (h *mjpeg_hub) read_fifo(stop chan struct{}) error {
+	// open blocks until framework opens fifo for writing, or woken by stopping.
+	f, err := os.Open(h.cfg.fifo())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !h.opened(stop, f) {
+		return nil
+	}
+	defer h.opened(stop, nil)
+
+	return h.read_frames(bufio.NewReader(f))
+}
+
+func (h *mjpeg_hub) run(stop, done chan struct{}) {
+	defer close(done)
+
+	for {
+		err := h.read_fifo(stop)
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			h.logger.WithError(err).Warningf("failed to read mjpeg fifo")
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(mjpeg_reopen_delay):
+			}
+		}
+	}
+}
+
+func (h *mjpeg_hub) read_frames(r *bufio.Reader) error {
+	for {
+		frame, err := read_mpjpeg_frame(r)
+		if err != nil {
+			return err
+		}
+
+		h.broadcast(frame)
+	}
+}
+
+// read_mpjpeg_frame reads next part of multipart stream, part should have content length.
+func read_mpjpeg_frame(r *bufio.Reader) ([]byte, error) {
+	tp := textproto.NewReader(r)
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(line, "--") {
+			break
+		}
+	}
+
+	hdr, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(hdr.Get("Content-Length"))
+	if err != nil || n <= 0 || n > mjpeg_max_frame_size {
+		return nil, ErrInvalidMjpegFrame
+	}
+
+	frame := make([]byte, n)
+	if _, err = io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+func new_mjpeg_hub(cfg *MjpegConfig, logger log.FieldLogger, on_demand func()) (*mjpeg_hub, error) {
+	if err := mkfifo(cfg.fifo()); err != nil {
+		return nil, err
+	}
+
+	h := &mjpeg_hub{
+		cfg:       cfg,
+		logger:    logger,
+		on_demand: on_demand,
+		viewers:   map[chan []byte]struct{}{},
+	}
+
+	return h, nil
+}
+
+func new_mjpeg_config(label string, opt *CameraDriverOption, work_dir string) (*MjpegConfig, error) {
+	key := fmt.Sprintf("outputs.%v", label)
+
+	c := &MjpegConfig{
+		Path:        path.Clean("/" + opt.GetString("path")),
+		Dir:         opt.GetString("dir"),
+		FrameRate:   MJPEG_DEFAULT_FRAME_RATE,
+		FrameSize:   opt.GetString("frame_size"),
+		Quality:     MJPEG_DEFAULT_QUALITY,
+		IdleTimeout: MJPEG_DEFAULT_IDLE_TIMEOUT,
+	}
+
+	if c.Path == "/" {
+		c.Path = path.Join("/mjpeg", label)
+	}
+
+	if c.Dir == "" {
+		c.Dir = filepath.Join(default_work_dir(work_dir), "metathings-camera", "mjpeg", label)
+	}
+
+	if val := opt.GetString("frame_rate"); val != "" {
+		if rate, err := strconv.ParseFloat(val, 64); err != nil || rate <= 0 {
+			return nil, new_invalid_config_error(key + ".frame_rate")
+		}
+		c.FrameRate = val
+	}
+
+	if c.FrameSize != "" {
+		if _, _, err := parse_frame_size(c.FrameSize); err != nil {
+			return nil, new_invalid_config_error(key + ".frame_size")
+		}
+	}
+
+	if opt.IsSet("quality") {
+		if c.Quality = opt.GetInt("quality"); c.Quality < 1 || c.Quality > 100 {
+			return nil, new_invalid_config_error(key + ".quality")
+		}
+	}
+
+	if opt.IsSet("idle_timeout") {
+		if c.IdleTimeout = opt.GetDuration("idle_timeout"); c.IdleTimeout < 0 {
+			return nil, new_invalid_config_error(key + ".idle_timeout")
+		}
+	}
+
+	if auth := opt.Sub("auth"); auth != nil {
+		c.Auth = &MjpegAuth{
+			Username: auth.GetString("username"),
+			Password: auth.GetString("password"),
+		}
+
+		if name := auth.GetString("password_env"); name != "" {
+			c.Auth.Password = os.Getenv(name)
+		}
+
+		if c.Auth.Username == "" || c.Auth.Password == "" {
+			return nil, new_invalid_config_error(key + ".auth")
+		}
+	}
+
+	return c, nil
+}
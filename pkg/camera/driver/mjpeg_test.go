@@ -0,0 +1,106 @@
+package camera_driver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func new_test_mjpeg_hub(t *testing.T, dir string) *mjpeg_hub {
+	cfg := &MjpegConfig{
+		Dir:         filepath.Join(dir, "mjpeg"),
+		IdleTimeout: time.Second,
+	}
+
+	h, err := new_mjpeg_hub(cfg, new_test_logger(), func() {})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func wait_mjpeg_reading_stopped(t *testing.T, done chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("reading mjpeg fifo not stopped")
+	}
+}
+
+func TestMjpegHubStopWaitingFifo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := new_test_mjpeg_hub(t, dir)
+
+	h.enable()
+	h.mtx.Lock()
+	done := h.done
+	h.mtx.Unlock()
+
+	// reader is blocked in opening fifo without framework.
+	time.Sleep(100 * time.Millisecond)
+	h.reset()
+
+	wait_mjpeg_reading_stopped(t, done)
+}
+
+func TestMjpegHubStopReadingFifo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := new_test_mjpeg_hub(t, dir)
+
+	h.enable()
+	h.mtx.Lock()
+	done := h.done
+	h.mtx.Unlock()
+
+	ch, ok := h.join()
+	if !ok {
+		t.Fatalf("viewer not joined")
+	}
+
+	// framework keeps fifo opened.
+	w, err := os.OpenFile(h.cfg.fifo(), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if _, err = w.Write([]byte("--ffmpeg\r\nContent-Type: image/jpeg\r\nContent-Length: 4\r\n\r\njpeg\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case frame := <-ch:
+		if string(frame) != "jpeg" {
+			t.Errorf("frame %q, want jpeg", frame)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("frame not received")
+	}
+
+	h.reset()
+
+	wait_mjpeg_reading_stopped(t, done)
+
+	// reading is started again for next streaming.
+	h.enable()
+	h.mtx.Lock()
+	done = h.done
+	h.mtx.Unlock()
+
+	h.reset()
+
+	wait_mjpeg_reading_stopped(t, done)
+}
@@ -0,0 +1,70 @@
+package camera_driver
+
+import (
+	"io"
+	"path"
+
+	opt_helper "github.com/nayotta/metathings/pkg/common/option"
+	component "github.com/nayotta/metathings/pkg/component"
+)
+
+// CameraDriverModule is module used by camera driver, objects are put in namespace of camera.
+type CameraDriverModule interface {
+	Kernel() *component.Kernel
+	PutObject(name string, content io.Reader) error
+	PutObjects(objects map[string]io.Reader) error
+	RemoveObject(name string) error
+	// ObjectName returns object name in module of object in namespace of camera.
+	ObjectName(name string) string
+}
+
+// camera_driver_module puts objects of camera under namespace in module, no namespace for single camera.
+type camera_driver_module struct {
+	*component.Module
+	namespace string
+}
+
+func (m *camera_driver_module) ObjectName(name string) string {
+	if m.namespace == "" {
+		return name
+	}
+
+	return path.Join(m.namespace, name)
+}
+
+func (m *camera_driver_module) PutObject(name string, content io.Reader) error {
+	return m.Module.PutObject(m.ObjectName(name), content)
+}
+
+func (m *camera_driver_module) PutObjects(objects map[string]io.Reader) error {
+	xs := make(map[string]io.Reader)
+	for name, content := range objects {
+		xs[m.ObjectName(name)] = content
+	}
+
+	return m.Module.PutObjects(xs)
+}
+
+func (m *camera_driver_module) RemoveObject(name string) error {
+	return m.Module.RemoveObject(m.ObjectName(name))
+}
+
+func NewCameraDriverModule(m *component.Module, namespace string) CameraDriverModule {
+	return &camera_driver_module{Module: m, namespace: namespace}
+}
+
+// ToCameraDriverModule accepts camera driver module, or module of single camera.
+func ToCameraDriverModule(v *CameraDriverModule) func(string, interface{}) error {
+	return func(key string, val interface{}) error {
+		switch x := val.(type) {
+		case CameraDriverModule:
+			*v = x
+		case *component.Module:
+			*v = NewCameraDriverModule(x, "")
+		default:
+			return opt_helper.InvalidArgument(key)
+		}
+
+		return nil
+	}
+}
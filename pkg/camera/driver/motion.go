@@ -0,0 +1,389 @@
+package camera_driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	motion "github.com/nayotta/metathings-component-camera/pkg/camera/motion"
+)
+
+/*
+ * Motion: detect motion on low resolution grayscale frames,
+ *   frames are written to fifo by rawvideo output of framework, framework keeps running while motion configured.
+ * Options:
+ *   driver:
+ *   ...
+ *     motion:
+ *       [ frame_size: <width>x<height> ]  // detect frame size, default `160x120`.
+ *       [ frame_rate: <rate> ]  // detect frame rate, default `5`.
+ *       [ sensitivity: <1-100> ]  // higher sensitivity triggers on smaller changed area, default 50.
+ *       [ threshold: <1-255> ]  // pixel changed if differs from background more than threshold, default 25.
+ *       [ learn_rate: <rate> ]  // background learning rate, default 0.05.
+ *       [ regions: [ "<x>,<y>,<width>,<height>", ... ] ]  // detected regions, normalized 0 to 1, all frame if empty.
+ *       [ masks: [ "<x>,<y>,<width>,<height>", ... ] ]  // ignored regions, normalized 0 to 1.
+ *       [ cooldown: <duration> ]  // min interval between events, default `10s`.
+ *       [ snapshot: <bool> ]  // store snapshot of event, default true.
+ *       [ max_events: <count> ]  // recent events kept for listing, default 1000.
+ *       [ path: <dir> ]  // directory of frame fifo, default `<work dir>/camera-motion`.
+ *   ...
+ *
+ * Events are stored to `motion/<id>` object as json, snapshots to `motion/<id>.jpg` object.
+ */
+
+const (
+	MOTION_DEFAULT_FRAME_SIZE = "160x120"
+	MOTION_DEFAULT_FRAME_RATE = 5
+	MOTION_DEFAULT_COOLDOWN   = 10 * time.Second
+	MOTION_DEFAULT_MAX_EVENTS = 1000
+
+	// framework output label of motion output.
+	MOTION_OUTPUT_LABEL = "_motion"
+
+	motion_reopen_delay = 1 * time.Second
+	// detected motions waiting for recording, dropped if full.
+	motion_queue_size = 8
+)
+
+type MotionConfig struct {
+	Width     int
+	Height    int
+	FrameRate int
+	Detector  *motion.Options
+	Snapshot  bool
+	MaxEvents int
+	Path      string
+}
+
+func (c *MotionConfig) fifo() string {
+	return filepath.Join(c.Path, "motion.fifo")
+}
+
+// apply_output adds motion output to framework option.
+// NOTE: fw should be a cloned option, it will be modified.
+func (c *MotionConfig) apply_output(fw *CameraDriverOption) {
+	k := "outputs." + MOTION_OUTPUT_LABEL
+
+	fw.Set(k+".format", "rawvideo")
+	fw.Set(k+".file", c.fifo())
+	fw.Set(k+".video.codec.name", "rawvideo")
+	fw.Set(k+".video.pixel_format", "gray")
+	fw.Set(k+".video.frame_size", fmt.Sprintf("%vx%v", c.Width, c.Height))
+	fw.Set(k+".video.frame_rate", strconv.Itoa(c.FrameRate))
+}
+
+// make_fifo creates fifo if not exists.
+func (c *MotionConfig) make_fifo() error {
+	return mkfifo(c.fifo())
+}
+
+// mkfifo creates fifo and its directory, file of other type is replaced.
+func mkfifo(file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(file)
+	if err == nil {
+		if info.Mode()&os.ModeNamedPipe != 0 {
+			return nil
+		}
+		if err = os.Remove(file); err != nil {
+			return err
+		}
+	}
+
+	return syscall.Mkfifo(file, 0600)
+}
+
+func motion_event_object(id string) string {
+	return "motion/" + id
+}
+
+func motion_snapshot_object(id string) string {
+	return "motion/" + id + ".jpg"
+}
+
+type motion_region_json struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type motion_event_json struct {
+	Id        string             `json:"id"`
+	Timestamp time.Time          `json:"timestamp"`
+	Score     float64            `json:"score"`
+	Region    motion_region_json `json:"region"`
+	Snapshot  string             `json:"snapshot,omitempty"`
+}
+
+type motion_detection struct {
+	frame *motion.Frame
+	res   *motion.Result
+}
+
+// motion_watcher reads frames from fifo, detects motion and keeps recent events,
+// events are recorded by worker, reading fifo is not blocked by storing objects.
+type motion_watcher struct {
+	cfg      *MotionConfig
+	detector *motion.Detector
+	logger   log.FieldLogger
+	on_event func(*motion.Frame, *motion.Result) (*CameraDriverMotionEvent, error)
+	queue    chan *motion_detection
+
+	mtx    sync.Mutex
+	events []*CameraDriverMotionEvent
+}
+
+func new_motion_watcher(cfg *MotionConfig, detector *motion.Detector, logger log.FieldLogger, on_event func(*motion.Frame, *motion.Result) (*CameraDriverMotionEvent, error)) *motion_watcher {
+	return &motion_watcher{
+		cfg:      cfg,
+		detector: detector,
+		logger:   logger,
+		on_event: on_event,
+		queue:    make(chan *motion_detection, motion_queue_size),
+	}
+}
+
+func (w *motion_watcher) run() {
+	go w.record_events()
+
+	for {
+		// open blocks until framework opens fifo for writing.
+		f, err := os.Open(w.cfg.fifo())
+		if err != nil {
+			w.logger.WithError(err).Warningf("failed to open motion fifo")
+			time.Sleep(motion_reopen_delay)
+			continue
+		}
+
+		w.detector.Reset()
+		err = w.read_frames(f)
+		f.Close()
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			w.logger.WithError(err).Warningf("failed to read motion frames")
+			time.Sleep(motion_reopen_delay)
+		}
+	}
+}
+
+func (w *motion_watcher) read_frames(r io.Reader) error {
+	for {
+		frame := &motion.Frame{
+			Width:  w.cfg.Width,
+			Height: w.cfg.Height,
+			Pix:    make([]byte, w.cfg.Width*w.cfg.Height),
+		}
+
+		if _, err := io.ReadFull(r, frame.Pix); err != nil {
+			return err
+		}
+		frame.Timestamp = time.Now()
+
+		res, ok, err := w.detector.Detect(frame)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			continue
+		}
+
+		select {
+		case w.queue <- &motion_detection{frame: frame, res: res}:
+		default:
+			w.logger.WithField("timestamp", res.Timestamp).Warningf("motion event queue full, drop event")
+		}
+	}
+}
+
+func (w *motion_watcher) record_events() {
+	for x := range w.queue {
+		evt, err := w.on_event(x.frame, x.res)
+		if err != nil {
+			w.logger.WithError(err).Warningf("failed to record motion event")
+			continue
+		}
+		w.push(evt)
+	}
+}
+
+func (w *motion_watcher) push(evt *CameraDriverMotionEvent) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.events = append(w.events, evt)
+	if n := len(w.events) - w.cfg.MaxEvents; n > 0 {
+		w.events = append([]*CameraDriverMotionEvent(nil), w.events[n:]...)
+	}
+}
+
+// list returns events in time range ordered by time, zero begin or end means unbounded.
+func (w *motion_watcher) list(begin, end time.Time) []*CameraDriverMotionEvent {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	var evts []*CameraDriverMotionEvent
+	for _, evt := range w.events {
+		if !begin.IsZero() && evt.Timestamp.Before(begin) {
+			continue
+		}
+
+		if !end.IsZero() && evt.Timestamp.After(end) {
+			continue
+		}
+
+		evts = append(evts, evt)
+	}
+
+	sort.SliceStable(evts, func(i, j int) bool {
+		return evts[i].Timestamp.Before(evts[j].Timestamp)
+	})
+
+	return evts
+}
+
+// encode_motion_frame encodes grayscale frame as jpeg.
+func encode_motion_frame(frame *motion.Frame) ([]byte, error) {
+	img := &image.Gray{
+		Pix:    frame.Pix,
+		Stride: frame.Width,
+		Rect:   image.Rect(0, 0, frame.Width, frame.Height),
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: SNAPSHOT_DEFAULT_QUALITY}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func marshal_motion_event(evt *CameraDriverMotionEvent) ([]byte, error) {
+	return json.Marshal(&motion_event_json{
+		Id:        evt.Id,
+		Timestamp: evt.Timestamp,
+		Score:     evt.Score,
+		Region:    motion_region_json(evt.Region),
+		Snapshot:  evt.Snapshot,
+	})
+}
+
+// parse_motion_rects parses rects like `0.1,0.2,0.5,0.5`.
+func parse_motion_rects(key string, ss []string) ([]motion.Rect, error) {
+	var rs []motion.Rect
+
+	for _, s := range ss {
+		fs := strings.Split(s, ",")
+		if len(fs) != 4 {
+			return nil, new_invalid_config_error(key)
+		}
+
+		var vs [4]float64
+		for i, f := range fs {
+			v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+			if err != nil || v < 0 || v > 1 {
+				return nil, new_invalid_config_error(key)
+			}
+			vs[i] = v
+		}
+
+		rs = append(rs, motion.Rect{X: vs[0], Y: vs[1], Width: vs[2], Height: vs[3]})
+	}
+
+	return rs, nil
+}
+
+func new_motion_config(opt *CameraDriverOption, work_dir string) (*MotionConfig, error) {
+	var err error
+
+	if opt == nil {
+		return nil, nil
+	}
+
+	c := &MotionConfig{
+		FrameRate: MOTION_DEFAULT_FRAME_RATE,
+		Detector: &motion.Options{
+			Sensitivity: motion.DEFAULT_SENSITIVITY,
+			Threshold:   motion.DEFAULT_THRESHOLD,
+			LearnRate:   motion.DEFAULT_LEARN_RATE,
+			Cooldown:    MOTION_DEFAULT_COOLDOWN,
+		},
+		Snapshot:  true,
+		MaxEvents: MOTION_DEFAULT_MAX_EVENTS,
+		Path:      filepath.Join(default_work_dir(work_dir), "camera-motion"),
+	}
+
+	frame_size := MOTION_DEFAULT_FRAME_SIZE
+	if val := opt.GetString("frame_size"); val != "" {
+		frame_size = val
+	}
+	if c.Width, c.Height, err = parse_frame_size(frame_size); err != nil {
+		return nil, new_invalid_config_error("motion.frame_size")
+	}
+
+	if opt.IsSet("frame_rate") {
+		if c.FrameRate = opt.GetInt("frame_rate"); c.FrameRate <= 0 {
+			return nil, new_invalid_config_error("motion.frame_rate")
+		}
+	}
+
+	if opt.IsSet("sensitivity") {
+		c.Detector.Sensitivity = opt.GetInt("sensitivity")
+	}
+
+	if opt.IsSet("threshold") {
+		c.Detector.Threshold = opt.GetInt("threshold")
+	}
+
+	if opt.IsSet("learn_rate") {
+		c.Detector.LearnRate = opt.GetFloat64("learn_rate")
+	}
+
+	if opt.IsSet("cooldown") {
+		c.Detector.Cooldown = opt.GetDuration("cooldown")
+	}
+
+	if c.Detector.Regions, err = parse_motion_rects("motion.regions", opt.GetStringSlice("regions")); err != nil {
+		return nil, err
+	}
+
+	if c.Detector.Masks, err = parse_motion_rects("motion.masks", opt.GetStringSlice("masks")); err != nil {
+		return nil, err
+	}
+
+	if opt.IsSet("snapshot") {
+		c.Snapshot = opt.GetBool("snapshot")
+	}
+
+	if opt.IsSet("max_events") {
+		if c.MaxEvents = opt.GetInt("max_events"); c.MaxEvents <= 0 {
+			return nil, new_invalid_config_error("motion.max_events")
+		}
+	}
+
+	if val := opt.GetString("path"); val != "" {
+		c.Path = val
+	}
+
+	if _, err = motion.NewDetector(c.Detector); err != nil {
+		return nil, new_invalid_config_error("motion")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,56 @@
+package camera_driver
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	motion "github.com/nayotta/metathings-component-camera/pkg/camera/motion"
+)
+
+func TestMotionWatcherNotBlockedByEvents(t *testing.T) {
+	cfg := &MotionConfig{Width: 4, Height: 4, MaxEvents: 100}
+	detector, err := motion.NewDetector(&motion.Options{
+		Sensitivity: 100,
+		Threshold:   10,
+		LearnRate:   0.01,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	w := new_motion_watcher(cfg, detector, new_test_logger(), func(frame *motion.Frame, res *motion.Result) (*CameraDriverMotionEvent, error) {
+		<-release
+		return &CameraDriverMotionEvent{Timestamp: res.Timestamp}, nil
+	})
+	go w.record_events()
+
+	// black background, then frames of white.
+	frames := 2 * motion_queue_size
+	buf := bytes.Repeat([]byte{0}, 16)
+	buf = append(buf, bytes.Repeat([]byte{255}, 16*frames)...)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.read_frames(bytes.NewReader(buf))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("reading frames blocked by recording events")
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for len(w.list(time.Time{}, time.Time{})) < motion_queue_size && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// one event is held by worker, others over queue size are dropped.
+	n := len(w.list(time.Time{}, time.Time{}))
+	if n < motion_queue_size || n > motion_queue_size+1 {
+		t.Errorf("recorded %v events, want %v or %v", n, motion_queue_size, motion_queue_size+1)
+	}
+}
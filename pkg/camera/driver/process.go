@@ -0,0 +1,228 @@
+package camera_driver
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// framework_process runs framework command, tracks its lifecycle and logs,
+// shared by command line based frameworks.
+type framework_process struct {
+	name string
+
+	logger   log.FieldLogger
+	op_mtx   *sync.Mutex
+	cmd      *exec.Cmd
+	cfn      context.CancelFunc
+	errchs   []chan<- error
+	done     chan struct{}
+	start_at time.Time
+	exited   bool
+	exit_err error
+	logs     *log_ring
+	stats    *FrameworkStats
+
+	error_lines int
+	// wait command exit after interrupt before kill, muxers finish files on interrupt.
+	stop_timeout time.Duration
+	// merge stdout into logs, some frameworks report errors on stdout.
+	merge_stdout bool
+	// parse progress line to stats, return nil if line is not a progress line.
+	parse_progress func(line string) *FrameworkStats
+}
+
+// NOTE: should be call after `op_mtx` locked!
+func (p *framework_process) send_to_waiting_channels(err error) {
+	for _, errch := range p.errchs {
+		errch <- err
+	}
+	p.errchs = nil
+}
+
+func (p *framework_process) start(args []string) error {
+	p.op_mtx.Lock()
+	defer p.op_mtx.Unlock()
+
+	if p.cfn != nil {
+		p.logger.WithError(ErrNotStartable).Debugf("%v not startable", p.name)
+		return ErrNotStartable
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	ctx, p.cfn = context.WithCancel(ctx)
+	p.cmd = exec.CommandContext(ctx, args[0], args[1:]...)
+	p.cmd.Stderr = w
+	if p.merge_stdout {
+		p.cmd.Stdout = w
+	}
+
+	err = p.cmd.Start()
+	w.Close()
+	if err != nil {
+		r.Close()
+		p.cfn()
+		p.cfn = nil
+		return err
+	}
+	p.start_at = time.Now()
+	done := make(chan struct{})
+	p.done = done
+
+	go func() {
+		// NOTE: read logs until closed before wait command.
+		p.read_logs(r)
+		r.Close()
+		err := p.cmd.Wait()
+
+		p.op_mtx.Lock()
+		defer p.op_mtx.Unlock()
+
+		if err != nil {
+			err = &FrameworkExitError{Err: err, Logs: p.logs.Tail(p.error_lines)}
+		}
+
+		p.exited = true
+		p.exit_err = err
+		close(done)
+
+		if err != nil {
+			p.logger.WithError(err).Debugf("failed to wait command exit")
+		}
+
+		p.send_to_waiting_channels(err)
+	}()
+
+	p.logger.WithField("cmd", strings.Join(RedactArguments(args), " ")).Debugf("%v start", p.name)
+
+	return nil
+}
+
+func (p *framework_process) read_logs(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scan_log_lines)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if p.parse_progress != nil {
+			if st := p.parse_progress(line); st != nil {
+				p.op_mtx.Lock()
+				p.stats = st
+				p.op_mtx.Unlock()
+				continue
+			}
+		}
+
+		p.logs.Push(line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		p.logger.WithError(err).Debugf("failed to read %v logs", p.name)
+	}
+}
+
+func (p *framework_process) Stop() error {
+	p.op_mtx.Lock()
+	defer p.op_mtx.Unlock()
+
+	if p.cfn == nil {
+		p.logger.WithError(ErrNotStoppable).Debugf("%v not stopable", p.name)
+		return ErrNotStoppable
+	}
+
+	cfn, done := p.cfn, p.done
+	if p.exited || p.stop_timeout <= 0 || p.cmd.Process.Signal(os.Interrupt) != nil {
+		cfn()
+	} else {
+		go func() {
+			timer := time.NewTimer(p.stop_timeout)
+			defer timer.Stop()
+
+			select {
+			case <-done:
+			case <-timer.C:
+				p.logger.Debugf("%v not exited after interrupt, kill it", p.name)
+			}
+			cfn()
+		}()
+	}
+	p.send_to_waiting_channels(nil)
+
+	p.logger.Debugf("%v stop", p.name)
+
+	return nil
+}
+
+func (p *framework_process) Wait() <-chan error {
+	p.op_mtx.Lock()
+	defer p.op_mtx.Unlock()
+
+	errch := make(chan error, 1)
+	if p.exited {
+		errch <- p.exit_err
+		return errch
+	}
+	p.errchs = append(p.errchs, errch)
+
+	return errch
+}
+
+func (p *framework_process) Status() *FrameworkStatus {
+	p.op_mtx.Lock()
+	defer p.op_mtx.Unlock()
+
+	st := &FrameworkStatus{
+		Stats: p.stats,
+		Logs:  p.logs.Tail(0),
+	}
+	if p.cmd == nil || p.cmd.Process == nil || p.exited {
+		return st
+	}
+
+	st.Pid = p.cmd.Process.Pid
+	st.StartAt = p.start_at
+
+	return st
+}
+
+func new_framework_process(name string, opt *FrameworkOption, logger log.FieldLogger) *framework_process {
+	log_lines := FRAMEWORK_DEFAULT_LOG_LINES
+	if opt.IsSet("log_lines") {
+		log_lines = opt.GetInt("log_lines")
+	}
+
+	error_lines := FRAMEWORK_DEFAULT_ERROR_LINES
+	if opt.IsSet("error_lines") {
+		error_lines = opt.GetInt("error_lines")
+	}
+
+	stop_timeout := FRAMEWORK_DEFAULT_STOP_TIMEOUT
+	if opt.IsSet("stop_timeout") {
+		stop_timeout = opt.GetDuration("stop_timeout")
+	}
+
+	return &framework_process{
+		name:         name,
+		logger:       logger,
+		op_mtx:       new(sync.Mutex),
+		logs:         new_log_ring(log_lines),
+		error_lines:  error_lines,
+		stop_timeout: stop_timeout,
+	}
+}
@@ -0,0 +1,273 @@
+package camera_driver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+ * Record: record segments to local directory alongside live outputs,
+ *   by `segment` muxer of ffmpeg framework, encoded once with live outputs.
+ * Options:
+ *   driver:
+ *   ...
+ *     record:
+ *       path: <dir>  // directory of segment files.
+ *       [ format: <format> ]  // `matroska`(default) or `mp4`, matroska is readable even if not finished.
+ *       [ name: <template> ]  // strftime file name template without extension, default `%Y%m%d-%H%M%S`.
+ *       [ segment_time: <duration> ]  // max segment duration, default `10m`.
+ *       [ max_age: <duration> ]  // remove segments older than max age, 0 means keep forever.
+ *       [ max_size: <size> ]  // remove oldest segments when total size exceeded, like `10G`, 0 means unlimited.
+ *       [ check_interval: <duration> ]  // retention check interval, default `1m`.
+ *       [ max_clip_duration: <duration> ]  // max duration of exported clip, default `1h`.
+ *       [ chunk_size: <size> ]  // object size of exported clip parts, default `4M`.
+ *   ...
+ */
+
+const (
+	RECORD_FORMAT_MATROSKA = "matroska"
+	RECORD_FORMAT_MP4      = "mp4"
+
+	RECORD_DEFAULT_NAME           = "%Y%m%d-%H%M%S"
+	RECORD_DEFAULT_SEGMENT_TIME   = 10 * time.Minute
+	RECORD_DEFAULT_CHECK_INTERVAL = 1 * time.Minute
+	RECORD_DEFAULT_MAX_CLIP       = 1 * time.Hour
+	RECORD_DEFAULT_CHUNK_SIZE     = 4 << 20
+
+	// framework output label of record output.
+	RECORD_OUTPUT_LABEL = "_record"
+)
+
+var record_file_exts = map[string]string{
+	RECORD_FORMAT_MATROSKA: "mkv",
+	RECORD_FORMAT_MP4:      "mp4",
+}
+
+type RecordConfig struct {
+	Path          string
+	Format        string
+	Name          string
+	SegmentTime   time.Duration
+	MaxAge        time.Duration
+	MaxSize       int64
+	CheckInterval time.Duration
+
+	MaxClipDuration time.Duration
+	ChunkSize       int64
+}
+
+func (c *RecordConfig) ext() string {
+	return record_file_exts[c.Format]
+}
+
+// apply_output adds record output to framework option.
+// NOTE: fw should be a cloned option, it will be modified.
+func (c *RecordConfig) apply_output(fw *CameraDriverOption) {
+	k := "outputs." + RECORD_OUTPUT_LABEL
+
+	fw.Set(k+".format", "segment")
+	fw.Set(k+".file", filepath.Join(c.Path, c.Name+"."+c.ext()))
+	fw.Set(k+".options.segment_format", c.Format)
+	fw.Set(k+".options.segment_time", strconv.FormatFloat(c.SegmentTime.Seconds(), 'f', -1, 64))
+	fw.Set(k+".options.reset_timestamps", "1")
+	fw.Set(k+".options.strftime", "1")
+}
+
+// segments returns segment files in record path, ordered by modify time.
+func (c *RecordConfig) segments() ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(c.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var segs []os.FileInfo
+	for _, info := range infos {
+		if info.IsDir() || filepath.Ext(info.Name()) != "."+c.ext() {
+			continue
+		}
+		segs = append(segs, info)
+	}
+
+	sort_file_infos_by_mod_time(segs)
+
+	return segs, nil
+}
+
+func sort_file_infos_by_mod_time(infos []os.FileInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+}
+
+// parse_size parses size with binary unit suffix, like `512M`, `10G`.
+func parse_size(s string) (int64, error) {
+	s = strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+
+	unit := int64(1)
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K':
+			unit = 1 << 10
+		case 'M':
+			unit = 1 << 20
+		case 'G':
+			unit = 1 << 30
+		case 'T':
+			unit = 1 << 40
+		}
+		if unit > 1 {
+			s = s[:n-1]
+		}
+	}
+
+	val, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("invalid size: %v", s)
+	}
+
+	return val * unit, nil
+}
+
+// record_janitor removes segments by retention of record config,
+// the newest segment is kept, it may be recording.
+type record_janitor struct {
+	cfg    *RecordConfig
+	logger log.FieldLogger
+}
+
+func (j *record_janitor) clean() {
+	segs, err := j.cfg.segments()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			j.logger.WithError(err).Warningf("failed to list record segments")
+		}
+		return
+	}
+
+	if len(segs) < 2 {
+		return
+	}
+	segs = segs[:len(segs)-1]
+
+	var total int64
+	for _, seg := range segs {
+		total += seg.Size()
+	}
+
+	now := time.Now()
+	for _, seg := range segs {
+		expired := j.cfg.MaxAge > 0 && now.Sub(seg.ModTime()) > j.cfg.MaxAge
+		exceeded := j.cfg.MaxSize > 0 && total > j.cfg.MaxSize
+		if !expired && !exceeded {
+			break
+		}
+
+		file := filepath.Join(j.cfg.Path, seg.Name())
+		if err = os.Remove(file); err != nil {
+			j.logger.WithError(err).WithField("file", file).Warningf("failed to remove record segment")
+			continue
+		}
+		total -= seg.Size()
+
+		j.logger.WithFields(log.Fields{
+			"file":     file,
+			"expired":  expired,
+			"exceeded": exceeded,
+		}).Debugf("remove record segment")
+	}
+}
+
+func (j *record_janitor) run() {
+	ticker := time.NewTicker(j.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		j.clean()
+		<-ticker.C
+	}
+}
+
+// new_record_config returns nil if record not configured.
+func new_record_config(opt *CameraDriverOption) (*RecordConfig, error) {
+	if opt == nil {
+		return nil, nil
+	}
+
+	c := &RecordConfig{
+		Format:        RECORD_FORMAT_MATROSKA,
+		Name:          RECORD_DEFAULT_NAME,
+		SegmentTime:   RECORD_DEFAULT_SEGMENT_TIME,
+		CheckInterval: RECORD_DEFAULT_CHECK_INTERVAL,
+
+		MaxClipDuration: RECORD_DEFAULT_MAX_CLIP,
+		ChunkSize:       RECORD_DEFAULT_CHUNK_SIZE,
+	}
+
+	if c.Path = opt.GetString("path"); c.Path == "" {
+		return nil, new_invalid_config_error("record.path")
+	}
+
+	if val := opt.GetString("format"); val != "" {
+		if _, ok := record_file_exts[val]; !ok {
+			return nil, new_invalid_config_error("record.format")
+		}
+		c.Format = val
+	}
+
+	if val := opt.GetString("name"); val != "" {
+		if strings.ContainsRune(val, os.PathSeparator) {
+			return nil, new_invalid_config_error("record.name")
+		}
+		c.Name = val
+	}
+
+	if opt.IsSet("segment_time") {
+		if c.SegmentTime = opt.GetDuration("segment_time"); c.SegmentTime < time.Second {
+			return nil, new_invalid_config_error("record.segment_time")
+		}
+	}
+
+	if opt.IsSet("max_age") {
+		if c.MaxAge = opt.GetDuration("max_age"); c.MaxAge < 0 {
+			return nil, new_invalid_config_error("record.max_age")
+		}
+	}
+
+	if val := opt.GetString("max_size"); val != "" {
+		size, err := parse_size(val)
+		if err != nil {
+			return nil, new_invalid_config_error("record.max_size")
+		}
+		c.MaxSize = size
+	}
+
+	if opt.IsSet("check_interval") {
+		if c.CheckInterval = opt.GetDuration("check_interval"); c.CheckInterval <= 0 {
+			return nil, new_invalid_config_error("record.check_interval")
+		}
+	}
+
+	if opt.IsSet("max_clip_duration") {
+		if c.MaxClipDuration = opt.GetDuration("max_clip_duration"); c.MaxClipDuration <= 0 {
+			return nil, new_invalid_config_error("record.max_clip_duration")
+		}
+	}
+
+	if val := opt.GetString("chunk_size"); val != "" {
+		size, err := parse_size(val)
+		if err != nil || size <= 0 {
+			return nil, new_invalid_config_error("record.chunk_size")
+		}
+		c.ChunkSize = size
+	}
+
+	return c, nil
+}
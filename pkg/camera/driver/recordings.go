@@ -0,0 +1,281 @@
+package camera_driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var strftime_fields = map[byte]int{
+	'Y': 4,
+	'm': 2,
+	'd': 2,
+	'H': 2,
+	'M': 2,
+	'S': 2,
+}
+
+// parse_strftime parses local time by strftime template,
+// supports `%Y`, `%m`, `%d`, `%H`, `%M`, `%S` and `%%`.
+func parse_strftime(tmpl, s string) (time.Time, error) {
+	vals := map[byte]int{'m': 1, 'd': 1}
+
+	for i := 0; i < len(tmpl); i++ {
+		if tmpl[i] != '%' || i+1 == len(tmpl) || tmpl[i+1] == '%' {
+			if tmpl[i] == '%' {
+				i++
+			}
+			if len(s) == 0 || s[0] != tmpl[i] {
+				return time.Time{}, fmt.Errorf("unmatched %q", s)
+			}
+			s = s[1:]
+			continue
+		}
+
+		i++
+		n, ok := strftime_fields[tmpl[i]]
+		if !ok || len(s) < n {
+			return time.Time{}, fmt.Errorf("unsupported field %%%c", tmpl[i])
+		}
+
+		val, err := strconv.Atoi(s[:n])
+		if err != nil {
+			return time.Time{}, err
+		}
+		vals[tmpl[i]] = val
+		s = s[n:]
+	}
+
+	if len(s) > 0 {
+		return time.Time{}, fmt.Errorf("unmatched %q", s)
+	}
+
+	return time.Date(vals['Y'], time.Month(vals['m']), vals['d'], vals['H'], vals['M'], vals['S'], 0, time.Local), nil
+}
+
+// recording returns metadata of segment, start time is parsed from file name,
+// estimated by segment time if file name not matched.
+func (c *RecordConfig) recording(info os.FileInfo) *CameraDriverRecording {
+	name := info.Name()
+
+	rec := &CameraDriverRecording{
+		Name:   name,
+		EndAt:  info.ModTime(),
+		Size:   info.Size(),
+		Format: c.Format,
+	}
+
+	start_at, err := parse_strftime(c.Name, strings.TrimSuffix(name, filepath.Ext(name)))
+	if err != nil || start_at.After(rec.EndAt) {
+		start_at = rec.EndAt.Add(-c.SegmentTime)
+	}
+	rec.StartAt = start_at
+
+	return rec
+}
+
+// recordings returns segments overlapped with time range, ordered by start time,
+// zero begin or end means unbounded.
+func (c *RecordConfig) recordings(begin, end time.Time) ([]*CameraDriverRecording, error) {
+	segs, err := c.segments()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var recs []*CameraDriverRecording
+	for _, seg := range segs {
+		rec := c.recording(seg)
+
+		if !begin.IsZero() && rec.EndAt.Before(begin) {
+			continue
+		}
+
+		if !end.IsZero() && rec.StartAt.After(end) {
+			continue
+		}
+
+		recs = append(recs, rec)
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].StartAt.Before(recs[j].StartAt)
+	})
+
+	return recs, nil
+}
+
+func (c *RecordConfig) get_recording(name string) (*CameraDriverRecording, error) {
+	if name == "" || name != filepath.Base(name) || filepath.Ext(name) != "."+c.ext() {
+		return nil, ErrRecordingNotFound
+	}
+
+	info, err := os.Stat(filepath.Join(c.Path, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrRecordingNotFound
+		}
+		return nil, err
+	}
+
+	return c.recording(info), nil
+}
+
+func ffconcat_quote(file string) string {
+	return "'" + strings.Replace(file, "'", `'\''`, -1) + "'"
+}
+
+// write_concat_list writes ffconcat list of segments cut by time range,
+// returns actual time range of list.
+func (c *RecordConfig) write_concat_list(w io.Writer, recs []*CameraDriverRecording, begin, end time.Time) (time.Time, time.Time, error) {
+	start_at, end_at := recs[0].StartAt, recs[len(recs)-1].EndAt
+
+	if _, err := fmt.Fprintln(w, "ffconcat version 1.0"); err != nil {
+		return start_at, end_at, err
+	}
+
+	for _, rec := range recs {
+		if _, err := fmt.Fprintf(w, "file %v\n", ffconcat_quote(filepath.Join(c.Path, rec.Name))); err != nil {
+			return start_at, end_at, err
+		}
+
+		if begin.After(rec.StartAt) {
+			fmt.Fprintf(w, "inpoint %.3f\n", begin.Sub(rec.StartAt).Seconds())
+			start_at = begin
+		}
+
+		if end.Before(rec.EndAt) {
+			fmt.Fprintf(w, "outpoint %.3f\n", end.Sub(rec.StartAt).Seconds())
+			end_at = end
+		}
+	}
+
+	return start_at, end_at, nil
+}
+
+// export_clip cuts segments between begin and end into file of dir by stream copy.
+func (c *RecordConfig) export_clip(binary, dir string, begin, end time.Time, logger log.FieldLogger) (*CameraDriverClip, string, error) {
+	if !end.After(begin) || end.Sub(begin) > c.MaxClipDuration {
+		return nil, "", ErrInvalidClipRange
+	}
+
+	recs, err := c.recordings(begin, end)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(recs) == 0 {
+		return nil, "", ErrRecordingNotFound
+	}
+
+	list := filepath.Join(dir, "list.ffconcat")
+	f, err := os.Create(list)
+	if err != nil {
+		return nil, "", err
+	}
+
+	start_at, end_at, err := c.write_concat_list(f, recs, begin, end)
+	f.Close()
+	if err != nil {
+		return nil, "", err
+	}
+
+	file := filepath.Join(dir, "clip."+c.ext())
+	if err = run_ffmpeg_concat(binary, list, file, c.Format, logger); err != nil {
+		return nil, "", err
+	}
+
+	clip := &CameraDriverClip{
+		Format:  c.Format,
+		StartAt: start_at,
+		EndAt:   end_at,
+	}
+
+	return clip, file, nil
+}
+
+// run_ffmpeg_concat concats files of ffconcat list into file by stream copy.
+func run_ffmpeg_concat(binary, list, file, format string, logger log.FieldLogger) error {
+	args := []string{binary, "-y", "-f", "concat", "-safe", "0", "-i", list, "-c", "copy", "-f", format, file}
+
+	proc := new_framework_process("concat", &FrameworkOption{viper.New()}, logger)
+	if err := proc.start(args); err != nil {
+		return err
+	}
+
+	return <-proc.Wait()
+}
+
+func clip_object(begin, end time.Time) string {
+	layout := "20060102T150405Z"
+	return fmt.Sprintf("clips/%v-%v", begin.UTC().Format(layout), end.UTC().Format(layout))
+}
+
+type clip_manifest struct {
+	Format  string    `json:"format"`
+	Size    int64     `json:"size"`
+	Parts   []string  `json:"parts"`
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+// put_clip_objects uploads clip file as part objects `<object>/<index>` in chunk size,
+// and `<object>/manifest` object lists parts in order, object and parts of clip are renamed in module.
+func put_clip_objects(mdl CameraDriverModule, clip *CameraDriverClip, file string, chunk_size int64) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	clip.Parts = nil
+	clip.Size = 0
+	for {
+		buf, err := ioutil.ReadAll(io.LimitReader(f, chunk_size))
+		if err != nil {
+			return err
+		}
+
+		if len(buf) == 0 {
+			break
+		}
+
+		part := fmt.Sprintf("%v/%06d", clip.Object, len(clip.Parts))
+		if err = mdl.PutObject(part, bytes.NewReader(buf)); err != nil {
+			return err
+		}
+		clip.Parts = append(clip.Parts, mdl.ObjectName(part))
+		clip.Size += int64(len(buf))
+	}
+
+	buf, err := json.Marshal(&clip_manifest{
+		Format:  clip.Format,
+		Size:    clip.Size,
+		Parts:   clip.Parts,
+		StartAt: clip.StartAt,
+		EndAt:   clip.EndAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = mdl.PutObject(clip.Object+"/manifest", bytes.NewReader(buf)); err != nil {
+		return err
+	}
+	clip.Object = mdl.ObjectName(clip.Object)
+
+	return nil
+}
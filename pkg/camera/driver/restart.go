@@ -0,0 +1,117 @@
+package camera_driver
+
+import (
+	"time"
+)
+
+/*
+ * Restart: restart framework when it exits by itself.
+ * Options:
+ *   driver:
+ *   ...
+ *     restart:
+ *       [ policy: <policy> ]  // `never`(default), `on-failure` or `always`.
+ *       [ initial_delay: <duration> ]  // delay before first restart, like `1s`.
+ *       [ max_delay: <duration> ]  // delay upper bound, doubled after each restart, like `1m`.
+ *       [ max_attempts: <count> ]  // give up after attempts, 0 means never give up.
+ *       [ reset_after: <duration> ]  // reset attempts after framework running stable, like `1m`.
+ *   ...
+ */
+
+const (
+	RESTART_POLICY_NEVER      = "never"
+	RESTART_POLICY_ON_FAILURE = "on-failure"
+	RESTART_POLICY_ALWAYS     = "always"
+
+	RESTART_POLICY_DEFAULT_INITIAL_DELAY = 1 * time.Second
+	RESTART_POLICY_DEFAULT_MAX_DELAY     = 1 * time.Minute
+	RESTART_POLICY_DEFAULT_RESET_AFTER   = 1 * time.Minute
+)
+
+type RestartPolicy struct {
+	Policy       string
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	MaxAttempts  int
+	ResetAfter   time.Duration
+}
+
+// ShouldRestart reports framework should be restarted or not,
+// err is framework exit error, attempts is restarted times since last stable running.
+func (p *RestartPolicy) ShouldRestart(err error, attempts int) bool {
+	if p.MaxAttempts > 0 && attempts >= p.MaxAttempts {
+		return false
+	}
+
+	switch p.Policy {
+	case RESTART_POLICY_ALWAYS:
+		return true
+	case RESTART_POLICY_ON_FAILURE:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// Delay returns delay before next restart, exponential backoff by attempts.
+func (p *RestartPolicy) Delay(attempts int) time.Duration {
+	delay := p.InitialDelay
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	return delay
+}
+
+func new_restart_policy(opt *CameraDriverOption) (*RestartPolicy, error) {
+	p := &RestartPolicy{
+		Policy:       RESTART_POLICY_NEVER,
+		InitialDelay: RESTART_POLICY_DEFAULT_INITIAL_DELAY,
+		MaxDelay:     RESTART_POLICY_DEFAULT_MAX_DELAY,
+		ResetAfter:   RESTART_POLICY_DEFAULT_RESET_AFTER,
+	}
+
+	if opt == nil {
+		return p, nil
+	}
+
+	if val := opt.GetString("policy"); val != "" {
+		switch val {
+		case RESTART_POLICY_NEVER, RESTART_POLICY_ON_FAILURE, RESTART_POLICY_ALWAYS:
+			p.Policy = val
+		default:
+			return nil, new_invalid_config_error("restart.policy")
+		}
+	}
+
+	if opt.IsSet("initial_delay") {
+		if p.InitialDelay = opt.GetDuration("initial_delay"); p.InitialDelay <= 0 {
+			return nil, new_invalid_config_error("restart.initial_delay")
+		}
+	}
+
+	if opt.IsSet("max_delay") {
+		if p.MaxDelay = opt.GetDuration("max_delay"); p.MaxDelay < p.InitialDelay {
+			return nil, new_invalid_config_error("restart.max_delay")
+		}
+	}
+
+	if opt.IsSet("max_attempts") {
+		if p.MaxAttempts = opt.GetInt("max_attempts"); p.MaxAttempts < 0 {
+			return nil, new_invalid_config_error("restart.max_attempts")
+		}
+	}
+
+	if opt.IsSet("reset_after") {
+		p.ResetAfter = opt.GetDuration("reset_after")
+	}
+
+	return p, nil
+}
@@ -0,0 +1,178 @@
+package camera_driver
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	rtsp "github.com/nayotta/metathings-component-camera/pkg/camera/rtsp"
+)
+
+/*
+ * RTSP: serve outputs of `rtsp` type by embedded rtsp server, clients like nvr pull from device directly.
+ * Options:
+ *   driver:
+ *   ...
+ *     rtsp:
+ *       [ address: <host>:<port> ]  // listen address, default `:8554`.
+ *       [ host: <host> ]  // host of published urls, default the first non-loopback address.
+ *       [ auth: ]  // authenticate clients, anonymous if absent.
+ *         username: <name>
+ *         [ password: <password> ]
+ *         [ password_env: <name> ]  // environment variable of password, overrides `password`.
+ *         [ scheme: <scheme> ]  // `basic`, `digest` or `any`(default).
+ *     outputs:
+ *       <label>:
+ *         type: rtsp
+ *         [ path: <path> ]  // stream path, default `/<label>`.
+ *         [ playbacks: ]  // same as simple driver.
+ *   ...
+ *
+ * Framework publishes output to embedded server by rtsp over local tcp, ffmpeg framework only.
+ * Clients play by rtp over rtsp interleaved tcp, udp transport is not supported.
+ * Url is published to `rtsp/<label>` object, and `rtsp` object if it is the first output.
+ * Publish url is local, only playbacks could be signed, `sign.publish` should be false.
+ */
+
+const (
+	OUTPUT_TYPE_RTSP = "rtsp"
+
+	RTSP_DEFAULT_ADDRESS = ":8554"
+)
+
+type RtspConfig struct {
+	Address string
+	Host    string
+	Auth    *rtsp.Credential
+}
+
+// default_output_host returns the first non-loopback ipv4 address, or host name.
+func default_output_host() string {
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ip, ok := addr.(*net.IPNet); ok && !ip.IP.IsLoopback() && ip.IP.To4() != nil {
+				return ip.IP.String()
+			}
+		}
+	}
+
+	host, _ := os.Hostname()
+	return host
+}
+
+// rtsp_output_path returns stream path of rtsp output.
+func rtsp_output_path(label string, cfg *CameraDriverOption) string {
+	p := cfg.GetString("path")
+	if p == "" {
+		p = label
+	}
+
+	return path.Clean("/" + p)
+}
+
+func (c *RtspConfig) new_server(logger log.FieldLogger) (*rtsp.Server, error) {
+	return rtsp.NewServer(&rtsp.ServerOption{
+		Address: c.Address,
+		Auth:    c.Auth,
+		Logger:  logger,
+	})
+}
+
+// new_rtsp_output publishes output path on rtsp server, output of start option is ignored.
+func new_rtsp_output(srv *rtsp.Server, host, label string, cfg *CameraDriverOption) (*CameraDriverOutput, error) {
+	p := rtsp_output_path(label, cfg)
+
+	file, err := srv.Publish(p)
+	if err != nil {
+		return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.path", label))
+	}
+
+	u := &url.URL{
+		Scheme: "rtsp",
+		Host:   net.JoinHostPort(host, strconv.Itoa(srv.Port())),
+		Path:   p,
+	}
+
+	out := &CameraDriverOutput{
+		Label:  label,
+		Url:    u.String(),
+		LiveId: path.Base(p),
+		File:   file,
+	}
+
+	ctx := &simple_camera_driver_output_context{
+		Label:    label,
+		LiveId:   out.LiveId,
+		Url:      out.Url,
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Hostname: u.Hostname(),
+		Path:     u.Path,
+	}
+
+	if out.Playbacks, err = render_playbacks(label, cfg.GetStringMapString("playbacks"), ctx); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// apply_rtsp_output sets rtsp muxer of output publishes to embedded server.
+// NOTE: fw should be a cloned option, it will be modified.
+func apply_rtsp_output(fw *CameraDriverOption, label string) {
+	k := "outputs." + label
+
+	fw.Set(k+".format", "rtsp")
+	fw.Set(k+".options.rtsp_transport", "tcp")
+}
+
+func new_rtsp_config(opt *CameraDriverOption) (*RtspConfig, error) {
+	if opt == nil {
+		return nil, nil
+	}
+
+	c := &RtspConfig{
+		Address: RTSP_DEFAULT_ADDRESS,
+		Host:    opt.GetString("host"),
+	}
+
+	if val := opt.GetString("address"); val != "" {
+		if _, _, err := net.SplitHostPort(val); err != nil {
+			return nil, new_invalid_config_error("rtsp.address")
+		}
+		c.Address = val
+	}
+
+	if c.Host == "" {
+		c.Host = default_output_host()
+	}
+
+	if auth := opt.Sub("auth"); auth != nil {
+		c.Auth = &rtsp.Credential{
+			Username: auth.GetString("username"),
+			Password: auth.GetString("password"),
+			Scheme:   auth.GetString("scheme"),
+		}
+
+		if name := auth.GetString("password_env"); name != "" {
+			c.Auth.Password = os.Getenv(name)
+		}
+
+		if c.Auth.Username == "" || c.Auth.Password == "" {
+			return nil, new_invalid_config_error("rtsp.auth")
+		}
+
+		switch c.Auth.Scheme {
+		case "", rtsp.AUTH_SCHEME_ANY, rtsp.AUTH_SCHEME_BASIC, rtsp.AUTH_SCHEME_DIGEST:
+		default:
+			return nil, new_invalid_config_error("rtsp.auth.scheme")
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,188 @@
+package camera_driver
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	opt_helper "github.com/nayotta/metathings/pkg/common/option"
+
+	schedule "github.com/nayotta/metathings-component-camera/pkg/camera/schedule"
+)
+
+/*
+ * Schedule: start and stop camera driver by schedule, wraps any camera driver.
+ *   manual Start or Stop overrides schedule until next scheduled transition.
+ * Options:
+ *   driver:
+ *   ...
+ *     schedule:
+ *       [ timezone: <zone> ]  // IANA time zone of schedule, like `Asia/Shanghai`, default local.
+ *       [ windows: [ "<days> <begin>-<end>", ... ] ]  // weekly windows, like `mon-fri 09:00-18:00`,
+ *                                                     // days is optional, window ends at next day if end is not after begin.
+ *       [ start: [ "<cron>", ... ] ]  // cron expressions to start, like `0 9 * * 1-5`.
+ *       [ stop: [ "<cron>", ... ] ]  // cron expressions to stop, like `0 18 * * 1-5`.
+ *   ...
+ *
+ * Camera is on if any window contains now, overlapped windows are merged,
+ * or the last start cron is not before the last stop cron, stop crons do not stop windows.
+ */
+
+const (
+	// max interval to check schedule, handles clock changes.
+	SCHEDULE_CHECK_INTERVAL = 1 * time.Minute
+)
+
+type ScheduledCameraDriver struct {
+	op_mtx *sync.Mutex
+	drv    CameraDriver
+	sched  *schedule.Schedule
+	logger log.FieldLogger
+
+	override bool
+	next_at  time.Time
+	next_on  bool
+}
+
+func (d *ScheduledCameraDriver) Unwrap() CameraDriver {
+	return d.drv
+}
+
+// sync_override marks override if driver state differs from schedule.
+// NOTE: should be call after `op_mtx` locked!
+func (d *ScheduledCameraDriver) sync_override() {
+	d.override = d.sched.State(time.Now()) != d.drv.State().Started()
+}
+
+func (d *ScheduledCameraDriver) Start(opt *CameraDriverStartOption) ([]*CameraDriverOutput, error) {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	outputs, err := d.drv.Start(opt)
+	if err != nil {
+		return nil, err
+	}
+	d.sync_override()
+
+	return outputs, nil
+}
+
+func (d *ScheduledCameraDriver) Stop() error {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if err := d.drv.Stop(); err != nil {
+		return err
+	}
+	d.sync_override()
+
+	return nil
+}
+
+func (d *ScheduledCameraDriver) State() *CameraDriverState {
+	return d.drv.State()
+}
+
+func (d *ScheduledCameraDriver) Status() *CameraDriverStatus {
+	st := d.drv.Status()
+
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	st.Schedule = &CameraDriverScheduleStatus{
+		Override:         d.override,
+		NextTransitionAt: d.next_at,
+		NextState:        CAMERA_DRIVER_STATE_OFF,
+	}
+	if d.next_on {
+		st.Schedule.NextState = CAMERA_DRIVER_STATE_ON
+	}
+
+	return st
+}
+
+// apply starts or stops driver by scheduled state.
+// NOTE: should be call after `op_mtx` locked!
+func (d *ScheduledCameraDriver) apply(on bool) {
+	d.override = false
+
+	switch st := d.drv.State(); {
+	case on && !st.Started():
+		if _, err := d.drv.Start(&CameraDriverStartOption{}); err != nil {
+			d.logger.WithError(err).Warningf("failed to start camera by schedule")
+			return
+		}
+		d.logger.Infof("camera started by schedule")
+	case !on && st.Started():
+		if err := d.drv.Stop(); err != nil {
+			d.logger.WithError(err).Warningf("failed to stop camera by schedule")
+			return
+		}
+		d.logger.Infof("camera stopped by schedule")
+	}
+}
+
+func (d *ScheduledCameraDriver) run() {
+	d.op_mtx.Lock()
+	d.apply(d.sched.State(time.Now()))
+	d.op_mtx.Unlock()
+
+	for {
+		d.op_mtx.Lock()
+		now := time.Now()
+		if !d.next_at.IsZero() && !now.Before(d.next_at) {
+			d.apply(d.sched.State(now))
+		}
+		d.next_at, d.next_on = d.sched.Next(now)
+
+		wait := SCHEDULE_CHECK_INTERVAL
+		if !d.next_at.IsZero() && d.next_at.Sub(now) < wait {
+			wait = d.next_at.Sub(now)
+		}
+		d.op_mtx.Unlock()
+
+		time.Sleep(wait)
+	}
+}
+
+func new_schedule(opt *CameraDriverOption) (*schedule.Schedule, error) {
+	loc := time.Local
+	if val := opt.GetString("timezone"); val != "" {
+		var err error
+		if loc, err = time.LoadLocation(val); err != nil {
+			return nil, new_invalid_config_error("schedule.timezone")
+		}
+	}
+
+	sched, err := schedule.NewSchedule(loc, opt.GetStringSlice("windows"), opt.GetStringSlice("start"), opt.GetStringSlice("stop"))
+	if err != nil {
+		return nil, new_invalid_config_error("schedule")
+	}
+
+	return sched, nil
+}
+
+func NewScheduledCameraDriver(drv CameraDriver, opt *CameraDriverOption, args ...interface{}) (CameraDriver, error) {
+	var logger log.FieldLogger
+
+	opt_helper.Setopt(map[string]func(key string, val interface{}) error{
+		"logger": opt_helper.ToLogger(&logger),
+		"module": func(string, interface{}) error { return nil },
+	})(args...)
+
+	sched, err := new_schedule(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &ScheduledCameraDriver{
+		op_mtx: new(sync.Mutex),
+		drv:    drv,
+		sched:  sched,
+		logger: logger,
+	}
+	go d.run()
+
+	return d, nil
+}
@@ -0,0 +1,170 @@
+package camera_driver
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+ * Sign: append expiry timestamp and signature to output urls, verified by rtmp or http server with shared secret.
+ * Options:
+ *   driver:
+ *   ...
+ *     outputs:
+ *       <label>:
+ *       ...
+ *         sign:
+ *           scheme: <scheme>  // `hmac`, `secure_link` or `txsecret`.
+ *           [ secret: <secret> ]  // shared secret.
+ *           [ secret_env: <name> ]  // environment variable of shared secret, overrides `secret`.
+ *           [ expire: <duration> ]  // url lifetime, default `24h`.
+ *           [ publish: <bool> ]  // sign publish url, default true.
+ *           [ playbacks: <bool> ]  // sign playback urls, default true.
+ *   ...
+ *
+ * Schemes:
+ *   hmac: `?expires=<unix>&signature=<hex>`, signature is hmac-sha256 of `<expires><path>` by secret,
+ *         verified by `on_publish` and `on_play` hooks of nginx-rtmp or srs.
+ *   secure_link: `?md5=<base64url>&expires=<unix>`, for nginx `secure_link` module configured as
+ *                `secure_link $arg_md5,$arg_expires; secure_link_md5 "$secure_link_expires$uri <secret>";`.
+ *   txsecret: `?txSecret=<hex>&txTime=<HEX>`, txSecret is md5 of `<secret><stream><txTime>`,
+ *             stream is the last path element without extension, txTime is hex of expiry timestamp.
+ *
+ * Publish url is signed again each time framework launches, playback urls are signed when outputs created,
+ * use RotateStreamKey to refresh expired playback urls.
+ */
+
+const (
+	SIGN_SCHEME_HMAC        = "hmac"
+	SIGN_SCHEME_SECURE_LINK = "secure_link"
+	SIGN_SCHEME_TXSECRET    = "txsecret"
+
+	SIGN_DEFAULT_EXPIRE = 24 * time.Hour
+)
+
+type SignConfig struct {
+	Scheme    string
+	Secret    string
+	Expire    time.Duration
+	Publish   bool
+	Playbacks bool
+}
+
+// sign returns url with expiry and signature query parameters, valid until now + expire.
+func (c *SignConfig) sign(raw string, now time.Time) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+
+	expires := now.Add(c.Expire).Unix()
+	q := u.Query()
+
+	switch c.Scheme {
+	case SIGN_SCHEME_HMAC:
+		mac := hmac.New(sha256.New, []byte(c.Secret))
+		fmt.Fprintf(mac, "%d%s", expires, u.Path)
+		q.Set("expires", strconv.FormatInt(expires, 10))
+		q.Set("signature", hex.EncodeToString(mac.Sum(nil)))
+	case SIGN_SCHEME_SECURE_LINK:
+		sum := md5.Sum([]byte(fmt.Sprintf("%d%s %s", expires, u.Path, c.Secret)))
+		q.Set("md5", base64.RawURLEncoding.EncodeToString(sum[:]))
+		q.Set("expires", strconv.FormatInt(expires, 10))
+	case SIGN_SCHEME_TXSECRET:
+		stream := path.Base(u.Path)
+		stream = strings.TrimSuffix(stream, path.Ext(stream))
+		tx_time := strings.ToUpper(strconv.FormatInt(expires, 16))
+		sum := md5.Sum([]byte(c.Secret + stream + tx_time))
+		q.Set("txSecret", hex.EncodeToString(sum[:]))
+		q.Set("txTime", tx_time)
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// apply signs published url and playback urls of output.
+func (c *SignConfig) apply(out *CameraDriverOutput, now time.Time) error {
+	var err error
+
+	// masked url can not be signed.
+	if c.Publish && out.Url == out.File {
+		if out.Url, err = c.sign(out.File, now); err != nil {
+			return err
+		}
+	}
+
+	if c.Playbacks {
+		for name, val := range out.Playbacks {
+			if out.Playbacks[name], err = c.sign(val, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// publish_file returns framework output file of output, signed if required.
+func (c *SignConfig) publish_file(out *CameraDriverOutput, now time.Time) (string, error) {
+	if c == nil || !c.Publish {
+		return out.File, nil
+	}
+
+	return c.sign(out.File, now)
+}
+
+func new_sign_config(label string, opt *CameraDriverOption) (*SignConfig, error) {
+	if opt == nil {
+		return nil, nil
+	}
+
+	key := fmt.Sprintf("outputs.%v.sign", label)
+
+	c := &SignConfig{
+		Expire:    SIGN_DEFAULT_EXPIRE,
+		Publish:   true,
+		Playbacks: true,
+	}
+
+	switch c.Scheme = opt.GetString("scheme"); c.Scheme {
+	case SIGN_SCHEME_HMAC, SIGN_SCHEME_SECURE_LINK, SIGN_SCHEME_TXSECRET:
+	default:
+		return nil, new_invalid_config_error(key + ".scheme")
+	}
+
+	c.Secret = opt.GetString("secret")
+	if name := opt.GetString("secret_env"); name != "" {
+		c.Secret = os.Getenv(name)
+	}
+	if c.Secret == "" {
+		return nil, new_invalid_config_error(key + ".secret")
+	}
+
+	if opt.IsSet("expire") {
+		if c.Expire = opt.GetDuration("expire"); c.Expire <= 0 {
+			return nil, new_invalid_config_error(key + ".expire")
+		}
+	}
+
+	if opt.IsSet("publish") {
+		c.Publish = opt.GetBool("publish")
+	}
+
+	if opt.IsSet("playbacks") {
+		c.Playbacks = opt.GetBool("playbacks")
+	}
+
+	return c, nil
+}
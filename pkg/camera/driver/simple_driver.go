@@ -1,18 +1,25 @@
 package camera_driver
 
 import (
+	"bytes"
+	"crypto/rand"
 	"fmt"
 	"io"
-	"math/rand"
+	"io/ioutil"
 	"net/url"
+	"os"
 	"path"
 	"strings"
 	"sync"
+	"text/template"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	motion "github.com/nayotta/metathings-component-camera/pkg/camera/motion"
+	rtsp "github.com/nayotta/metathings-component-camera/pkg/camera/rtsp"
+	webrtc "github.com/nayotta/metathings-component-camera/pkg/camera/webrtc"
 	opt_helper "github.com/nayotta/metathings/pkg/common/option"
-	component "github.com/nayotta/metathings/pkg/component"
 )
 
 /*
@@ -21,55 +28,232 @@ import (
  * Options:
  *   driver:
  *     name: simple
- *     inputs:
+ *     inputs:  // all inputs are used, ordered by label.
  *       0:
  *         file: <path>  // file path, like `/dev/video0` etc.
- *     outputs:
+ *         [ device: ]  // select v4l2 device by stable attributes instead of file, exclusive with file, see `v4l2.go`.
+ *           ...
+ *         [ check: <bool> ]  // check v4l2 device supports frame size and rate, default true, see `v4l2.go`.
+ *     outputs:  // all outputs are used, ordered by label, each with random live id,
+ *               // published to `rtmp/<label>` object, and `rtmp` object for the first output.
  *       0:
+ *         [ type: <type> ]  // default push to rtmp server, or served from device,
+ *                           // `rtsp` by embedded rtsp server, see `rtsp.go`, `hls` by embedded http server, see `hls.go`,
+ *                           // `webrtc` by NegotiateWebrtc, see `webrtc.go`, `mjpeg` by embedded http server, see `mjpeg.go`.
  *         file_prefix: <path>  // file path prefix, like `rtmp://rtmp-server:1935/path`.
+ *         [ playbacks: ]  // playback url templates, keyed by playback name.
+ *           [ <name>: <template> ]  // like `http://rtmp-server:7001/path/{{.LiveId}}.flv`,
+ *                                   // variables: .Label .LiveId .Url .Scheme .Host .Hostname .Path
+ *         [ sign: ]  // sign urls with shared secret, see `sign.go`.
+ *           ...
+ *     [ work_dir: <dir> ]  // base directory of default working directories, default system temp directory.
+ *     [ restart: ]  // restart framework when it exits by itself, see `restart.go`.
+ *        ...
+ *     [ hotplug: ]  // relaunch framework when device of input plugged back, see `hotplug.go`.
+ *        ...
+ *     [ snapshot: ]  // snapshot settings, see `snapshot.go`.
+ *        ...
+ *     [ record: ]  // record settings, see `record.go`.
+ *        ...
+ *     [ buffer: ]  // pre-event buffer settings, see `buffer.go`.
+ *        ...
+ *     [ motion: ]  // motion detection settings, see `motion.go`.
+ *        ...
+ *     [ rtsp: ]  // embedded rtsp server for outputs of `rtsp` type, see `rtsp.go`.
+ *        ...
+ *     [ http: ]  // embedded http server for outputs of `hls` and `mjpeg` type, see `http.go`.
+ *        ...
+ *     [ webrtc: ]  // webrtc settings for outputs of `webrtc` type, see `webrtc.go`.
+ *        ...
  *     framework:
  *        ...
  */
 
+const (
+	SIMPLE_CAMERA_DRIVER_DEFAULT_AUDIO_CODEC = `aac`
+)
+
 type SimpleCameraDriver struct {
+	*framework_supervisor
+
 	op_mtx *sync.Mutex
-	frmwrk Framework
+
+	// oneshot snapshots are serialized, framework of running one is stopped by launching.
+	oneshot_mtx *sync.Mutex
+	oneshot     Framework
 
 	logger log.FieldLogger
-	mdl    *component.Module
+	mdl    CameraDriverModule
 	opt    *CameraDriverOption
 	st     *CameraDriverState
+
+	new_output simple_camera_driver_output_factory
+	signs      map[string]*SignConfig
+
+	hotplug  *HotplugConfig
+	snap     *SnapshotConfig
+	rec      *RecordConfig
+	buf      *BufferConfig
+	mot      *MotionConfig
+	watcher  *motion_watcher
+	rtsp     *RtspConfig
+	rtsp_srv *rtsp.Server
+	http_srv *http_server
+	hls      map[string]*hls_handler
+	webrtc   map[string]*webrtc.Relay
+	mjpeg    map[string]*mjpeg_hub
+
+	outputs      []*CameraDriverOutput
+	start_opt    *CameraDriverStartOption
+	recording    bool
+	start_at     time.Time
+	disconnected bool
 }
 
 const _LIVEID_LETTERS = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
+// random_strings returns cryptographically random letters, live id is a secret of stream.
 func random_strings(n int) string {
-	buf := make([]byte, n)
-	for i := 0; i < n; i++ {
-		buf[i] = _LIVEID_LETTERS[rand.Intn(len(_LIVEID_LETTERS))]
+	// largest multiple of letters fits in byte, avoids modulo bias.
+	limit := byte(256 - 256%len(_LIVEID_LETTERS))
+
+	buf := make([]byte, 0, n)
+	rnd := make([]byte, n)
+	for len(buf) < n {
+		if _, err := rand.Read(rnd); err != nil {
+			panic(err)
+		}
+
+		for _, b := range rnd {
+			if b < limit && len(buf) < n {
+				buf = append(buf, _LIVEID_LETTERS[int(b)%len(_LIVEID_LETTERS)])
+			}
+		}
 	}
+
 	return string(buf)
 }
 
-func (d *SimpleCameraDriver) Start() error {
-	var err error
-	var output string
+// simple_camera_driver_output_object returns object name of output,
+// `<kind>` object is kept for the first output.
+func simple_camera_driver_output_object(kind, label string) string {
+	return kind + "/" + label
+}
 
-	d.op_mtx.Lock()
-	defer d.op_mtx.Unlock()
+// output_kind returns object kind of output, type of output served from device, `rtmp` for others.
+func (d *SimpleCameraDriver) output_kind(label string) string {
+	if typ := d.opt.GetString(fmt.Sprintf("outputs.%v.type", label)); typ != "" {
+		return typ
+	}
 
-	if d.st == CAMERA_DRIVER_STATE_ON {
-		return ErrNotStartable
+	return "rtmp"
+}
+
+type simple_camera_driver_output_context struct {
+	Label    string
+	LiveId   string
+	Url      string
+	Scheme   string
+	Host     string
+	Hostname string
+	Path     string
+}
+
+func new_simple_camera_driver_output(label, prefix string, playbacks map[string]string) (*CameraDriverOutput, error) {
+	live_id := random_strings(64)
+
+	u, err := url.Parse(prefix + "/" + live_id)
+	if err != nil {
+		return nil, err
 	}
+	u.Path = path.Clean(u.Path)
 
-	drv_ins := d.opt.Sub("inputs")
-	drv_outs := d.opt.Sub("outputs")
-	fw := d.opt.Sub("framework")
+	out := &CameraDriverOutput{
+		Label:  label,
+		Url:    u.String(),
+		LiveId: live_id,
+		File:   u.String(),
+	}
+
+	ctx := &simple_camera_driver_output_context{
+		Label:    label,
+		LiveId:   live_id,
+		Url:      out.Url,
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Hostname: u.Hostname(),
+		Path:     u.Path,
+	}
+
+	if out.Playbacks, err = render_playbacks(label, playbacks, ctx); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// render_playbacks renders playback url templates by context.
+func render_playbacks(label string, playbacks map[string]string, ctx interface{}) (map[string]string, error) {
+	res := map[string]string{}
+
+	for name, text := range playbacks {
+		tmpl, err := template.New(name).Parse(text)
+		if err != nil {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.playbacks.%v", label, name))
+		}
+
+		var buf strings.Builder
+		if err = tmpl.Execute(&buf, ctx); err != nil {
+			return nil, err
+		}
+		res[name] = buf.String()
+	}
+
+	return res, nil
+}
+
+// simple_camera_driver_output_factory creates output by driver output config,
+// override replaces configured output path if not empty.
+type simple_camera_driver_output_factory func(label string, cfg *CameraDriverOption, override string) (*CameraDriverOutput, error)
+
+// new_random_live_id_output appends random live id to `file_prefix`.
+func new_random_live_id_output(label string, cfg *CameraDriverOption, override string) (*CameraDriverOutput, error) {
+	val := cfg.GetString("file_prefix")
+	if override != "" {
+		val = override
+	}
+	if val == "" {
+		return nil, new_invalid_config_error(fmt.Sprintf("framework.outputs.%v.file_prefix", label))
+	}
+
+	return new_simple_camera_driver_output(label, val, cfg.GetStringMapString("playbacks"))
+}
+
+// apply_driver_inputs sets driver input files into framework inputs,
+// input selects device by `device` section is resolved to current device file.
+// NOTE: fw should be a cloned option, it will be modified.
+func apply_driver_inputs(opt, fw *CameraDriverOption) error {
+	drv_ins := opt.Sub("inputs")
+	if drv_ins == nil {
+		return new_invalid_config_error("inputs")
+	}
 
 	for _, k := range drv_ins.NextKeys() {
 		drv_in := drv_ins.Sub(k)
 
 		val := drv_in.GetString("file")
+		if sel := drv_in.Sub("device"); sel != nil {
+			if val != "" {
+				return new_invalid_config_error(fmt.Sprintf("inputs.%v.device", k))
+			}
+
+			dev, err := select_v4l2_device(sel, fmt.Sprintf("inputs.%v.device", k))
+			if err != nil {
+				return err
+			}
+			val = dev.Path
+		}
+
 		if val == "" {
 			return new_invalid_config_error(fmt.Sprintf("framework.inputs.%v.file", k))
 		}
@@ -77,143 +261,1256 @@ func (d *SimpleCameraDriver) Start() error {
 		drv_in_k := "inputs." + k
 		fw.Set(drv_in_k, fw.Get(drv_in_k))
 		fw.Set(fmt.Sprintf("inputs.%v.file", k), val)
+	}
 
-		// TODO(Peer): accept multi-inputs
-		break
+	return nil
+}
+
+// NOTE: fw should be a cloned option, it will be modified.
+func (d *SimpleCameraDriver) apply_start_option(fw *CameraDriverOption, opt *CameraDriverStartOption) {
+	if opt == nil {
+		return
 	}
 
-	for _, k := range drv_outs.NextKeys() {
-		drv_out := drv_outs.Sub(k)
+	if fw_ins := fw.Sub("inputs"); fw_ins != nil {
+		for _, k := range fw_ins.NextKeys() {
+			if opt.FrameSize != "" {
+				fw.Set(fmt.Sprintf("inputs.%v.frame_size", k), opt.FrameSize)
+			}
 
-		val := drv_out.GetString("file_prefix")
-		if val == "" {
-			return new_invalid_config_error(fmt.Sprintf("framework.outputs.%v.file_prefix", k))
+			if opt.FrameRate > 0 {
+				fw.Set(fmt.Sprintf("inputs.%v.frame_rate", k), opt.FrameRate)
+			}
+		}
+	}
+
+	if opt.Codec != "" {
+		fw.Set("video.codec.name", opt.Codec)
+	}
+
+	if opt.BitRate != "" {
+		fw.Set("video.codec.bit_rate", opt.BitRate)
+	}
+
+	if opt.Audio != nil {
+		if !*opt.Audio {
+			fw.Set("audio.disable", true)
+		} else if fw.Sub("audio") == nil {
+			fw.Set("audio.codec.name", SIMPLE_CAMERA_DRIVER_DEFAULT_AUDIO_CODEC)
+		}
+	}
+}
+
+// new_outputs returns outputs of driver config by output factory of driver.
+func (d *SimpleCameraDriver) new_outputs(opt *CameraDriverStartOption) ([]*CameraDriverOutput, error) {
+	var outputs []*CameraDriverOutput
+
+	drv_outs := d.opt.Sub("outputs")
+	if drv_outs == nil {
+		return nil, new_invalid_config_error("outputs")
+	}
+
+	now := time.Now()
+	for i, k := range drv_outs.NextKeys() {
+		override := ""
+		if i == 0 && opt != nil {
+			override = opt.Output
 		}
 
-		u, err := url.Parse(val + "/" + random_strings(64))
+		var out *CameraDriverOutput
+		var err error
+		switch drv_outs.GetString(k + ".type") {
+		case OUTPUT_TYPE_RTSP:
+			out, err = new_rtsp_output(d.rtsp_srv, d.rtsp.Host, k, drv_outs.Sub(k))
+		case OUTPUT_TYPE_HLS:
+			out, err = d.hls[k].new_output(d.http_srv, k, drv_outs.Sub(k))
+		case OUTPUT_TYPE_WEBRTC:
+			out, err = new_webrtc_output(d.webrtc[k], k, drv_outs.Sub(k))
+		case OUTPUT_TYPE_MJPEG:
+			out, err = d.mjpeg[k].new_output(d.http_srv, k, drv_outs.Sub(k))
+		default:
+			out, err = d.new_output(k, drv_outs.Sub(k), override)
+		}
 		if err != nil {
-			return err
+			return nil, err
+		}
+
+		if sign := d.signs[k]; sign != nil {
+			if err = sign.apply(out, now); err != nil {
+				return nil, err
+			}
+		}
+		outputs = append(outputs, out)
+	}
+
+	if len(outputs) == 0 {
+		return nil, new_invalid_config_error("outputs")
+	}
+
+	return outputs, nil
+}
+
+// framework_option returns framework option with outputs of streaming, recording and snapshot.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) framework_option() (*FrameworkOption, error) {
+	fw := d.opt.Sub("framework")
+	if fw == nil {
+		return nil, new_invalid_config_error("framework")
+	}
+	opt := fw.CloneExcept("outputs")
+
+	if err := apply_driver_inputs(d.opt, opt); err != nil {
+		return nil, err
+	}
+
+	if d.st == CAMERA_DRIVER_STATE_ON {
+		d.apply_start_option(opt, d.start_opt)
+
+		now := time.Now()
+		for _, out := range d.outputs {
+			// mjpeg is encoded only when viewers connected.
+			if h, ok := d.mjpeg[out.Label]; ok && !h.active() {
+				continue
+			}
+
+			k := "outputs." + out.Label
+			if fw_out := fw.Sub(k); fw_out != nil {
+				for _, key := range fw_out.AllKeys() {
+					opt.Set(k+"."+key, fw_out.Get(key))
+				}
+			}
+
+			switch d.output_kind(out.Label) {
+			case OUTPUT_TYPE_RTSP:
+				apply_rtsp_output(opt, out.Label)
+			case OUTPUT_TYPE_HLS:
+				d.hls[out.Label].cfg.apply_output(opt, out.Label)
+			case OUTPUT_TYPE_WEBRTC:
+				apply_webrtc_output(opt, out.Label)
+			case OUTPUT_TYPE_MJPEG:
+				d.mjpeg[out.Label].cfg.apply_output(opt, out.Label)
+			}
+
+			// signed again for each launch, signature of last launch may be expired.
+			file, err := d.signs[out.Label].publish_file(out, now)
+			if err != nil {
+				return nil, err
+			}
+			opt.Set(k+".file", file)
 		}
-		u.Path = path.Clean(u.Path)
+	}
+
+	if d.recording {
+		d.rec.apply_output(opt)
+	}
+
+	if d.buf != nil {
+		d.buf.apply_output(opt)
+	}
+
+	if d.mot != nil {
+		d.mot.apply_output(opt)
+	}
+
+	// snapshot is grabbed from running framework, but framework is not launched only for it.
+	if opt.Sub("outputs") != nil {
+		d.snap.apply_live_output(opt)
+	}
+
+	return &FrameworkOption{opt.Viper}, nil
+}
 
-		drv_out_k := "outputs." + k
-		fw.Set(drv_out_k, fw.Get(drv_out_k))
+// active reports framework should be running, buffer and motion keep framework running,
+// snapshot is grabbed from snapshot output of running framework then.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) active() bool {
+	return d.st == CAMERA_DRIVER_STATE_ON || d.recording || d.buf != nil || d.mot != nil
+}
 
-		// TODO(Peer): accpet multi-outputs
-		output = u.String()
-		fw.Set(fmt.Sprintf("outputs.%v.file", k), output)
+func (d *SimpleCameraDriver) Start(opt *CameraDriverStartOption) ([]*CameraDriverOutput, error) {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
 
-		break
+	if d.st == CAMERA_DRIVER_STATE_ON {
+		return nil, ErrNotStartable
 	}
 
-	fw_opt := &FrameworkOption{fw.Viper}
-	d.frmwrk, err = NewFramework(fw.GetString("name"), fw_opt, "logger", d.logger)
+	outputs, err := d.new_outputs(opt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = d.frmwrk.Start()
+	d.st = CAMERA_DRIVER_STATE_ON
+	d.outputs = outputs
+	d.start_opt = opt
+	d.restart_count = 0
+
+	err = d.relaunch()
 	if err != nil {
-		return err
+		d.last_error = err
+		d.clear_streaming()
+		if d.active() {
+			d.relaunch_or_reset()
+		}
+		return nil, err
 	}
 
-	err = d.mdl.PutObjects(map[string]io.Reader{
-		"rtmp":  strings.NewReader(output),
-		"state": strings.NewReader("on"),
-	})
+	err = d.put_output_objects(outputs)
 	if err != nil {
-		return err
+		// streaming is unreachable without published urls, framework is relaunched for remaining outputs.
+		d.last_error = err
+		d.clear_streaming()
+		d.relaunch_or_reset()
+		return nil, err
 	}
 
-	d.st = CAMERA_DRIVER_STATE_ON
-	go func() {
-		err := <-d.frmwrk.Wait()
+	d.start_at = time.Now()
 
-		d.op_mtx.Lock()
-		defer d.op_mtx.Unlock()
+	return outputs, nil
+}
 
-		if d.st == CAMERA_DRIVER_STATE_OFF {
-			return
+// put_output_objects publishes urls of outputs, outputs without url like `webrtc` are skipped.
+func (d *SimpleCameraDriver) put_output_objects(outputs []*CameraDriverOutput) error {
+	objs := map[string]io.Reader{
+		"state": strings.NewReader(d.state().String()),
+	}
+	if outputs[0].Url != "" {
+		objs[d.output_kind(outputs[0].Label)] = strings.NewReader(outputs[0].Url)
+	}
+	for _, out := range outputs {
+		if out.Url != "" {
+			objs[simple_camera_driver_output_object(d.output_kind(out.Label), out.Label)] = strings.NewReader(out.Url)
 		}
+	}
 
-		if err != nil {
-			d.logger.WithError(err).Warningf("failed to wait framework")
+	return d.mdl.PutObjects(objs)
+}
+
+// RotateStreamKey restarts streaming with new outputs, old urls are not published anymore.
+func (d *SimpleCameraDriver) RotateStreamKey() ([]*CameraDriverOutput, error) {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if d.st != CAMERA_DRIVER_STATE_ON {
+		return nil, ErrNotStreaming
+	}
+
+	outputs, err := d.new_outputs(d.start_opt)
+	if err != nil {
+		return nil, err
+	}
+
+	d.outputs = outputs
+	d.restart_count = 0
+
+	err = d.relaunch()
+	if err != nil {
+		d.last_error = err
+		d.clear_streaming()
+		if d.active() {
+			d.relaunch_or_reset()
 		}
+		return nil, err
+	}
 
-		d.reset()
-	}()
+	err = d.put_output_objects(outputs)
+	if err != nil {
+		// streaming is unreachable without published urls, framework is relaunched for remaining outputs.
+		d.last_error = err
+		d.clear_streaming()
+		d.relaunch_or_reset()
+		return nil, err
+	}
 
-	return nil
+	return outputs, nil
 }
 
-func (d *SimpleCameraDriver) Reset() {
-	d.op_mtx.Lock()
-	defer d.op_mtx.Unlock()
+// webrtc_relay returns relay of webrtc output, the first webrtc output if label is empty.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) webrtc_relay(label string) (string, *webrtc.Relay, error) {
+	for _, out := range d.outputs {
+		if d.output_kind(out.Label) != OUTPUT_TYPE_WEBRTC {
+			continue
+		}
 
-	d.reset()
+		if label == "" || label == out.Label {
+			return out.Label, d.webrtc[out.Label], nil
+		}
+	}
+
+	return "", nil, ErrWebrtcOutputNotFound
 }
 
-func (d *SimpleCameraDriver) reset() {
-	var err error
+func (d *SimpleCameraDriver) NegotiateWebrtc(label, offer string) (*CameraDriverWebrtcSession, error) {
+	d.op_mtx.Lock()
+	if d.st != CAMERA_DRIVER_STATE_ON {
+		d.op_mtx.Unlock()
+		return nil, ErrNotStreaming
+	}
 
-	err = d.mdl.RemoveObject("rtmp")
+	label, relay, err := d.webrtc_relay(label)
+	d.op_mtx.Unlock()
 	if err != nil {
-		d.logger.WithError(err).Warningf("failed to remove rtmp object")
+		return nil, err
 	}
 
-	err = d.mdl.PutObject("state", strings.NewReader("off"))
+	// gathering candidates takes a while, driver is not locked.
+	id, answer, err := relay.Negotiate(offer)
 	if err != nil {
-		d.logger.WithError(err).Warningf("failed to write off state")
+		return nil, err
 	}
 
-	d.frmwrk = nil
-	d.st = CAMERA_DRIVER_STATE_OFF
+	// sessions are closed by clear_streaming, session created after that is closed here.
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+	if d.st != CAMERA_DRIVER_STATE_ON {
+		relay.Close(id)
+		return nil, ErrNotStreaming
+	}
+
+	return &CameraDriverWebrtcSession{
+		Id:     id,
+		Label:  label,
+		Answer: answer,
+	}, nil
 }
 
-func (d *SimpleCameraDriver) Stop() error {
+func (d *SimpleCameraDriver) CloseWebrtc(id string) error {
 	d.op_mtx.Lock()
 	defer d.op_mtx.Unlock()
 
-	if d.st == CAMERA_DRIVER_STATE_OFF {
-		return ErrNotStoppable
+	for _, r := range d.webrtc {
+		if r.Has(id) {
+			return r.Close(id)
+		}
 	}
 
-	err := d.frmwrk.Stop()
+	return ErrWebrtcSessionNotFound
+}
+
+// on_mjpeg_demand relaunches framework when mjpeg viewers came or all left.
+func (d *SimpleCameraDriver) on_mjpeg_demand() {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if d.st != CAMERA_DRIVER_STATE_ON {
+		return
+	}
+
+	d.relaunch_or_reset()
+}
+
+// relaunch stops running framework, launches new framework for active outputs,
+// streaming is interrupted shortly when recording started or stopped.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) relaunch() error {
+	d.stop()
+
+	if !d.active() {
+		return nil
+	}
+
+	// device is released by oneshot snapshot before launching.
+	d.stop_oneshot()
+
+	// framework is launched when devices plugged back.
+	if d.hotplug != nil && !d.devices_present() {
+		d.disconnect()
+		return nil
+	}
+
+	fw_opt, err := d.framework_option()
 	if err != nil {
-		d.logger.WithError(err).Debugf("failed to stop camera in framework")
+		return err
 	}
 
-	d.reset()
+	if err = check_v4l2_inputs(d.opt, fw_opt, d.logger); err != nil {
+		return err
+	}
 
-	return nil
+	// framework waits for viewers if all outputs are mjpeg.
+	if fw_opt.Sub("outputs") == nil {
+		return nil
+	}
+
+	if err = os.MkdirAll(d.snap.Path, 0755); err != nil {
+		return err
+	}
+	os.Remove(d.snap.live_file())
+
+	if d.recording {
+		if err = os.MkdirAll(d.rec.Path, 0755); err != nil {
+			return err
+		}
+	}
+
+	if d.buf != nil {
+		if err = os.MkdirAll(d.buf.Path, 0755); err != nil {
+			return err
+		}
+		d.buf.clean()
+	}
+
+	if d.mot != nil {
+		if err = d.mot.make_fifo(); err != nil {
+			return err
+		}
+	}
+
+	if d.st == CAMERA_DRIVER_STATE_ON {
+		for _, out := range d.outputs {
+			if h, ok := d.hls[out.Label]; ok {
+				if err = h.prepare(); err != nil {
+					return err
+				}
+			}
+
+			if h, ok := d.mjpeg[out.Label]; ok {
+				if err = mkfifo(h.cfg.fifo()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	d.fw_opt = fw_opt
+	d.attempts = 0
+
+	return d.launch()
 }
 
-func (d *SimpleCameraDriver) State() *CameraDriverState {
+// relaunch_or_reset relaunches framework for remaining outputs, reset if failed.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) relaunch_or_reset() {
+	if err := d.relaunch(); err != nil {
+		d.logger.WithError(err).Warningf("failed to relaunch framework")
+		d.last_error = err
+		d.reset()
+	}
+}
+
+func (d *SimpleCameraDriver) Reset() {
 	d.op_mtx.Lock()
 	defer d.op_mtx.Unlock()
 
-	return d.st
+	d.reset()
 }
 
-func NewSimpleCameraDriver(opt *CameraDriverOption, args ...interface{}) (CameraDriver, error) {
-	var logger log.FieldLogger
-	var module *component.Module
+// clear_streaming removes output objects and turns streaming off,
+// framework is not touched.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) clear_streaming() {
+	var err error
 
-	opt_helper.Setopt(map[string]func(key string, val interface{}) error{
-		"logger": opt_helper.ToLogger(&logger),
-		"module": component.ToModule(&module),
-	})(args...)
+	objs := []string{"rtmp"}
+	if drv_outs := d.opt.Sub("outputs"); drv_outs != nil {
+		for i, k := range drv_outs.NextKeys() {
+			kind := d.output_kind(k)
+			if kind == OUTPUT_TYPE_WEBRTC {
+				continue
+			}
+			if i == 0 && kind != "rtmp" {
+				objs[0] = kind
+			}
+			objs = append(objs, simple_camera_driver_output_object(kind, k))
 
-	drv := &SimpleCameraDriver{
-		op_mtx: new(sync.Mutex),
-		logger: logger,
-		mdl:    module,
-		opt:    opt,
-		st:     CAMERA_DRIVER_STATE_OFF,
+			if kind == OUTPUT_TYPE_RTSP && d.rtsp_srv != nil {
+				d.rtsp_srv.Unpublish(rtsp_output_path(k, drv_outs.Sub(k)))
+			}
+		}
 	}
-	drv.Reset()
 
-	return drv, nil
+	for _, h := range d.hls {
+		h.reset()
+	}
+
+	for _, r := range d.webrtc {
+		r.CloseSessions()
+	}
+
+	for _, h := range d.mjpeg {
+		h.reset()
+	}
+
+	for _, obj := range objs {
+		err = d.mdl.RemoveObject(obj)
+		if err != nil {
+			d.logger.WithError(err).WithField("object", obj).Warningf("failed to remove output object")
+		}
+	}
+
+	err = d.mdl.PutObject("state", strings.NewReader("off"))
+	if err != nil {
+		d.logger.WithError(err).Warningf("failed to write off state")
+	}
+
+	d.outputs = nil
+	d.start_opt = nil
+	d.start_at = time.Time{}
+	d.st = CAMERA_DRIVER_STATE_OFF
+}
+
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) clear_recording() {
+	if d.rec == nil {
+		return
+	}
+
+	err := d.mdl.PutObject("recording", strings.NewReader("off"))
+	if err != nil {
+		d.logger.WithError(err).Warningf("failed to write off recording")
+	}
+
+	d.recording = false
+}
+
+func (d *SimpleCameraDriver) reset() {
+	d.clear_streaming()
+	d.clear_recording()
+
+	d.frmwrk = nil
+	d.fw_opt = nil
+	d.disconnected = false
+}
+
+func (d *SimpleCameraDriver) Stop() error {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if d.st == CAMERA_DRIVER_STATE_OFF {
+		return ErrNotStoppable
+	}
+
+	d.clear_streaming()
+	if d.active() {
+		d.relaunch_or_reset()
+		return nil
+	}
+
+	d.stop()
+	d.reset()
+
+	return nil
+}
+
+func (d *SimpleCameraDriver) StartRecording() error {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if d.rec == nil {
+		return new_invalid_config_error("record")
+	}
+
+	if d.recording {
+		return ErrNotStartable
+	}
+
+	d.recording = true
+	err := d.relaunch()
+	if err != nil {
+		d.last_error = err
+		d.recording = false
+		if d.active() {
+			d.relaunch_or_reset()
+		}
+		return err
+	}
+
+	err = d.mdl.PutObject("recording", strings.NewReader("on"))
+	if err != nil {
+		d.logger.WithError(err).Warningf("failed to write on recording")
+	}
+
+	return nil
+}
+
+func (d *SimpleCameraDriver) StopRecording() error {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if !d.recording {
+		return ErrNotStoppable
+	}
+
+	d.clear_recording()
+	if d.active() {
+		d.relaunch_or_reset()
+		return nil
+	}
+
+	d.stop()
+
+	return nil
+}
+
+// mark_recording marks the newest segment is recording.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) mark_recording(recs []*CameraDriverRecording) {
+	if !d.recording {
+		return
+	}
+
+	var newest *CameraDriverRecording
+	for _, rec := range recs {
+		if newest == nil || rec.EndAt.After(newest.EndAt) {
+			newest = rec
+		}
+	}
+
+	if newest != nil && time.Since(newest.EndAt) < d.rec.SegmentTime {
+		newest.Recording = true
+	}
+}
+
+func (d *SimpleCameraDriver) ListRecordings(begin, end time.Time) ([]*CameraDriverRecording, error) {
+	if d.rec == nil {
+		return nil, new_invalid_config_error("record")
+	}
+
+	recs, err := d.rec.recordings(begin, end)
+	if err != nil {
+		return nil, err
+	}
+
+	d.op_mtx.Lock()
+	d.mark_recording(recs)
+	d.op_mtx.Unlock()
+
+	return recs, nil
+}
+
+func (d *SimpleCameraDriver) GetRecording(name string) (*CameraDriverRecording, error) {
+	if d.rec == nil {
+		return nil, new_invalid_config_error("record")
+	}
+
+	rec, err := d.rec.get_recording(name)
+	if err != nil {
+		return nil, err
+	}
+
+	recs, err := d.rec.recordings(rec.StartAt, rec.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	d.op_mtx.Lock()
+	d.mark_recording(recs)
+	d.op_mtx.Unlock()
+
+	for _, x := range recs {
+		if x.Name == rec.Name {
+			return x, nil
+		}
+	}
+
+	return rec, nil
+}
+
+// ExportClip cuts recorded segments between begin and end without re-encoding,
+// and uploads clip as part objects.
+func (d *SimpleCameraDriver) ExportClip(begin, end time.Time) (*CameraDriverClip, error) {
+	if d.rec == nil {
+		return nil, new_invalid_config_error("record")
+	}
+
+	dir, err := ioutil.TempDir("", "camera-clip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	clip, file, err := d.rec.export_clip(ffmpeg_binary(d.opt), dir, begin, end, d.logger)
+	if err != nil {
+		return nil, err
+	}
+	clip.Object = clip_object(clip.StartAt, clip.EndAt)
+
+	if err = put_clip_objects(d.mdl, clip, file, d.rec.ChunkSize); err != nil {
+		return nil, err
+	}
+
+	return clip, nil
+}
+
+// TriggerEvent captures pre-event segments from buffer at once,
+// post-event segments are captured and event clip is published in background.
+func (d *SimpleCameraDriver) TriggerEvent(opt *CameraDriverEventOption) (*CameraDriverEvent, error) {
+	if d.buf == nil {
+		return nil, new_invalid_config_error("buffer")
+	}
+
+	var label string
+	pre, post := d.buf.Pre, d.buf.Post
+	if opt != nil {
+		label = opt.Label
+
+		if opt.Pre != nil {
+			pre = *opt.Pre
+		}
+
+		if opt.Post != nil {
+			post = *opt.Post
+		}
+	}
+
+	if pre < 0 || pre > d.buf.Duration || post < 0 || post > d.buf.MaxPost {
+		return nil, ErrInvalidEventDuration
+	}
+
+	d.op_mtx.Lock()
+	running := d.frmwrk != nil
+	d.op_mtx.Unlock()
+
+	if !running {
+		return nil, ErrBufferUnavailable
+	}
+
+	now := time.Now()
+	obj := event_object(now, label)
+	evt := &CameraDriverEvent{
+		Object:    d.mdl.ObjectName(obj),
+		Label:     label,
+		TriggerAt: now,
+		StartAt:   now.Add(-pre),
+		EndAt:     now.Add(post),
+	}
+
+	dir, err := ioutil.TempDir("", "camera-event")
+	if err != nil {
+		return nil, err
+	}
+
+	be := new_buffer_event(d.buf, dir, now, pre)
+	if err = be.capture(time.Time{}); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	go d.publish_event(obj, evt, be)
+
+	return evt, nil
+}
+
+// publish_event publishes event clip to object in namespace of camera.
+func (d *SimpleCameraDriver) publish_event(obj string, evt *CameraDriverEvent, be *buffer_event) {
+	defer os.RemoveAll(be.dir)
+
+	logger := d.logger.WithField("object", evt.Object)
+
+	// wait segment contains post-event end finished, segments are cut at key frames,
+	// may be longer than segment time.
+	deadline := evt.EndAt.Add(4*d.buf.SegmentTime + 10*time.Second)
+	for {
+		time.Sleep(d.buf.SegmentTime)
+
+		if err := be.capture(evt.EndAt); err != nil {
+			logger.WithError(err).Warningf("failed to capture event segments")
+			return
+		}
+
+		if !be.end_at.Before(evt.EndAt) || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	if len(be.files) == 0 {
+		logger.WithError(ErrBufferUnavailable).Warningf("failed to capture event segments")
+		return
+	}
+
+	list := path.Join(be.dir, "list.ffconcat")
+	f, err := os.Create(list)
+	if err != nil {
+		logger.WithError(err).Warningf("failed to create event concat list")
+		return
+	}
+
+	err = be.write_concat_list(f)
+	f.Close()
+	if err != nil {
+		logger.WithError(err).Warningf("failed to write event concat list")
+		return
+	}
+
+	file := path.Join(be.dir, "event."+record_file_exts[d.buf.Format])
+	if err = run_ffmpeg_concat(ffmpeg_binary(d.opt), list, file, d.buf.Format, d.logger); err != nil {
+		logger.WithError(err).Warningf("failed to concat event segments")
+		return
+	}
+
+	clip := &CameraDriverClip{
+		Object:  obj,
+		Format:  d.buf.Format,
+		StartAt: be.start_at,
+		EndAt:   be.end_at,
+	}
+
+	if err = put_clip_objects(d.mdl, clip, file, d.buf.ChunkSize); err != nil {
+		logger.WithError(err).Warningf("failed to put event objects")
+		return
+	}
+
+	logger.WithField("parts", len(clip.Parts)).Infof("event published")
+}
+
+// live_snapshot returns recent frame of snapshot output, nil if unavailable.
+func (d *SimpleCameraDriver) live_snapshot() []byte {
+	buf, _, ok := read_snapshot_file(d.snap.live_file(), time.Now().Add(-2*d.snap.Interval))
+	if !ok {
+		return nil
+	}
+
+	return buf
+}
+
+// record_motion_event stores motion event and snapshot to objects,
+// snapshot is recent frame of snapshot output, or detected frame if unavailable.
+func (d *SimpleCameraDriver) record_motion_event(frame *motion.Frame, res *motion.Result) (*CameraDriverMotionEvent, error) {
+	var err error
+
+	id := res.Timestamp.UTC().Format("20060102T150405.000Z")
+	evt := &CameraDriverMotionEvent{
+		Id:        id,
+		Timestamp: res.Timestamp,
+		Score:     res.Score,
+		Region:    MotionRegion(res.Region),
+		Object:    d.mdl.ObjectName(motion_event_object(id)),
+	}
+
+	if d.mot.Snapshot {
+		content := d.live_snapshot()
+		if content == nil {
+			if content, err = encode_motion_frame(frame); err != nil {
+				return nil, err
+			}
+		}
+
+		obj := motion_snapshot_object(id)
+		if err = d.mdl.PutObject(obj, bytes.NewReader(content)); err != nil {
+			return nil, err
+		}
+		evt.Snapshot = d.mdl.ObjectName(obj)
+	}
+
+	buf, err := marshal_motion_event(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = d.mdl.PutObject(motion_event_object(id), bytes.NewReader(buf)); err != nil {
+		return nil, err
+	}
+
+	d.logger.WithFields(log.Fields{
+		"object": evt.Object,
+		"score":  evt.Score,
+	}).Infof("motion detected")
+
+	return evt, nil
+}
+
+func (d *SimpleCameraDriver) ListMotionEvents(begin, end time.Time) ([]*CameraDriverMotionEvent, error) {
+	if d.watcher == nil {
+		return nil, ErrMotionUnavailable
+	}
+
+	return d.watcher.list(begin, end), nil
+}
+
+func (d *SimpleCameraDriver) Snapshot(opt *CameraDriverSnapshotOption) (*CameraDriverSnapshot, error) {
+	cfg, err := d.snap.with_option(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := d.grab_snapshot(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &CameraDriverSnapshot{
+		Content:   content,
+		Format:    cfg.Format,
+		Timestamp: time.Now(),
+	}
+
+	if cfg.Store {
+		obj := snapshot_object(snap.Timestamp, snap.Format)
+		if err = d.mdl.PutObject(obj, bytes.NewReader(content)); err != nil {
+			return nil, err
+		}
+		snap.Object = d.mdl.ObjectName(obj)
+	}
+
+	return snap, nil
+}
+
+// grab_snapshot grabs frame from running framework, or runs framework once if not running,
+// like camera off, waiting for restart or mjpeg viewers.
+func (d *SimpleCameraDriver) grab_snapshot(cfg *SnapshotConfig) ([]byte, error) {
+	d.op_mtx.Lock()
+	running := d.frmwrk != nil
+	d.op_mtx.Unlock()
+
+	if !running {
+		return d.grab_oneshot_snapshot(cfg)
+	}
+
+	return d.grab_live_snapshot(cfg)
+}
+
+// grab_live_snapshot waits frame of snapshot output of running framework.
+func (d *SimpleCameraDriver) grab_live_snapshot(cfg *SnapshotConfig) ([]byte, error) {
+	// frame should be written in last intervals, wait next frame if stale.
+	since := time.Now().Add(-2 * cfg.Interval)
+	_, img, err := wait_snapshot_file(cfg.live_file(), since, cfg.Timeout, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.encode(img)
+}
+
+// grab_oneshot_snapshot runs framework once for snapshot when framework not running,
+// driver is not locked while waiting frame, oneshot framework is stopped if framework launched meanwhile.
+func (d *SimpleCameraDriver) grab_oneshot_snapshot(cfg *SnapshotConfig) ([]byte, error) {
+	d.oneshot_mtx.Lock()
+	defer d.oneshot_mtx.Unlock()
+
+	d.op_mtx.Lock()
+	if d.frmwrk != nil {
+		d.op_mtx.Unlock()
+		return d.grab_live_snapshot(cfg)
+	}
+	frmwrk, file, err := d.start_oneshot_snapshot(cfg)
+	if err != nil {
+		d.op_mtx.Unlock()
+		return nil, err
+	}
+	d.oneshot = frmwrk
+	errch := frmwrk.Wait()
+	d.op_mtx.Unlock()
+
+	defer func() {
+		d.op_mtx.Lock()
+		d.stop_oneshot()
+		d.op_mtx.Unlock()
+	}()
+
+	// file removed before start, any file is new.
+	content, _, err := wait_snapshot_file(file, time.Time{}, cfg.Timeout, errch)
+	if err != nil {
+		d.op_mtx.Lock()
+		running := d.frmwrk != nil
+		d.op_mtx.Unlock()
+
+		// oneshot framework is stopped by launching framework, grab from it instead.
+		if running {
+			return d.grab_live_snapshot(cfg)
+		}
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// start_oneshot_snapshot starts framework writes one frame to returned file.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) start_oneshot_snapshot(cfg *SnapshotConfig) (Framework, string, error) {
+	fw := d.opt.Sub("framework")
+	if fw == nil {
+		return nil, "", new_invalid_config_error("framework")
+	}
+	fw = fw.Clone()
+
+	if err := apply_driver_inputs(d.opt, fw); err != nil {
+		return nil, "", err
+	}
+
+	if err := os.MkdirAll(cfg.Path, 0755); err != nil {
+		return nil, "", err
+	}
+	file := path.Join(cfg.Path, "oneshot."+snapshot_file_ext(cfg.Format))
+	os.Remove(file)
+
+	fw_opt := cfg.oneshot_option(fw, file)
+	frmwrk, err := NewFramework(fw_opt.GetString("name"), fw_opt, "logger", d.logger)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err = frmwrk.Start(); err != nil {
+		return nil, "", err
+	}
+
+	return frmwrk, file, nil
+}
+
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) stop_oneshot() {
+	if d.oneshot == nil {
+		return
+	}
+
+	d.oneshot.Stop()
+	d.oneshot = nil
+}
+
+// state returns disconnected if streaming waits for devices plugged back.
+// NOTE: should be call after `op_mtx` locked!
+func (d *SimpleCameraDriver) state() *CameraDriverState {
+	if d.st == CAMERA_DRIVER_STATE_ON && d.disconnected {
+		return CAMERA_DRIVER_STATE_DISCONNECTED
+	}
+
+	return d.st
+}
+
+func (d *SimpleCameraDriver) State() *CameraDriverState {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	return d.state()
+}
+
+func (d *SimpleCameraDriver) Status() *CameraDriverStatus {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	st := &CameraDriverStatus{
+		State:          d.state(),
+		Outputs:        d.outputs,
+		Recording:      d.recording,
+		StartAt:        d.start_at,
+		RestartCount:   d.restart_count,
+		LastError:      d.last_error,
+		LastExitReason: d.last_exit_reason,
+	}
+
+	if d.frmwrk != nil {
+		fst := d.frmwrk.Status()
+		st.Pid = fst.Pid
+		st.Stats = fst.Stats
+		st.Logs = fst.Logs
+	}
+
+	return st
+}
+
+func new_simple_camera_driver(opt *CameraDriverOption, new_output simple_camera_driver_output_factory, logger log.FieldLogger, module CameraDriverModule) (*SimpleCameraDriver, error) {
+	rst, err := new_restart_policy(opt.Sub("restart"))
+	if err != nil {
+		return nil, err
+	}
+
+	hotplug, err := new_hotplug_config(opt.Sub("hotplug"), opt.Sub("inputs"))
+	if err != nil {
+		return nil, err
+	}
+
+	work_dir := opt.GetString("work_dir")
+
+	snap, err := new_snapshot_config(opt.Sub("snapshot"), work_dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := new_record_config(opt.Sub("record"))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := new_buffer_config(opt.Sub("buffer"), work_dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mot, err := new_motion_config(opt.Sub("motion"), work_dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rtsp_cfg, err := new_rtsp_config(opt.Sub("rtsp"))
+	if err != nil {
+		return nil, err
+	}
+
+	http_cfg, err := new_http_config(opt.Sub("http"))
+	if err != nil {
+		return nil, err
+	}
+
+	webrtc_cfg, err := new_webrtc_config(opt.Sub("webrtc"))
+	if err != nil {
+		return nil, err
+	}
+
+	signs := map[string]*SignConfig{}
+	hls_cfgs := map[string]*HlsConfig{}
+	mjpeg_cfgs := map[string]*MjpegConfig{}
+	if drv_outs := opt.Sub("outputs"); drv_outs != nil {
+		for _, k := range drv_outs.NextKeys() {
+			if signs[k], err = new_sign_config(k, drv_outs.Sub(k+".sign")); err != nil {
+				return nil, err
+			}
+
+			typ := drv_outs.GetString(k + ".type")
+			switch typ {
+			case "":
+			case OUTPUT_TYPE_RTSP:
+				if rtsp_cfg == nil {
+					return nil, new_invalid_config_error("rtsp")
+				}
+			case OUTPUT_TYPE_HLS:
+				if http_cfg == nil {
+					return nil, new_invalid_config_error("http")
+				}
+
+				if hls_cfgs[k], err = new_hls_config(k, drv_outs.Sub(k), work_dir); err != nil {
+					return nil, err
+				}
+			case OUTPUT_TYPE_WEBRTC:
+			case OUTPUT_TYPE_MJPEG:
+				if http_cfg == nil {
+					return nil, new_invalid_config_error("http")
+				}
+
+				if mjpeg_cfgs[k], err = new_mjpeg_config(k, drv_outs.Sub(k), work_dir); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.type", k))
+			}
+
+			// publish url of output served from device is local, only playbacks could be signed.
+			if typ != "" && signs[k] != nil && signs[k].Publish {
+				return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.sign.publish", k))
+			}
+		}
+	}
+
+	var rtsp_srv *rtsp.Server
+	if rtsp_cfg != nil {
+		if rtsp_srv, err = rtsp_cfg.new_server(logger); err != nil {
+			return nil, err
+		}
+	}
+
+	// mjpeg demand is changed by viewers after driver created.
+	var drv *SimpleCameraDriver
+
+	var http_srv *http_server
+	hls := map[string]*hls_handler{}
+	mjpeg := map[string]*mjpeg_hub{}
+	if http_cfg != nil {
+		if http_srv, err = http_cfg.new_server(logger); err != nil {
+			return nil, err
+		}
+
+		for k, cfg := range hls_cfgs {
+			hls[k] = &hls_handler{cfg: cfg}
+			http_srv.handle(cfg.Path+"/", hls[k])
+		}
+
+		for k, cfg := range mjpeg_cfgs {
+			if mjpeg[k], err = new_mjpeg_hub(cfg, logger.WithField("output", k), func() { drv.on_mjpeg_demand() }); err != nil {
+				return nil, err
+			}
+			http_srv.handle(cfg.Path, mjpeg[k])
+		}
+	}
+
+	relays := map[string]*webrtc.Relay{}
+	if drv_outs := opt.Sub("outputs"); drv_outs != nil {
+		for _, k := range drv_outs.NextKeys() {
+			if drv_outs.GetString(k+".type") != OUTPUT_TYPE_WEBRTC {
+				continue
+			}
+
+			if relays[k], err = webrtc_cfg.new_relay(logger.WithField("output", k)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	drv = &SimpleCameraDriver{
+		op_mtx:      new(sync.Mutex),
+		oneshot_mtx: new(sync.Mutex),
+		new_output:  new_output,
+		signs:       signs,
+		rtsp:        rtsp_cfg,
+		rtsp_srv:    rtsp_srv,
+		http_srv:    http_srv,
+		hls:         hls,
+		webrtc:      relays,
+		mjpeg:       mjpeg,
+		logger:      logger,
+		mdl:         module,
+		opt:         opt,
+		hotplug:     hotplug,
+		snap:        snap,
+		rec:         rec,
+		buf:         buf,
+		mot:         mot,
+		st:          CAMERA_DRIVER_STATE_OFF,
+	}
+	drv.framework_supervisor = &framework_supervisor{
+		mtx:        drv.op_mtx,
+		logger:     logger,
+		rst:        rst,
+		supervised: drv.active,
+		new_option: drv.framework_option,
+		on_give_up: drv.reset,
+	}
+	if hotplug != nil {
+		drv.on_exit = drv.on_framework_exit
+		drv.exit_delay = HOTPLUG_SETTLE_DELAY
+	}
+	drv.Reset()
+
+	if rec != nil {
+		janitor := &record_janitor{cfg: rec, logger: logger}
+		go janitor.run()
+	}
+
+	if mot != nil {
+		if err = mot.make_fifo(); err != nil {
+			return nil, err
+		}
+
+		detector, err := motion.NewDetector(mot.Detector)
+		if err != nil {
+			return nil, err
+		}
+
+		drv.watcher = new_motion_watcher(mot, detector, logger, drv.record_motion_event)
+		go drv.watcher.run()
+	}
+
+	if hotplug != nil {
+		go drv.watch_devices()
+	}
+
+	drv.op_mtx.Lock()
+	if drv.active() {
+		drv.relaunch_or_reset()
+	}
+	drv.op_mtx.Unlock()
+
+	return drv, nil
+}
+
+func NewSimpleCameraDriver(opt *CameraDriverOption, args ...interface{}) (CameraDriver, error) {
+	var logger log.FieldLogger
+	var module CameraDriverModule
+
+	opt_helper.Setopt(map[string]func(key string, val interface{}) error{
+		"logger": opt_helper.ToLogger(&logger),
+		"module": ToCameraDriverModule(&module),
+	})(args...)
+
+	return new_simple_camera_driver(opt, new_random_live_id_output, logger, module)
 }
 
 var register_simple_camera_driver_once sync.Once
@@ -0,0 +1,203 @@
+package camera_driver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	component "github.com/nayotta/metathings/pkg/component"
+)
+
+type test_driver_module struct{}
+
+func (m *test_driver_module) Kernel() *component.Kernel                      { return nil }
+func (m *test_driver_module) PutObject(name string, content io.Reader) error { return nil }
+func (m *test_driver_module) PutObjects(objects map[string]io.Reader) error  { return nil }
+func (m *test_driver_module) RemoveObject(name string) error                 { return nil }
+func (m *test_driver_module) ObjectName(name string) string                  { return name }
+
+// test_failed_driver_module fails to put objects.
+type test_failed_driver_module struct {
+	test_driver_module
+}
+
+func (m *test_failed_driver_module) PutObjects(objects map[string]io.Reader) error {
+	return errors.New("put objects failed")
+}
+
+func new_test_logger() log.FieldLogger {
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	return logger
+}
+
+// new_test_binary writes shell script as executable file in dir.
+func new_test_binary(t *testing.T, dir, name, script string) string {
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func new_test_simple_driver(t *testing.T, dir, binary, extra string) *SimpleCameraDriver {
+	opt := new_test_driver_option(t, fmt.Sprintf(`
+work_dir: %v
+inputs:
+  0:
+    file: %v
+outputs:
+  0:
+    file_prefix: rtmp://localhost/live
+framework:
+  name: ffmpeg
+  binary: %v
+  inputs:
+    0:
+      format: lavfi
+  outputs:
+    0:
+      format: flv
+  video:
+    codec:
+      name: libx264
+%v`, dir, filepath.Join(dir, "input"), binary, extra))
+
+	drv, err := new_simple_camera_driver(opt, new_random_live_id_output, new_test_logger(), &test_driver_module{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return drv
+}
+
+func TestSnapshotWhenStreaming(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	binary := new_test_binary(t, dir, "ffmpeg", "exec sleep 10\n")
+	drv := new_test_simple_driver(t, dir, binary, `
+snapshot:
+  format: png
+  timeout: 1s
+`)
+
+	if _, err = drv.Start(nil); err != nil {
+		t.Fatal(err)
+	}
+	defer drv.Stop()
+
+	drv.op_mtx.Lock()
+	file := drv.fw_opt.GetString("outputs." + SNAPSHOT_OUTPUT_LABEL + ".file")
+	drv.op_mtx.Unlock()
+	if file != drv.snap.live_file() {
+		t.Fatalf("snapshot output file %q, want %q", file, drv.snap.live_file())
+	}
+
+	var buf bytes.Buffer
+	if err = jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := drv.Snapshot(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(snap.Content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != SNAPSHOT_FORMAT_PNG || img.Bounds().Dx() != 4 {
+		t.Errorf("snapshot %v %v, want png of live frame", format, img.Bounds())
+	}
+}
+
+func TestOneshotSnapshotReleasesLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// framework never writes frame.
+	binary := new_test_binary(t, dir, "ffmpeg", "exec sleep 10\n")
+	drv := new_test_simple_driver(t, dir, binary, `
+snapshot:
+  timeout: 1s
+`)
+
+	errch := make(chan error, 1)
+	go func() {
+		_, err := drv.Snapshot(nil)
+		errch <- err
+	}()
+
+	// wait oneshot framework started.
+	time.Sleep(200 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		drv.State()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatalf("driver locked while waiting snapshot")
+	}
+
+	select {
+	case err = <-errch:
+		if err != ErrSnapshotTimeout {
+			t.Errorf("error %v, want %v", err, ErrSnapshotTimeout)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("snapshot not timeout")
+	}
+}
+
+func TestStartRollbackOnPublishFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-driver-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	binary := new_test_binary(t, dir, "ffmpeg", "exec sleep 10\n")
+	drv := new_test_simple_driver(t, dir, binary, "")
+	drv.mdl = &test_failed_driver_module{}
+
+	if _, err = drv.Start(nil); err == nil {
+		t.Fatalf("started without published outputs")
+	}
+
+	drv.op_mtx.Lock()
+	defer drv.op_mtx.Unlock()
+
+	if drv.st != CAMERA_DRIVER_STATE_OFF {
+		t.Errorf("state %v, want off", drv.st)
+	}
+
+	if drv.frmwrk != nil {
+		t.Errorf("framework keeps running")
+	}
+}
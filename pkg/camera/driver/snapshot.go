@@ -0,0 +1,312 @@
+package camera_driver
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+/*
+ * Snapshot: grab single frame as jpeg or png.
+ *   grab from snapshot output of running pipeline when framework running, like camera on, recording, buffer or motion,
+ *   or run framework once when framework not running.
+ * Options:
+ *   driver:
+ *   ...
+ *     snapshot:
+ *       [ format: <format> ]  // `jpeg`(default) or `png`.
+ *       [ frame_size: <width>x<height> ]  // scale frame, default input frame size.
+ *       [ quality: <1-100> ]  // jpeg quality, default 90.
+ *       [ store: <bool> ]  // store snapshot to `snapshots/<timestamp>.<ext>` object by default.
+ *       [ interval: <duration> ]  // frame interval of snapshot output, like `1s`, costs jpeg encoding of every interval.
+ *       [ path: <dir> ]  // directory for frame files, default `<work dir>/camera-snapshot`.
+ *       [ timeout: <duration> ]  // wait frame timeout, like `10s`.
+ *   ...
+ */
+
+const (
+	SNAPSHOT_FORMAT_JPEG = "jpeg"
+	SNAPSHOT_FORMAT_PNG  = "png"
+
+	SNAPSHOT_DEFAULT_QUALITY  = 90
+	SNAPSHOT_DEFAULT_INTERVAL = 1 * time.Second
+	SNAPSHOT_DEFAULT_TIMEOUT  = 10 * time.Second
+
+	// framework output label of snapshot output.
+	SNAPSHOT_OUTPUT_LABEL = "_snapshot"
+
+	snapshot_poll_interval = 100 * time.Millisecond
+)
+
+type SnapshotConfig struct {
+	Format    string
+	FrameSize string
+	Quality   int
+	Store     bool
+	Interval  time.Duration
+	Path      string
+	Timeout   time.Duration
+}
+
+func snapshot_file_ext(format string) string {
+	if format == SNAPSHOT_FORMAT_JPEG {
+		return "jpg"
+	}
+
+	return format
+}
+
+func snapshot_object(ts time.Time, format string) string {
+	return fmt.Sprintf("snapshots/%v.%v", ts.UTC().Format("20060102T150405.000Z"), snapshot_file_ext(format))
+}
+
+func parse_frame_size(s string) (int, int, error) {
+	ss := strings.SplitN(s, "x", 2)
+	if len(ss) != 2 {
+		return 0, 0, ErrInvalidFrameSize
+	}
+
+	w, err := strconv.Atoi(ss[0])
+	if err != nil || w <= 0 {
+		return 0, 0, ErrInvalidFrameSize
+	}
+
+	h, err := strconv.Atoi(ss[1])
+	if err != nil || h <= 0 {
+		return 0, 0, ErrInvalidFrameSize
+	}
+
+	return w, h, nil
+}
+
+func (c *SnapshotConfig) live_file() string {
+	return filepath.Join(c.Path, "live.jpg")
+}
+
+// with_option returns config for one snapshot, overridden by opt.
+func (c *SnapshotConfig) with_option(opt *CameraDriverSnapshotOption) (*SnapshotConfig, error) {
+	x := *c
+
+	if opt == nil {
+		return &x, nil
+	}
+
+	if opt.Format != "" {
+		x.Format = opt.Format
+	}
+
+	if opt.FrameSize != "" {
+		x.FrameSize = opt.FrameSize
+	}
+
+	if opt.Quality > 0 {
+		x.Quality = opt.Quality
+	}
+
+	if opt.Store != nil {
+		x.Store = *opt.Store
+	}
+
+	if err := x.validate(); err != nil {
+		return nil, err
+	}
+
+	return &x, nil
+}
+
+func (c *SnapshotConfig) validate() error {
+	switch c.Format {
+	case SNAPSHOT_FORMAT_JPEG, SNAPSHOT_FORMAT_PNG:
+	default:
+		return ErrInvalidSnapshotFormat
+	}
+
+	if c.FrameSize != "" {
+		if _, _, err := parse_frame_size(c.FrameSize); err != nil {
+			return err
+		}
+	}
+
+	if c.Quality < 1 || c.Quality > 100 {
+		return ErrInvalidSnapshotQuality
+	}
+
+	return nil
+}
+
+// apply_live_output adds snapshot output to framework option,
+// frames are written to live file in best quality, scaled and encoded on snapshot.
+// NOTE: fw should be a cloned option, it will be modified.
+func (c *SnapshotConfig) apply_live_output(fw *CameraDriverOption) {
+	k := "outputs." + SNAPSHOT_OUTPUT_LABEL
+
+	fw.Set(k+".format", "image2")
+	fw.Set(k+".file", c.live_file())
+	fw.Set(k+".options.update", "1")
+	fw.Set(k+".video.codec.name", "mjpeg")
+	fw.Set(k+".video.frame_rate", fmt.Sprintf("1000/%d", c.Interval/time.Millisecond))
+	fw.Set(k+".video.quality", 100)
+}
+
+// oneshot_option returns framework option writes one frame to file,
+// outputs of fw are replaced by snapshot output.
+func (c *SnapshotConfig) oneshot_option(fw *CameraDriverOption, file string) *FrameworkOption {
+	v := fw.CloneExcept("outputs").Viper
+
+	k := "outputs." + SNAPSHOT_OUTPUT_LABEL
+	v.Set(k+".format", "image2")
+	v.Set(k+".file", file)
+	v.Set(k+".options.update", "1")
+	v.Set(k+".video.frames", 1)
+
+	switch c.Format {
+	case SNAPSHOT_FORMAT_JPEG:
+		v.Set(k+".video.codec.name", "mjpeg")
+		v.Set(k+".video.quality", c.Quality)
+	case SNAPSHOT_FORMAT_PNG:
+		v.Set(k+".video.codec.name", "png")
+	}
+
+	if c.FrameSize != "" {
+		v.Set(k+".video.frame_size", c.FrameSize)
+	}
+
+	return &FrameworkOption{v}
+}
+
+// encode scales image to frame size and encodes it in format.
+func (c *SnapshotConfig) encode(img image.Image) ([]byte, error) {
+	if c.FrameSize != "" {
+		w, h, err := parse_frame_size(c.FrameSize)
+		if err != nil {
+			return nil, err
+		}
+
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+		img = dst
+	}
+
+	var buf bytes.Buffer
+	switch c.Format {
+	case SNAPSHOT_FORMAT_JPEG:
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: c.Quality}); err != nil {
+			return nil, err
+		}
+	case SNAPSHOT_FORMAT_PNG:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidSnapshotFormat
+	}
+
+	return buf.Bytes(), nil
+}
+
+// read_snapshot_file returns content and decoded image of file modified since,
+// ok is false when file is not ready, like not exists, stale or partial written.
+func read_snapshot_file(file string, since time.Time) ([]byte, image.Image, bool) {
+	info, err := os.Stat(file)
+	if err != nil || info.ModTime().Before(since) {
+		return nil, nil, false
+	}
+
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return nil, nil, false
+	}
+
+	return buf, img, true
+}
+
+// wait_snapshot_file waits file ready until timeout or framework exited.
+func wait_snapshot_file(file string, since time.Time, timeout time.Duration, errch <-chan error) ([]byte, image.Image, error) {
+	ticker := time.NewTicker(snapshot_poll_interval)
+	defer ticker.Stop()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		if buf, img, ok := read_snapshot_file(file, since); ok {
+			return buf, img, nil
+		}
+
+		select {
+		case err := <-errch:
+			if buf, img, ok := read_snapshot_file(file, since); ok {
+				return buf, img, nil
+			}
+			if err == nil {
+				err = ErrSnapshotUnavailable
+			}
+			return nil, nil, err
+		case <-timer.C:
+			return nil, nil, ErrSnapshotTimeout
+		case <-ticker.C:
+		}
+	}
+}
+
+func new_snapshot_config(opt *CameraDriverOption, work_dir string) (*SnapshotConfig, error) {
+	c := &SnapshotConfig{
+		Format:   SNAPSHOT_FORMAT_JPEG,
+		Quality:  SNAPSHOT_DEFAULT_QUALITY,
+		Interval: SNAPSHOT_DEFAULT_INTERVAL,
+		Path:     filepath.Join(default_work_dir(work_dir), "camera-snapshot"),
+		Timeout:  SNAPSHOT_DEFAULT_TIMEOUT,
+	}
+
+	if opt == nil {
+		return c, nil
+	}
+
+	if val := opt.GetString("format"); val != "" {
+		c.Format = val
+	}
+
+	c.FrameSize = opt.GetString("frame_size")
+
+	if opt.IsSet("quality") {
+		c.Quality = opt.GetInt("quality")
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, new_invalid_config_error("snapshot")
+	}
+
+	c.Store = opt.GetBool("store")
+
+	if opt.IsSet("interval") {
+		if c.Interval = opt.GetDuration("interval"); c.Interval < time.Millisecond {
+			return nil, new_invalid_config_error("snapshot.interval")
+		}
+	}
+
+	if val := opt.GetString("path"); val != "" {
+		c.Path = val
+	}
+
+	if opt.IsSet("timeout") {
+		if c.Timeout = opt.GetDuration("timeout"); c.Timeout <= 0 {
+			return nil, new_invalid_config_error("snapshot.timeout")
+		}
+	}
+
+	return c, nil
+}
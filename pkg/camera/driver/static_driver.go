@@ -0,0 +1,206 @@
+package camera_driver
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	opt_helper "github.com/nayotta/metathings/pkg/common/option"
+)
+
+/*
+ * Driver: static
+ *   for rtmp servers require stable stream key, like youtube, nginx-rtmp, srs or cloud ingest,
+ *   output file is rendered by template, other settings are same as simple driver.
+ * Options:
+ *   driver:
+ *     name: static
+ *     inputs:  // same as simple driver.
+ *       ...
+ *     outputs:  // all outputs are used, ordered by label,
+ *               // published to `rtmp/<label>` object, and `rtmp` object for the first output.
+ *       0:
+ *         file: <template>  // output file template, like `rtmp://a.rtmp.youtube.com/live2/{{.StreamKey}}`,
+ *                           // variables: .Label .DeviceId .ModuleName .Date .Time .StreamKey
+ *         [ stream_key: <key> ]  // secret stream key.
+ *         [ stream_key_env: <name> ]  // environment variable of secret stream key, overrides `stream_key`.
+ *         [ publish_stream_key: <bool> ]  // publish url with stream key, default false, masked by `****`.
+ *         [ playbacks: ]  // playback url templates, keyed by playback name.
+ *           [ <name>: <template> ]  // variables: .Label .LiveId .Url .Scheme .Host .Hostname .Path
+ *                                   // and variables of output file, .StreamKey is masked if not published.
+ *     ...  // same as simple driver, `output` of start request replaces file of the first output as literal url.
+ *
+ * `.Date` is local date like `20060102`, `.Time` is local time, like `{{.Time.Format "150405"}}`.
+ * `.LiveId` is the last path element of published url.
+ */
+
+const (
+	STATIC_STREAM_KEY_MASK = "****"
+)
+
+type static_camera_driver_output_context struct {
+	Label      string
+	DeviceId   string
+	ModuleName string
+	Date       string
+	Time       time.Time
+	StreamKey  string
+
+	LiveId   string
+	Url      string
+	Scheme   string
+	Host     string
+	Hostname string
+	Path     string
+}
+
+type static_camera_driver_output_factory struct {
+	mtx         sync.Mutex
+	mdl         CameraDriverModule
+	device_id   string
+	module_name string
+}
+
+// load_module loads device id and module name of module, cached after first success.
+func (f *static_camera_driver_output_factory) load_module() (string, string, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.device_id == "" {
+		mdl, err := f.mdl.Kernel().Show()
+		if err != nil {
+			return "", "", err
+		}
+		f.device_id, f.module_name = mdl.GetDeviceId(), mdl.GetName()
+	}
+
+	return f.device_id, f.module_name, nil
+}
+
+func render_static_output_file(label, text string, ctx *static_camera_driver_output_context) (string, error) {
+	tmpl, err := template.New(label).Parse(text)
+	if err != nil {
+		return "", new_invalid_config_error(fmt.Sprintf("outputs.%v.file", label))
+	}
+
+	var buf strings.Builder
+	if err = tmpl.Execute(&buf, ctx); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (f *static_camera_driver_output_factory) new_output(label string, cfg *CameraDriverOption, override string) (*CameraDriverOutput, error) {
+	var err error
+
+	// override of start request is literal url, only config is rendered with stream key.
+	text := cfg.GetString("file")
+	if override != "" {
+		text = ""
+	} else if text == "" {
+		return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.file", label))
+	}
+
+	playbacks := cfg.GetStringMapString("playbacks")
+
+	now := time.Now()
+	ctx := &static_camera_driver_output_context{
+		Label:     label,
+		Date:      now.Format("20060102"),
+		Time:      now,
+		StreamKey: cfg.GetString("stream_key"),
+	}
+
+	if name := cfg.GetString("stream_key_env"); name != "" {
+		if ctx.StreamKey = os.Getenv(name); ctx.StreamKey == "" {
+			return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.stream_key_env", label))
+		}
+	}
+
+	if strings.Contains(text, ".StreamKey") && ctx.StreamKey == "" {
+		return nil, new_invalid_config_error(fmt.Sprintf("outputs.%v.stream_key", label))
+	}
+
+	// module is loaded from server only if referred.
+	refs := text
+	for _, x := range playbacks {
+		refs += x
+	}
+	if strings.Contains(refs, ".DeviceId") || strings.Contains(refs, ".ModuleName") {
+		if ctx.DeviceId, ctx.ModuleName, err = f.load_module(); err != nil {
+			return nil, err
+		}
+	}
+
+	file, pub := override, override
+	if text != "" {
+		if file, err = render_static_output_file(label, text, ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.GetBool("publish_stream_key") && ctx.StreamKey != "" {
+		ctx.StreamKey = STATIC_STREAM_KEY_MASK
+	}
+
+	if text != "" {
+		if pub, err = render_static_output_file(label, text, ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	u, err := url.Parse(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &CameraDriverOutput{
+		Label:  label,
+		Url:    pub,
+		LiveId: path.Base(u.Path),
+		File:   file,
+	}
+
+	ctx.LiveId = out.LiveId
+	ctx.Url = out.Url
+	ctx.Scheme = u.Scheme
+	ctx.Host = u.Host
+	ctx.Hostname = u.Hostname()
+	ctx.Path = u.Path
+
+	if out.Playbacks, err = render_playbacks(label, playbacks, ctx); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func NewStaticCameraDriver(opt *CameraDriverOption, args ...interface{}) (CameraDriver, error) {
+	var logger log.FieldLogger
+	var module CameraDriverModule
+
+	opt_helper.Setopt(map[string]func(key string, val interface{}) error{
+		"logger": opt_helper.ToLogger(&logger),
+		"module": ToCameraDriverModule(&module),
+	})(args...)
+
+	fty := &static_camera_driver_output_factory{mdl: module}
+
+	return new_simple_camera_driver(opt, fty.new_output, logger, module)
+}
+
+var register_static_camera_driver_once sync.Once
+
+func init() {
+	register_static_camera_driver_once.Do(func() {
+		register_camera_driver_factory("static", NewStaticCameraDriver)
+	})
+}
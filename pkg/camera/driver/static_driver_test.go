@@ -0,0 +1,84 @@
+package camera_driver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func new_test_driver_option(t *testing.T, yaml string) *CameraDriverOption {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatal(err)
+	}
+
+	return &CameraDriverOption{v}
+}
+
+const test_static_output_config = `
+file: rtmp://a.rtmp.youtube.com/live2/{{.StreamKey}}
+stream_key: secret
+playbacks:
+  hls: https://example.com/{{.LiveId}}.m3u8
+`
+
+func TestStaticOutputRendersStreamKey(t *testing.T) {
+	fty := &static_camera_driver_output_factory{}
+
+	out, err := fty.new_output("0", new_test_driver_option(t, test_static_output_config), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.File != "rtmp://a.rtmp.youtube.com/live2/secret" {
+		t.Errorf("file %q", out.File)
+	}
+
+	if out.Url != "rtmp://a.rtmp.youtube.com/live2/"+STATIC_STREAM_KEY_MASK {
+		t.Errorf("url %q", out.Url)
+	}
+
+	if out.Playbacks["hls"] != "https://example.com/"+STATIC_STREAM_KEY_MASK+".m3u8" {
+		t.Errorf("playback %q", out.Playbacks["hls"])
+	}
+}
+
+func TestStaticOutputOverrideIsLiteral(t *testing.T) {
+	fty := &static_camera_driver_output_factory{}
+
+	for _, override := range []string{
+		"rtmp://attacker.example.com/live/{{.StreamKey}}",
+		"rtmp://attacker.example.com/live/{{with .StreamKey}}{{.}}{{end}}",
+		"rtmp://localhost/live/test",
+	} {
+		out, err := fty.new_output("0", new_test_driver_option(t, test_static_output_config), override)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out.File != override || out.Url != override {
+			t.Errorf("override %q: file %q, url %q", override, out.File, out.Url)
+		}
+
+		for _, x := range append([]string{out.File, out.Url}, out.Playbacks["hls"]) {
+			if strings.Contains(x, "secret") {
+				t.Errorf("override %q: stream key expanded: %q", override, x)
+			}
+		}
+	}
+}
+
+func TestStaticOutputRequiresStreamKey(t *testing.T) {
+	fty := &static_camera_driver_output_factory{}
+
+	opt := new_test_driver_option(t, `file: rtmp://a.rtmp.youtube.com/live2/{{.StreamKey}}`)
+	if _, err := fty.new_output("0", opt, ""); err == nil {
+		t.Errorf("missing stream key accepted")
+	}
+
+	if _, err := fty.new_output("0", opt, "rtmp://localhost/live/test"); err != nil {
+		t.Errorf("override without stream key: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package camera_driver
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// framework_supervisor launches framework of driver, and restarts it by restart policy after it exited by itself,
+// shared by drivers run one framework at a time, hooks are called with `op_mtx` of driver locked.
+type framework_supervisor struct {
+	// op_mtx of driver.
+	mtx    *sync.Mutex
+	logger log.FieldLogger
+	rst    *RestartPolicy
+
+	// supervised reports framework should be running.
+	supervised func() bool
+	// new_option returns option of restarted framework.
+	new_option func() (*FrameworkOption, error)
+	// on_exit handles framework exited or failed to restart, returns true if handled and not restarted.
+	on_exit func(err error) bool
+	// on_give_up is called when framework will not be restarted.
+	on_give_up func()
+	// delay handling framework exited with error.
+	exit_delay time.Duration
+
+	frmwrk  Framework
+	fw_opt  *FrameworkOption
+	session int
+
+	launch_at        time.Time
+	attempts         int
+	restart_count    int
+	last_error       error
+	last_exit_reason string
+}
+
+// stop stops running framework and waits it exited to release device,
+// pending restart is canceled by session.
+// NOTE: should be call after `op_mtx` locked!
+func (s *framework_supervisor) stop() {
+	s.session++
+	s.fw_opt = nil
+
+	// framework is nil when waiting for restart.
+	if s.frmwrk == nil {
+		return
+	}
+
+	frmwrk := s.frmwrk
+	s.frmwrk = nil
+
+	if err := frmwrk.Stop(); err != nil {
+		s.logger.WithError(err).Debugf("failed to stop framework")
+		return
+	}
+	<-frmwrk.Wait()
+}
+
+// launch starts framework of fw_opt and supervises it.
+// NOTE: should be call after `op_mtx` locked!
+func (s *framework_supervisor) launch() error {
+	frmwrk, err := NewFramework(s.fw_opt.GetString("name"), s.fw_opt, "logger", s.logger)
+	if err != nil {
+		return err
+	}
+
+	if err = frmwrk.Start(); err != nil {
+		return err
+	}
+
+	s.frmwrk = frmwrk
+	s.launch_at = time.Now()
+	go s.supervise(frmwrk, frmwrk.Wait())
+
+	return nil
+}
+
+func (s *framework_supervisor) supervise(frmwrk Framework, errch <-chan error) {
+	err := <-errch
+
+	if err != nil && s.exit_delay > 0 {
+		time.Sleep(s.exit_delay)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.supervised() || s.frmwrk != frmwrk {
+		return
+	}
+	s.frmwrk = nil
+
+	if s.on_exit != nil && s.on_exit(err) {
+		return
+	}
+
+	if err != nil {
+		s.logger.WithError(err).Warningf("failed to wait framework")
+		s.last_error = err
+		s.last_exit_reason = err.Error()
+	} else {
+		s.last_exit_reason = "exited"
+	}
+
+	if time.Since(s.launch_at) >= s.rst.ResetAfter {
+		s.attempts = 0
+	}
+
+	s.restart(err)
+}
+
+// NOTE: should be call after `op_mtx` locked!
+func (s *framework_supervisor) restart(err error) {
+	if !s.rst.ShouldRestart(err, s.attempts) {
+		s.on_give_up()
+		return
+	}
+
+	delay := s.rst.Delay(s.attempts)
+	s.attempts++
+	session := s.session
+
+	s.logger.WithFields(log.Fields{
+		"delay":    delay,
+		"attempts": s.attempts,
+	}).Infof("restart framework")
+
+	time.AfterFunc(delay, func() {
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+
+		if !s.supervised() || s.session != session {
+			return
+		}
+
+		s.restart_count++
+
+		// option is renewed, like signed output urls and next frame index.
+		fw_opt, err := s.new_option()
+		if err == nil {
+			s.fw_opt = fw_opt
+			err = s.launch()
+		}
+		if err != nil {
+			if s.on_exit != nil && s.on_exit(err) {
+				return
+			}
+
+			s.logger.WithError(err).Warningf("failed to restart framework")
+			s.last_error = err
+			s.last_exit_reason = err.Error()
+			s.restart(err)
+		}
+	})
+}
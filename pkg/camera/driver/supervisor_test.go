@@ -0,0 +1,217 @@
+package camera_driver
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var test_framework_starts int32
+
+// test_framework exits with error of `exit` option at once, or runs until stopped if `hold` option set.
+type test_framework struct {
+	opt   *FrameworkOption
+	errch chan error
+}
+
+func (f *test_framework) Start() error {
+	atomic.AddInt32(&test_framework_starts, 1)
+
+	if !f.opt.GetBool("hold") {
+		f.errch <- errors.New(f.opt.GetString("exit"))
+	}
+
+	return nil
+}
+
+func (f *test_framework) Stop() error {
+	select {
+	case f.errch <- nil:
+	default:
+	}
+
+	return nil
+}
+
+func (f *test_framework) Wait() <-chan error {
+	return f.errch
+}
+
+func (f *test_framework) Status() *FrameworkStatus {
+	return &FrameworkStatus{}
+}
+
+func init() {
+	register_framework_factory("test", func(opt *FrameworkOption, args ...interface{}) (Framework, error) {
+		return &test_framework{opt: opt, errch: make(chan error, 1)}, nil
+	})
+}
+
+func new_test_supervisor(t *testing.T, fw string, rst *RestartPolicy) (*framework_supervisor, chan struct{}) {
+	atomic.StoreInt32(&test_framework_starts, 0)
+
+	gave_up := make(chan struct{}, 1)
+	s := &framework_supervisor{
+		mtx:        new(sync.Mutex),
+		logger:     new_test_logger(),
+		rst:        rst,
+		supervised: func() bool { return true },
+		new_option: func() (*FrameworkOption, error) {
+			return new_test_framework_option(t, fw), nil
+		},
+		on_give_up: func() { gave_up <- struct{}{} },
+	}
+
+	return s, gave_up
+}
+
+func launch_test_supervisor(t *testing.T, s *framework_supervisor) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var err error
+	if s.fw_opt, err = s.new_option(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.launch(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSupervisorRestart(t *testing.T) {
+	s, gave_up := new_test_supervisor(t, "name: test\nexit: broken pipe", &RestartPolicy{
+		Policy:       RESTART_POLICY_ON_FAILURE,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		MaxAttempts:  2,
+		ResetAfter:   time.Minute,
+	})
+	launch_test_supervisor(t, s)
+
+	select {
+	case <-gave_up:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("supervisor not gave up")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if n := atomic.LoadInt32(&test_framework_starts); n != 3 {
+		t.Errorf("framework started %v times, want 3", n)
+	}
+
+	if s.restart_count != 2 || s.attempts != 2 {
+		t.Errorf("restart count %v, attempts %v, want 2", s.restart_count, s.attempts)
+	}
+
+	if s.last_exit_reason != "broken pipe" || s.frmwrk != nil {
+		t.Errorf("last exit reason %q, framework %v", s.last_exit_reason, s.frmwrk)
+	}
+}
+
+func TestSupervisorExitHandled(t *testing.T) {
+	s, gave_up := new_test_supervisor(t, "name: test\nexit: no such device", &RestartPolicy{
+		Policy:       RESTART_POLICY_ALWAYS,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		ResetAfter:   time.Minute,
+	})
+
+	handled := make(chan error, 1)
+	s.on_exit = func(err error) bool {
+		handled <- err
+		return true
+	}
+	launch_test_supervisor(t, s)
+
+	select {
+	case err := <-handled:
+		if err == nil || err.Error() != "no such device" {
+			t.Errorf("handled error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("exit not handled")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	select {
+	case <-gave_up:
+		t.Errorf("handled exit gave up")
+	default:
+	}
+
+	if n := atomic.LoadInt32(&test_framework_starts); n != 1 {
+		t.Errorf("framework started %v times, want 1", n)
+	}
+}
+
+func TestSupervisorStopCancelsRestart(t *testing.T) {
+	s, _ := new_test_supervisor(t, "name: test\nexit: broken pipe", &RestartPolicy{
+		Policy:       RESTART_POLICY_ALWAYS,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     100 * time.Millisecond,
+		ResetAfter:   time.Minute,
+	})
+	launch_test_supervisor(t, s)
+
+	// wait framework exited and restart pending.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.mtx.Lock()
+		attempts := s.attempts
+		if attempts > 0 {
+			s.stop()
+		}
+		s.mtx.Unlock()
+
+		if attempts > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("restart not pending")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&test_framework_starts); n != 1 {
+		t.Errorf("framework started %v times after stopped, want 1", n)
+	}
+}
+
+func TestSupervisorStop(t *testing.T) {
+	s, gave_up := new_test_supervisor(t, "name: test\nhold: true", &RestartPolicy{
+		Policy:       RESTART_POLICY_ALWAYS,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     time.Millisecond,
+		ResetAfter:   time.Minute,
+	})
+	launch_test_supervisor(t, s)
+
+	s.mtx.Lock()
+	s.stop()
+	frmwrk, fw_opt := s.frmwrk, s.fw_opt
+	s.mtx.Unlock()
+
+	if frmwrk != nil || fw_opt != nil {
+		t.Errorf("framework %v, option %v after stopped", frmwrk, fw_opt)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	select {
+	case <-gave_up:
+		t.Errorf("stopped framework handled as exited")
+	default:
+	}
+
+	if n := atomic.LoadInt32(&test_framework_starts); n != 1 {
+		t.Errorf("framework started %v times, want 1", n)
+	}
+}
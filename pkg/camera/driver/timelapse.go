@@ -0,0 +1,314 @@
+package camera_driver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+/*
+ * Timelapse: capture frame every interval to local sequence directory,
+ *   sequence is assembled into mp4 periodically or by AssembleTimelapse and uploaded as clip.
+ * Options:
+ *   driver:
+ *   ...
+ *     timelapse:
+ *       [ path: <dir> ]  // directory of sequences and state file, default `<work dir>/metathings-camera/timelapse`.
+ *       [ interval: <duration> ]  // capture interval, default `10s`.
+ *       [ frame_size: <width>x<height> ]  // optional, scale frames.
+ *       [ quality: <1-100> ]  // jpeg quality of frames, default 90.
+ *       [ fps: <rate> ]  // frame rate of assembled video, default 25.
+ *       [ codec: <codec> ]  // video codec of assembled video, default `libx264`.
+ *       [ assemble_interval: <duration> ]  // assemble periodically, 0(default) means only by AssembleTimelapse.
+ *       [ keep_frames: <bool> ]  // keep frames after assembled, default false.
+ *       [ chunk_size: <size> ]  // object size of assembled clip parts, default `4M`.
+ *   ...
+ *
+ * Frames are stored as `<path>/<sequence>/<index>.jpg`, current sequence is resumed after restart.
+ * Assembled video is uploaded as clip parts under `timelapses/<sequence>` object.
+ */
+
+const (
+	TIMELAPSE_DEFAULT_INTERVAL = 10 * time.Second
+	TIMELAPSE_DEFAULT_FPS      = 25
+	TIMELAPSE_DEFAULT_CODEC    = "libx264"
+
+	// framework output label of timelapse output.
+	TIMELAPSE_OUTPUT_LABEL = "_timelapse"
+
+	timelapse_frame_ext      = ".jpg"
+	timelapse_state_file     = "timelapse.json"
+	timelapse_assembled_file = "assembled"
+)
+
+type TimelapseConfig struct {
+	Path             string
+	Interval         time.Duration
+	FrameSize        string
+	Quality          int
+	Fps              int
+	Codec            string
+	AssembleInterval time.Duration
+	KeepFrames       bool
+	ChunkSize        int64
+}
+
+func (c *TimelapseConfig) sequence_dir(seq string) string {
+	return filepath.Join(c.Path, seq)
+}
+
+// apply_output adds timelapse output writes frames of sequence from index to framework option.
+// NOTE: fw should be a cloned option, it will be modified.
+func (c *TimelapseConfig) apply_output(fw *CameraDriverOption, seq string, index int) {
+	k := "outputs." + TIMELAPSE_OUTPUT_LABEL
+
+	fw.Set(k+".format", "image2")
+	fw.Set(k+".file", filepath.Join(c.sequence_dir(seq), "%06d"+timelapse_frame_ext))
+	fw.Set(k+".options.start_number", strconv.Itoa(index))
+	fw.Set(k+".video.codec.name", "mjpeg")
+	fw.Set(k+".video.frame_rate", fmt.Sprintf("1000/%v", c.Interval.Nanoseconds()/int64(time.Millisecond)))
+	fw.Set(k+".video.quality", c.Quality)
+	if c.FrameSize != "" {
+		fw.Set(k+".video.frame_size", c.FrameSize)
+	}
+}
+
+// frames returns frame files of sequence, ordered by index.
+func (c *TimelapseConfig) frames(seq string) ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(c.sequence_dir(seq))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var frms []os.FileInfo
+	for _, info := range infos {
+		if info.IsDir() || filepath.Ext(info.Name()) != timelapse_frame_ext {
+			continue
+		}
+		frms = append(frms, info)
+	}
+
+	return frms, nil
+}
+
+// next_index returns index of next frame of sequence, resumes after the last frame.
+func (c *TimelapseConfig) next_index(seq string) (int, error) {
+	frms, err := c.frames(seq)
+	if err != nil {
+		return 0, err
+	}
+
+	index := 1
+	for _, frm := range frms {
+		n, err := strconv.Atoi(strings.TrimSuffix(frm.Name(), timelapse_frame_ext))
+		if err == nil && n >= index {
+			index = n + 1
+		}
+	}
+
+	return index, nil
+}
+
+// closed_sequences returns sequences not assembled except current one, ordered by name.
+func (c *TimelapseConfig) closed_sequences(current string) ([]string, error) {
+	infos, err := ioutil.ReadDir(c.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var seqs []string
+	for _, info := range infos {
+		if !info.IsDir() || info.Name() == current {
+			continue
+		}
+
+		if _, err = os.Stat(filepath.Join(c.sequence_dir(info.Name()), timelapse_assembled_file)); err == nil {
+			continue
+		}
+		seqs = append(seqs, info.Name())
+	}
+
+	return seqs, nil
+}
+
+// assemble encodes frames of sequence into mp4 file of dir.
+func (c *TimelapseConfig) assemble(binary, dir, seq string, logger log.FieldLogger) (*CameraDriverTimelapse, string, error) {
+	frms, err := c.frames(seq)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(frms) == 0 {
+		return nil, "", ErrTimelapseEmpty
+	}
+	sort_file_infos_by_mod_time(frms)
+
+	file := filepath.Join(dir, "timelapse.mp4")
+	args := []string{
+		binary, "-y",
+		"-framerate", strconv.Itoa(c.Fps),
+		"-pattern_type", "glob",
+		"-i", filepath.Join(c.sequence_dir(seq), "*"+timelapse_frame_ext),
+		"-c:v", c.Codec,
+		"-pix_fmt", "yuv420p",
+		"-movflags", "+faststart",
+		"-f", RECORD_FORMAT_MP4, file,
+	}
+
+	proc := new_framework_process("timelapse", &FrameworkOption{viper.New()}, logger)
+	if err = proc.start(args); err != nil {
+		return nil, "", err
+	}
+
+	if err = <-proc.Wait(); err != nil {
+		return nil, "", err
+	}
+
+	tl := &CameraDriverTimelapse{
+		Sequence: seq,
+		Frames:   len(frms),
+		Clip: &CameraDriverClip{
+			Object:  timelapse_object(seq),
+			Format:  RECORD_FORMAT_MP4,
+			StartAt: frms[0].ModTime(),
+			EndAt:   frms[len(frms)-1].ModTime(),
+		},
+	}
+
+	return tl, file, nil
+}
+
+// finish removes frames of assembled sequence, or marks it assembled if frames kept.
+func (c *TimelapseConfig) finish(seq string) error {
+	if !c.KeepFrames {
+		return os.RemoveAll(c.sequence_dir(seq))
+	}
+
+	return ioutil.WriteFile(filepath.Join(c.sequence_dir(seq), timelapse_assembled_file), nil, 0644)
+}
+
+func timelapse_object(seq string) string {
+	return "timelapses/" + seq
+}
+
+func new_timelapse_sequence() string {
+	return time.Now().UTC().Format("20060102T150405.000Z")
+}
+
+// timelapse_state is persisted to resume capture after restart.
+type timelapse_state struct {
+	Sequence  string `json:"sequence"`
+	Capturing bool   `json:"capturing"`
+}
+
+func (c *TimelapseConfig) load_state() (*timelapse_state, error) {
+	st := &timelapse_state{}
+
+	buf, err := ioutil.ReadFile(filepath.Join(c.Path, timelapse_state_file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return nil, err
+	}
+
+	if err = json.Unmarshal(buf, st); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+func (c *TimelapseConfig) save_state(st *timelapse_state) error {
+	buf, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(c.Path, timelapse_state_file)
+	if err = ioutil.WriteFile(file+".tmp", buf, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(file+".tmp", file)
+}
+
+func new_timelapse_config(opt *CameraDriverOption, work_dir string) (*TimelapseConfig, error) {
+	c := &TimelapseConfig{
+		Path:      filepath.Join(default_work_dir(work_dir), "metathings-camera", "timelapse"),
+		Interval:  TIMELAPSE_DEFAULT_INTERVAL,
+		Quality:   SNAPSHOT_DEFAULT_QUALITY,
+		Fps:       TIMELAPSE_DEFAULT_FPS,
+		Codec:     TIMELAPSE_DEFAULT_CODEC,
+		ChunkSize: RECORD_DEFAULT_CHUNK_SIZE,
+	}
+
+	if opt == nil {
+		return c, nil
+	}
+
+	if val := opt.GetString("path"); val != "" {
+		c.Path = val
+	}
+
+	if opt.IsSet("interval") {
+		if c.Interval = opt.GetDuration("interval"); c.Interval < 100*time.Millisecond {
+			return nil, new_invalid_config_error("timelapse.interval")
+		}
+	}
+
+	if val := opt.GetString("frame_size"); val != "" {
+		if _, _, err := parse_frame_size(val); err != nil {
+			return nil, new_invalid_config_error("timelapse.frame_size")
+		}
+		c.FrameSize = val
+	}
+
+	if opt.IsSet("quality") {
+		if c.Quality = opt.GetInt("quality"); c.Quality < 1 || c.Quality > 100 {
+			return nil, new_invalid_config_error("timelapse.quality")
+		}
+	}
+
+	if opt.IsSet("fps") {
+		if c.Fps = opt.GetInt("fps"); c.Fps <= 0 {
+			return nil, new_invalid_config_error("timelapse.fps")
+		}
+	}
+
+	if val := opt.GetString("codec"); val != "" {
+		c.Codec = val
+	}
+
+	if opt.IsSet("assemble_interval") {
+		if c.AssembleInterval = opt.GetDuration("assemble_interval"); c.AssembleInterval < 0 {
+			return nil, new_invalid_config_error("timelapse.assemble_interval")
+		}
+	}
+
+	c.KeepFrames = opt.GetBool("keep_frames")
+
+	if val := opt.GetString("chunk_size"); val != "" {
+		size, err := parse_size(val)
+		if err != nil || size <= 0 {
+			return nil, new_invalid_config_error("timelapse.chunk_size")
+		}
+		c.ChunkSize = size
+	}
+
+	return c, nil
+}
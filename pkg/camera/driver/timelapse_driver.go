@@ -0,0 +1,395 @@
+package camera_driver
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	opt_helper "github.com/nayotta/metathings/pkg/common/option"
+)
+
+/*
+ * Driver: timelapse
+ * Options:
+ *   driver:
+ *     name: timelapse
+ *     inputs:  // inputs like simple driver.
+ *       ...
+ *     [ work_dir: <dir> ]  // base directory of default working directories, default system temp directory.
+ *     [ timelapse: ]  // timelapse settings, see `timelapse.go`.
+ *       ...
+ *     [ restart: ]  // restart settings, see `restart.go`.
+ *       ...
+ *     framework:
+ *       name: <framework>  // framework name, `ffmpeg` or `gstreamer`.
+ *       ...  // framework settings without outputs.
+ *
+ * Start and Stop control capture, frames are captured to current sequence until assembled.
+ */
+
+type TimelapseCameraDriver struct {
+	*framework_supervisor
+
+	op_mtx       *sync.Mutex
+	assemble_mtx *sync.Mutex
+	logger       log.FieldLogger
+	mdl          CameraDriverModule
+	opt          *CameraDriverOption
+	tl           *TimelapseConfig
+
+	st       *CameraDriverState
+	sequence string
+	start_at time.Time
+}
+
+// NOTE: should be call after `op_mtx` locked!
+func (d *TimelapseCameraDriver) capturing() bool {
+	return d.st == CAMERA_DRIVER_STATE_ON
+}
+
+// NOTE: should be call after `op_mtx` locked!
+func (d *TimelapseCameraDriver) save_state() error {
+	return d.tl.save_state(&timelapse_state{
+		Sequence:  d.sequence,
+		Capturing: d.st == CAMERA_DRIVER_STATE_ON,
+	})
+}
+
+// framework_option returns framework option captures frames to current sequence.
+// NOTE: should be call after `op_mtx` locked!
+func (d *TimelapseCameraDriver) framework_option() (*FrameworkOption, error) {
+	fw_opt := d.opt.Sub("framework")
+	if fw_opt == nil {
+		return nil, new_invalid_config_error("framework")
+	}
+	fw_opt = fw_opt.CloneExcept("outputs")
+
+	if err := apply_driver_inputs(d.opt, fw_opt); err != nil {
+		return nil, err
+	}
+
+	index, err := d.tl.next_index(d.sequence)
+	if err != nil {
+		return nil, err
+	}
+	d.tl.apply_output(fw_opt, d.sequence, index)
+
+	return &FrameworkOption{fw_opt.Viper}, nil
+}
+
+// relaunch restarts framework for current sequence, starts new sequence if none.
+// NOTE: should be call after `op_mtx` locked!
+func (d *TimelapseCameraDriver) relaunch() error {
+	d.stop()
+
+	if d.sequence == "" {
+		d.sequence = new_timelapse_sequence()
+	}
+
+	if err := os.MkdirAll(d.tl.sequence_dir(d.sequence), 0755); err != nil {
+		return err
+	}
+
+	if err := d.save_state(); err != nil {
+		return err
+	}
+
+	fw_opt, err := d.framework_option()
+	if err != nil {
+		return err
+	}
+
+	d.fw_opt = fw_opt
+	d.attempts = 0
+
+	return d.launch()
+}
+
+// reset turns capture off, current sequence is kept for next Start.
+// NOTE: should be call after `op_mtx` locked!
+func (d *TimelapseCameraDriver) reset() {
+	d.st = CAMERA_DRIVER_STATE_OFF
+	d.frmwrk = nil
+	d.fw_opt = nil
+
+	if err := d.save_state(); err != nil {
+		d.logger.WithError(err).Warningf("failed to save timelapse state")
+	}
+
+	if err := d.mdl.PutObject("state", strings.NewReader("off")); err != nil {
+		d.logger.WithError(err).Warningf("failed to put state object")
+	}
+}
+
+// NOTE: should be call after `op_mtx` locked!
+func (d *TimelapseCameraDriver) start() error {
+	d.st = CAMERA_DRIVER_STATE_ON
+	d.start_at = time.Now()
+	d.restart_count = 0
+
+	if err := d.relaunch(); err != nil {
+		d.last_error = err
+		d.stop()
+		d.reset()
+		return err
+	}
+
+	if err := d.mdl.PutObject("state", strings.NewReader("on")); err != nil {
+		d.logger.WithError(err).Warningf("failed to put state object")
+	}
+
+	d.logger.WithField("sequence", d.sequence).Infof("timelapse capture started")
+
+	return nil
+}
+
+func (d *TimelapseCameraDriver) Start(opt *CameraDriverStartOption) ([]*CameraDriverOutput, error) {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if d.st == CAMERA_DRIVER_STATE_ON {
+		return nil, ErrNotStartable
+	}
+
+	if err := d.start(); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (d *TimelapseCameraDriver) Stop() error {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	if d.st == CAMERA_DRIVER_STATE_OFF {
+		return ErrNotStoppable
+	}
+
+	d.stop()
+	d.reset()
+
+	d.logger.WithField("sequence", d.sequence).Infof("timelapse capture stopped")
+
+	return nil
+}
+
+// rotate closes current sequence, capture continues with new sequence if capturing,
+// returns closed sequence, empty if no current sequence.
+func (d *TimelapseCameraDriver) rotate() (string, error) {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	seq := d.sequence
+	if seq == "" {
+		return "", nil
+	}
+
+	d.sequence = ""
+	if d.st != CAMERA_DRIVER_STATE_ON {
+		return seq, d.save_state()
+	}
+
+	d.restart_count = 0
+	if err := d.relaunch(); err != nil {
+		d.last_error = err
+		d.stop()
+		d.reset()
+		return seq, err
+	}
+
+	return seq, nil
+}
+
+// assemble_sequence assembles sequence and uploads it as clip parts.
+func (d *TimelapseCameraDriver) assemble_sequence(seq string) (*CameraDriverTimelapse, error) {
+	dir, err := ioutil.TempDir("", "camera-timelapse")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tl, file, err := d.tl.assemble(ffmpeg_binary(d.opt), dir, seq, d.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = put_clip_objects(d.mdl, tl.Clip, file, d.tl.ChunkSize); err != nil {
+		return nil, err
+	}
+
+	if err = d.tl.finish(seq); err != nil {
+		d.logger.WithError(err).WithField("sequence", seq).Warningf("failed to finish timelapse sequence")
+	}
+
+	d.logger.WithFields(log.Fields{
+		"sequence": seq,
+		"frames":   tl.Frames,
+		"object":   tl.Clip.Object,
+	}).Infof("timelapse assembled")
+
+	return tl, nil
+}
+
+// AssembleTimelapse closes current sequence, assembles and uploads it,
+// sequences left by failed assembling are assembled too.
+func (d *TimelapseCameraDriver) AssembleTimelapse() (*CameraDriverTimelapse, error) {
+	d.assemble_mtx.Lock()
+	defer d.assemble_mtx.Unlock()
+
+	cur, err := d.rotate()
+	if err != nil {
+		return nil, err
+	}
+
+	d.op_mtx.Lock()
+	next := d.sequence
+	d.op_mtx.Unlock()
+
+	seqs, err := d.tl.closed_sequences(next)
+	if err != nil {
+		return nil, err
+	}
+
+	var tl *CameraDriverTimelapse
+	var cur_err error
+	for _, seq := range seqs {
+		x, err := d.assemble_sequence(seq)
+		if err == ErrTimelapseEmpty {
+			os.RemoveAll(d.tl.sequence_dir(seq))
+		}
+
+		if seq == cur {
+			tl, cur_err = x, err
+			continue
+		}
+
+		if err != nil && err != ErrTimelapseEmpty {
+			d.logger.WithError(err).WithField("sequence", seq).Warningf("failed to assemble timelapse sequence")
+		}
+	}
+
+	if cur_err != nil {
+		return nil, cur_err
+	}
+
+	if tl == nil {
+		return nil, ErrTimelapseEmpty
+	}
+
+	return tl, nil
+}
+
+func (d *TimelapseCameraDriver) assemble_periodically() {
+	ticker := time.NewTicker(d.tl.AssembleInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := d.AssembleTimelapse(); err != nil && err != ErrTimelapseEmpty {
+			d.logger.WithError(err).Warningf("failed to assemble timelapse")
+		}
+	}
+}
+
+func (d *TimelapseCameraDriver) State() *CameraDriverState {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	return d.st
+}
+
+func (d *TimelapseCameraDriver) Status() *CameraDriverStatus {
+	d.op_mtx.Lock()
+	defer d.op_mtx.Unlock()
+
+	st := &CameraDriverStatus{
+		State:          d.st,
+		StartAt:        d.start_at,
+		RestartCount:   d.restart_count,
+		LastError:      d.last_error,
+		LastExitReason: d.last_exit_reason,
+	}
+
+	if d.frmwrk != nil {
+		fst := d.frmwrk.Status()
+		st.Pid = fst.Pid
+		st.Stats = fst.Stats
+		st.Logs = fst.Logs
+	}
+
+	return st
+}
+
+func NewTimelapseCameraDriver(opt *CameraDriverOption, args ...interface{}) (CameraDriver, error) {
+	var logger log.FieldLogger
+	var module CameraDriverModule
+
+	opt_helper.Setopt(map[string]func(key string, val interface{}) error{
+		"logger": opt_helper.ToLogger(&logger),
+		"module": ToCameraDriverModule(&module),
+	})(args...)
+
+	rst, err := new_restart_policy(opt.Sub("restart"))
+	if err != nil {
+		return nil, err
+	}
+
+	tl, err := new_timelapse_config(opt.Sub("timelapse"), opt.GetString("work_dir"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(tl.Path, 0755); err != nil {
+		return nil, err
+	}
+
+	state, err := tl.load_state()
+	if err != nil {
+		return nil, err
+	}
+
+	drv := &TimelapseCameraDriver{
+		op_mtx:       new(sync.Mutex),
+		assemble_mtx: new(sync.Mutex),
+		logger:       logger,
+		mdl:          module,
+		opt:          opt,
+		tl:           tl,
+		st:           CAMERA_DRIVER_STATE_OFF,
+		sequence:     state.Sequence,
+	}
+	drv.framework_supervisor = &framework_supervisor{
+		mtx:        drv.op_mtx,
+		logger:     logger,
+		rst:        rst,
+		supervised: drv.capturing,
+		new_option: drv.framework_option,
+		on_give_up: drv.reset,
+	}
+
+	if state.Capturing {
+		drv.op_mtx.Lock()
+		if err = drv.start(); err != nil {
+			drv.logger.WithError(err).Warningf("failed to resume timelapse capture")
+		}
+		drv.op_mtx.Unlock()
+	}
+
+	if tl.AssembleInterval > 0 {
+		go drv.assemble_periodically()
+	}
+
+	return drv, nil
+}
+
+var register_timelapse_camera_driver_once sync.Once
+
+func init() {
+	register_timelapse_camera_driver_once.Do(func() {
+		register_camera_driver_factory("timelapse", NewTimelapseCameraDriver)
+	})
+}
@@ -0,0 +1,33 @@
+package camera_driver
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTimelapseConfigPath(t *testing.T) {
+	for _, c := range []struct {
+		yaml     string
+		work_dir string
+		path     string
+	}{
+		{"", "/var/lib/camera", "/var/lib/camera/metathings-camera/timelapse"},
+		{"interval: 1s", "/var/lib/camera", "/var/lib/camera/metathings-camera/timelapse"},
+		{"path: /data/timelapse", "/var/lib/camera", "/data/timelapse"},
+		{"", "", filepath.Join(default_work_dir(""), "metathings-camera", "timelapse")},
+	} {
+		var opt *CameraDriverOption
+		if c.yaml != "" {
+			opt = new_test_driver_option(t, c.yaml)
+		}
+
+		cfg, err := new_timelapse_config(opt, c.work_dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cfg.Path != c.path {
+			t.Errorf("option %q, work dir %q: path %v, want %v", c.yaml, c.work_dir, cfg.Path, c.path)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package camera_driver
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	v4l2 "github.com/nayotta/metathings-component-camera/pkg/camera/v4l2"
+)
+
+/*
+ * V4L2: framework inputs of `v4l2` format are checked by device capabilities before launch,
+ *   frame size and frame rate should be supported by device, supported values are listed by ListDevices.
+ *   Device numbers of usb cameras may change after reboot, input could select device by stable attributes,
+ *   resolved to current device file at each launch.
+ * Options:
+ *   driver:
+ *   ...
+ *     inputs:
+ *       0:
+ *         [ file: /dev/video0 ]
+ *         [ device: ]  // select capture device, all set fields should match exactly one device, exclusive with file.
+ *           [ by_id: <name> ]  // link name in `/dev/v4l/by-id`, like `usb-046d_HD_Pro_Webcam_C920_1234ABCD-video-index0`.
+ *           [ by_path: <name> ]  // link name in `/dev/v4l/by-path`, like `platform-xhci-hcd.0-usb-0:1:1.0-video-index0`.
+ *           [ vendor: <id> ]  // usb vendor id in hex, quoted like `"046d"`.
+ *           [ product: <id> ]  // usb product id in hex, quoted like `"082d"`.
+ *           [ serial: <serial> ]  // usb serial number.
+ *           [ card: <name> ]  // v4l2 card name, like `HD Pro Webcam C920`.
+ *         [ check: <bool> ]  // check device capabilities, default true.
+ *   ...
+ *
+ * Attributes of devices are listed by ListDevices. Device failed to query, like permission denied, is not checked.
+ */
+
+func new_v4l2_selector(opt *CameraDriverOption) *v4l2.Selector {
+	return &v4l2.Selector{
+		ById:    opt.GetString("by_id"),
+		ByPath:  opt.GetString("by_path"),
+		Vendor:  opt.GetString("vendor"),
+		Product: opt.GetString("product"),
+		Serial:  opt.GetString("serial"),
+		Card:    opt.GetString("card"),
+	}
+}
+
+// select_v4l2_device returns current device selected by selector of key.
+func select_v4l2_device(opt *CameraDriverOption, key string) (*v4l2.Device, error) {
+	sel := new_v4l2_selector(opt)
+	if sel.Empty() {
+		return nil, new_invalid_config_error(key)
+	}
+
+	return v4l2.SelectDevice(sel, nil)
+}
+
+// check_v4l2_inputs returns error if framework inputs of v4l2 format are not supported by device.
+func check_v4l2_inputs(opt *CameraDriverOption, fw *FrameworkOption, logger log.FieldLogger) error {
+	fw_ins := fw.Sub("inputs")
+	if fw_ins == nil {
+		return nil
+	}
+
+	for _, k := range fw_ins.NextKeys() {
+		input := fw_ins.Sub(k)
+		if input.GetString("format") != "v4l2" {
+			continue
+		}
+
+		key := "inputs." + k
+		if opt.IsSet(key+".check") && !opt.GetBool(key+".check") {
+			continue
+		}
+
+		file := input.GetString("file")
+		dev, err := v4l2.QueryDevice(file)
+		if err != nil {
+			logger.WithError(err).WithField("file", file).Debugf("failed to query v4l2 device, skip check")
+			continue
+		}
+
+		if err = check_v4l2_input(dev, input, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func check_v4l2_input(dev *v4l2.Device, input *FrameworkOption, key string) error {
+	if !dev.Capture {
+		return fmt.Errorf("unsupported input: %v is not video capture device", dev.Path)
+	}
+
+	var width, height uint32
+	if val := input.GetString("frame_size"); val != "" {
+		var err error
+		if width, height, err = v4l2.ParseFrameSize(val); err != nil {
+			return new_invalid_config_error(key + ".frame_size")
+		}
+
+		if !dev.SupportsFrameSize(width, height) {
+			return new_unsupported_input_error(key+".frame_size", val, dev.FrameSizes())
+		}
+	}
+
+	if val := input.GetString("frame_rate"); val != "" {
+		rate, err := v4l2.ParseFrameRate(val)
+		if err != nil {
+			return new_invalid_config_error(key + ".frame_rate")
+		}
+
+		if !dev.SupportsFrameRate(width, height, rate) {
+			return new_unsupported_input_error(key+".frame_rate", val, dev.FrameRates(width, height))
+		}
+	}
+
+	return nil
+}
+
+func new_unsupported_input_error(key, val string, supported []string) error {
+	return fmt.Errorf("unsupported input: %s %s, supported: %s", key, val, strings.Join(supported, ", "))
+}
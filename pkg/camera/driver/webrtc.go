@@ -0,0 +1,154 @@
+package camera_driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	webrtc "github.com/nayotta/metathings-component-camera/pkg/camera/webrtc"
+)
+
+/*
+ * WebRTC: outputs of `webrtc` type are played by browsers with low latency, negotiated by NegotiateWebrtc,
+ *   framework sends h264 rtp to local relay, all sessions of output share one encoder.
+ * Options:
+ *   driver:
+ *   ...
+ *     [ webrtc: ]
+ *       [ ice_servers: ]  // stun or turn servers.
+ *         - urls: [ <url>, ... ]  // like `stun:stun.l.google.com:19302`.
+ *           [ username: <name> ]
+ *           [ credential: <password> ]
+ *       [ port_range: <min>-<max> ]  // udp port range of ice, default any.
+ *       [ nat_ips: [ <ip>, ... ] ]  // public ips of 1:1 nat, used as host candidates.
+ *       [ max_sessions: <count> ]  // max sessions of each output, default 8.
+ *       [ gather_timeout: <duration> ]  // max duration to gather candidates of answer, default `5s`.
+ *     outputs:
+ *       <label>:
+ *         type: webrtc
+ *   ...
+ *
+ * Video codec should be h264 without b-frames, like `libx264` with `-tune zerolatency`,
+ * new session starts playing from next key frame, so short gop is recommended. Audio is not sent.
+ * Webrtc output has no url, no object is published.
+ */
+
+const (
+	OUTPUT_TYPE_WEBRTC = "webrtc"
+)
+
+type WebrtcConfig struct {
+	IceServers    []*webrtc.IceServer
+	PortMin       uint16
+	PortMax       uint16
+	NatIPs        []string
+	MaxSessions   int
+	GatherTimeout time.Duration
+}
+
+func (c *WebrtcConfig) new_relay(logger log.FieldLogger) (*webrtc.Relay, error) {
+	opt := &webrtc.RelayOption{
+		IceServers:    c.IceServers,
+		PortMin:       c.PortMin,
+		PortMax:       c.PortMax,
+		Nat1To1IPs:    c.NatIPs,
+		MaxSessions:   c.MaxSessions,
+		GatherTimeout: c.GatherTimeout,
+		Logger:        logger,
+	}
+
+	return webrtc.NewRelay(opt)
+}
+
+func new_webrtc_output(relay *webrtc.Relay, label string, cfg *CameraDriverOption) (*CameraDriverOutput, error) {
+	out := &CameraDriverOutput{
+		Label:  label,
+		LiveId: label,
+		File:   relay.Url(),
+	}
+
+	ctx := &simple_camera_driver_output_context{
+		Label:  label,
+		LiveId: label,
+	}
+
+	var err error
+	if out.Playbacks, err = render_playbacks(label, cfg.GetStringMapString("playbacks"), ctx); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// apply_webrtc_output sets rtp muxer of output sends to relay.
+// NOTE: fw should be a cloned option, it will be modified.
+func apply_webrtc_output(fw *CameraDriverOption, label string) {
+	fw.Set("outputs."+label+".format", "rtp")
+}
+
+func parse_port_range(s string) (uint16, uint16, error) {
+	ss := strings.Split(s, "-")
+	if len(ss) != 2 {
+		return 0, 0, fmt.Errorf("invalid port range")
+	}
+
+	min, err := strconv.ParseUint(ss[0], 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	max, err := strconv.ParseUint(ss[1], 10, 16)
+	if err != nil || max < min || min == 0 {
+		return 0, 0, fmt.Errorf("invalid port range")
+	}
+
+	return uint16(min), uint16(max), nil
+}
+
+func new_webrtc_config(opt *CameraDriverOption) (*WebrtcConfig, error) {
+	c := &WebrtcConfig{}
+	if opt == nil {
+		return c, nil
+	}
+
+	var servers []struct {
+		Urls       []string
+		Username   string
+		Credential string
+	}
+	if err := opt.UnmarshalKey("ice_servers", &servers); err != nil {
+		return nil, new_invalid_config_error("webrtc.ice_servers")
+	}
+	for _, s := range servers {
+		if len(s.Urls) == 0 {
+			return nil, new_invalid_config_error("webrtc.ice_servers")
+		}
+		c.IceServers = append(c.IceServers, &webrtc.IceServer{Urls: s.Urls, Username: s.Username, Credential: s.Credential})
+	}
+
+	if val := opt.GetString("port_range"); val != "" {
+		var err error
+		if c.PortMin, c.PortMax, err = parse_port_range(val); err != nil {
+			return nil, new_invalid_config_error("webrtc.port_range")
+		}
+	}
+
+	c.NatIPs = opt.GetStringSlice("nat_ips")
+
+	if opt.IsSet("max_sessions") {
+		if c.MaxSessions = opt.GetInt("max_sessions"); c.MaxSessions <= 0 {
+			return nil, new_invalid_config_error("webrtc.max_sessions")
+		}
+	}
+
+	if opt.IsSet("gather_timeout") {
+		if c.GatherTimeout = opt.GetDuration("gather_timeout"); c.GatherTimeout <= 0 {
+			return nil, new_invalid_config_error("webrtc.gather_timeout")
+		}
+	}
+
+	return c, nil
+}
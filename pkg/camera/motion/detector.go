@@ -0,0 +1,227 @@
+package camera_motion
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DEFAULT_SENSITIVITY = 50
+	DEFAULT_THRESHOLD   = 25
+	DEFAULT_LEARN_RATE  = 0.05
+)
+
+var (
+	ErrInvalidOptions = errors.New("invalid motion detector options")
+	ErrInvalidFrame   = errors.New("invalid frame")
+)
+
+// Rect is region in normalized coordinates, 0 to 1 of frame width and height.
+type Rect struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+func (r Rect) contains(x, y float64) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Frame is grayscale frame, one byte per pixel, row by row.
+type Frame struct {
+	Width     int
+	Height    int
+	Pix       []byte
+	Timestamp time.Time
+}
+
+type Options struct {
+	// 1 to 100, higher sensitivity triggers on smaller changed area.
+	Sensitivity int
+	// pixel changed if differs from background more than threshold, 1 to 255.
+	Threshold int
+	// background learning rate, 0 to 1.
+	LearnRate float64
+	// only pixels in regions are detected, all pixels if empty.
+	Regions []Rect
+	// pixels in masks are ignored.
+	Masks    []Rect
+	Cooldown time.Duration
+}
+
+func (o *Options) validate() error {
+	if o.Sensitivity < 1 || o.Sensitivity > 100 {
+		return ErrInvalidOptions
+	}
+
+	if o.Threshold < 1 || o.Threshold > 255 {
+		return ErrInvalidOptions
+	}
+
+	if o.LearnRate <= 0 || o.LearnRate > 1 {
+		return ErrInvalidOptions
+	}
+
+	if o.Cooldown < 0 {
+		return ErrInvalidOptions
+	}
+
+	return nil
+}
+
+// min_area returns changed ratio of detected pixels to trigger motion,
+// from 5% at sensitivity 1 to 0.05% at sensitivity 100.
+func (o *Options) min_area() float64 {
+	return float64(101-o.Sensitivity) / 100 * 0.05
+}
+
+type Result struct {
+	Timestamp time.Time
+	// changed ratio of detected pixels.
+	Score float64
+	// bounding region of changed pixels.
+	Region Rect
+}
+
+// Detector detects motion by difference between frame and running average background,
+// not safe for concurrent use.
+type Detector struct {
+	opt *Options
+
+	width      int
+	height     int
+	mask       []bool
+	detected   int
+	background []float32
+	last_event time.Time
+}
+
+// Reset drops background, next frame is learned as background.
+func (d *Detector) Reset() {
+	d.background = nil
+}
+
+func (d *Detector) init(f *Frame) {
+	d.width, d.height = f.Width, f.Height
+	d.mask = make([]bool, f.Width*f.Height)
+	d.detected = 0
+
+	for y := 0; y < f.Height; y++ {
+		fy := (float64(y) + 0.5) / float64(f.Height)
+		for x := 0; x < f.Width; x++ {
+			fx := (float64(x) + 0.5) / float64(f.Width)
+
+			ok := len(d.opt.Regions) == 0
+			for _, r := range d.opt.Regions {
+				if r.contains(fx, fy) {
+					ok = true
+					break
+				}
+			}
+
+			for _, r := range d.opt.Masks {
+				if r.contains(fx, fy) {
+					ok = false
+					break
+				}
+			}
+
+			d.mask[y*f.Width+x] = ok
+			if ok {
+				d.detected++
+			}
+		}
+	}
+
+	d.background = make([]float32, len(f.Pix))
+	for i, p := range f.Pix {
+		d.background[i] = float32(p)
+	}
+}
+
+// Detect returns result and true if motion detected and not in cooldown,
+// background is updated by frame.
+func (d *Detector) Detect(f *Frame) (*Result, bool, error) {
+	if f.Width <= 0 || f.Height <= 0 || len(f.Pix) != f.Width*f.Height {
+		return nil, false, ErrInvalidFrame
+	}
+
+	if d.background == nil || f.Width != d.width || f.Height != d.height {
+		d.init(f)
+		return nil, false, nil
+	}
+
+	changed := 0
+	min_x, min_y, max_x, max_y := f.Width, f.Height, -1, -1
+	threshold := float32(d.opt.Threshold)
+	rate := float32(d.opt.LearnRate)
+
+	for i, p := range f.Pix {
+		v := float32(p)
+		bg := d.background[i]
+		d.background[i] = bg + (v-bg)*rate
+
+		if !d.mask[i] {
+			continue
+		}
+
+		diff := v - bg
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= threshold {
+			continue
+		}
+
+		changed++
+		x, y := i%f.Width, i/f.Width
+		if x < min_x {
+			min_x = x
+		}
+		if x > max_x {
+			max_x = x
+		}
+		if y < min_y {
+			min_y = y
+		}
+		if y > max_y {
+			max_y = y
+		}
+	}
+
+	if d.detected == 0 {
+		return nil, false, nil
+	}
+
+	score := float64(changed) / float64(d.detected)
+	if changed == 0 || score < d.opt.min_area() {
+		return nil, false, nil
+	}
+
+	if !d.last_event.IsZero() && f.Timestamp.Sub(d.last_event) < d.opt.Cooldown {
+		return nil, false, nil
+	}
+	d.last_event = f.Timestamp
+
+	res := &Result{
+		Timestamp: f.Timestamp,
+		Score:     score,
+		Region: Rect{
+			X:      float64(min_x) / float64(f.Width),
+			Y:      float64(min_y) / float64(f.Height),
+			Width:  float64(max_x-min_x+1) / float64(f.Width),
+			Height: float64(max_y-min_y+1) / float64(f.Height),
+		},
+	}
+
+	return res, true, nil
+}
+
+func NewDetector(opt *Options) (*Detector, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Detector{opt: opt}, nil
+}
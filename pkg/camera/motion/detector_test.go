@@ -0,0 +1,195 @@
+package camera_motion
+
+import (
+	"testing"
+	"time"
+)
+
+const (
+	test_frame_width  = 20
+	test_frame_height = 20
+)
+
+// new_test_frame returns black frame with square patch of value at x, y.
+func new_test_frame(ts time.Time, x, y, size int, value byte) *Frame {
+	f := &Frame{
+		Width:     test_frame_width,
+		Height:    test_frame_height,
+		Pix:       make([]byte, test_frame_width*test_frame_height),
+		Timestamp: ts,
+	}
+
+	for j := y; j < y+size; j++ {
+		for i := x; i < x+size; i++ {
+			f.Pix[j*f.Width+i] = value
+		}
+	}
+
+	return f
+}
+
+func new_test_options() *Options {
+	return &Options{
+		Sensitivity: DEFAULT_SENSITIVITY,
+		Threshold:   DEFAULT_THRESHOLD,
+		LearnRate:   DEFAULT_LEARN_RATE,
+		Cooldown:    10 * time.Second,
+	}
+}
+
+// detect learns black background, returns result of frame.
+func detect(t *testing.T, opt *Options, f *Frame) (*Result, bool) {
+	d, err := NewDetector(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := d.Detect(new_test_frame(f.Timestamp.Add(-time.Second), 0, 0, 0, 0)); err != nil || ok {
+		t.Fatalf("background detected: %v, %v", ok, err)
+	}
+
+	res, ok, err := d.Detect(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res, ok
+}
+
+func TestDetectThreshold(t *testing.T) {
+	now := time.Now()
+
+	for _, c := range []struct {
+		value    byte
+		detected bool
+	}{
+		{10, false},
+		{DEFAULT_THRESHOLD, false},
+		{DEFAULT_THRESHOLD + 1, true},
+		{255, true},
+	} {
+		_, ok := detect(t, new_test_options(), new_test_frame(now, 10, 10, 5, c.value))
+		if ok != c.detected {
+			t.Errorf("value %v: detected %v, want %v", c.value, ok, c.detected)
+		}
+	}
+}
+
+func TestDetectMinArea(t *testing.T) {
+	now := time.Now()
+
+	for _, c := range []struct {
+		sensitivity int
+		size        int
+		detected    bool
+	}{
+		// 1% changed, min area 2.55%.
+		{50, 2, false},
+		// 6.25% changed.
+		{50, 5, true},
+		// min area 0.05%.
+		{100, 1, true},
+		// min area 5%.
+		{1, 4, false},
+		{1, 5, true},
+	} {
+		opt := new_test_options()
+		opt.Sensitivity = c.sensitivity
+
+		_, ok := detect(t, opt, new_test_frame(now, 0, 0, c.size, 255))
+		if ok != c.detected {
+			t.Errorf("sensitivity %v size %v: detected %v, want %v", c.sensitivity, c.size, ok, c.detected)
+		}
+	}
+}
+
+func TestDetectResult(t *testing.T) {
+	now := time.Now()
+
+	res, ok := detect(t, new_test_options(), new_test_frame(now, 10, 5, 5, 255))
+	if !ok {
+		t.Fatalf("motion not detected")
+	}
+
+	if !res.Timestamp.Equal(now) {
+		t.Errorf("timestamp %v, want %v", res.Timestamp, now)
+	}
+
+	if res.Score != 25.0/400 {
+		t.Errorf("score %v, want %v", res.Score, 25.0/400)
+	}
+
+	want := Rect{X: 0.5, Y: 0.25, Width: 0.25, Height: 0.25}
+	if res.Region != want {
+		t.Errorf("region %+v, want %+v", res.Region, want)
+	}
+}
+
+func TestDetectMasks(t *testing.T) {
+	now := time.Now()
+
+	opt := new_test_options()
+	opt.Masks = []Rect{{X: 0.5, Y: 0.5, Width: 0.5, Height: 0.5}}
+	if _, ok := detect(t, opt, new_test_frame(now, 10, 10, 5, 255)); ok {
+		t.Errorf("motion detected in mask")
+	}
+
+	opt = new_test_options()
+	opt.Regions = []Rect{{X: 0, Y: 0, Width: 0.5, Height: 0.5}}
+	if _, ok := detect(t, opt, new_test_frame(now, 10, 10, 5, 255)); ok {
+		t.Errorf("motion detected out of regions")
+	}
+	if _, ok := detect(t, opt, new_test_frame(now, 0, 0, 5, 255)); !ok {
+		t.Errorf("motion not detected in regions")
+	}
+}
+
+func TestDetectCooldown(t *testing.T) {
+	d, err := NewDetector(new_test_options())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for _, c := range []struct {
+		offset   time.Duration
+		detected bool
+	}{
+		{0, false},
+		{1 * time.Second, true},
+		{2 * time.Second, false},
+		{10 * time.Second, false},
+		{11 * time.Second, true},
+		{12 * time.Second, false},
+	} {
+		size := 5
+		if c.offset == 0 {
+			// black background.
+			size = 0
+		}
+
+		_, ok, err := d.Detect(new_test_frame(now.Add(c.offset), 0, 0, size, 255))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok != c.detected {
+			t.Errorf("offset %v: detected %v, want %v", c.offset, ok, c.detected)
+		}
+	}
+}
+
+func TestDetectInvalid(t *testing.T) {
+	if _, err := NewDetector(&Options{Sensitivity: 0, Threshold: 25, LearnRate: 0.05}); err != ErrInvalidOptions {
+		t.Errorf("error %v, want %v", err, ErrInvalidOptions)
+	}
+
+	d, err := NewDetector(new_test_options())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = d.Detect(&Frame{Width: 2, Height: 2, Pix: make([]byte, 3)}); err != ErrInvalidFrame {
+		t.Errorf("error %v, want %v", err, ErrInvalidFrame)
+	}
+}
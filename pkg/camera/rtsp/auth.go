@@ -0,0 +1,193 @@
+package camera_rtsp
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AUTH_SCHEME_ANY    = "any"
+	AUTH_SCHEME_BASIC  = "basic"
+	AUTH_SCHEME_DIGEST = "digest"
+
+	auth_realm = "camera"
+
+	// digest nonce is issued for each challenge, valid until expired.
+	auth_nonce_ttl = 1 * time.Minute
+	// outstanding nonces are bounded, clients could request challenges without authorizing.
+	auth_max_nonces = 1024
+)
+
+// Credential authenticates clients by basic or digest auth, empty scheme means any.
+type Credential struct {
+	Username string
+	Password string
+	Scheme   string
+}
+
+func (c *Credential) allow(scheme string) bool {
+	return c.Scheme == "" || c.Scheme == AUTH_SCHEME_ANY || c.Scheme == scheme
+}
+
+type authenticator struct {
+	ttl time.Duration
+
+	mtx    sync.Mutex
+	nonces map[string]time.Time // expiry of issued nonces.
+}
+
+func new_authenticator(ttl time.Duration) *authenticator {
+	return &authenticator{
+		ttl:    ttl,
+		nonces: map[string]time.Time{},
+	}
+}
+
+func (a *authenticator) new_nonce() string {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	now := time.Now()
+	if len(a.nonces) >= auth_max_nonces {
+		for nonce, expiry := range a.nonces {
+			if now.After(expiry) {
+				delete(a.nonces, nonce)
+			}
+		}
+	}
+
+	for nonce := range a.nonces {
+		if len(a.nonces) < auth_max_nonces {
+			break
+		}
+		delete(a.nonces, nonce)
+	}
+
+	nonce := random_hex(16)
+	a.nonces[nonce] = now.Add(a.ttl)
+
+	return nonce
+}
+
+// check_nonce returns nonce is issued and not expired, stale if expired.
+func (a *authenticator) check_nonce(nonce string) (ok bool, stale bool) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	expiry, found := a.nonces[nonce]
+	if !found {
+		return false, false
+	}
+
+	if time.Now().After(expiry) {
+		delete(a.nonces, nonce)
+		return false, true
+	}
+
+	return true, false
+}
+
+// challenge sets authenticate headers with fresh nonce,
+// stale tells client to retry with new nonce without asking password.
+func (a *authenticator) challenge(res *response, cred *Credential, stale bool) *response {
+	if cred.allow(AUTH_SCHEME_DIGEST) {
+		val := fmt.Sprintf(`Digest realm="%s", nonce="%s"`, auth_realm, a.new_nonce())
+		if stale {
+			val += `, stale=TRUE`
+		}
+		res.set("WWW-Authenticate", val)
+	}
+
+	if cred.allow(AUTH_SCHEME_BASIC) {
+		res.set("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, auth_realm))
+	}
+
+	return res
+}
+
+func md5_hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func secure_equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// parse_auth_params parses params of digest authorization, like `username="a", nonce="b"`.
+func parse_auth_params(s string) map[string]string {
+	ps := map[string]string{}
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimLeft(s, ", ") {
+		i := strings.IndexByte(s, '=')
+		if i < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:i]))
+		s = s[i+1:]
+
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			j := strings.IndexByte(s[1:], '"')
+			if j < 0 {
+				break
+			}
+			val, s = s[1:j+1], s[j+2:]
+		} else {
+			j := strings.IndexByte(s, ',')
+			if j < 0 {
+				j = len(s)
+			}
+			val, s = strings.TrimSpace(s[:j]), s[j:]
+		}
+		ps[key] = val
+	}
+
+	return ps
+}
+
+// verify returns request is authorized by credential, stale if digest is correct but nonce expired.
+func (a *authenticator) verify(req *request, cred *Credential) (ok bool, stale bool) {
+	val := req.header.Get("Authorization")
+
+	switch {
+	case strings.HasPrefix(val, "Basic ") && cred.allow(AUTH_SCHEME_BASIC):
+		buf, err := base64.StdEncoding.DecodeString(strings.TrimSpace(val[len("Basic "):]))
+		if err != nil {
+			return false, false
+		}
+		return secure_equal(string(buf), cred.Username+":"+cred.Password), false
+	case strings.HasPrefix(val, "Digest ") && cred.allow(AUTH_SCHEME_DIGEST):
+		ps := parse_auth_params(val[len("Digest "):])
+		if ps["username"] != cred.Username || ps["realm"] != auth_realm || ps["nonce"] == "" {
+			return false, false
+		}
+
+		// digest is computed on uri, replayed digest of other uri is rejected.
+		if ps["uri"] != req.uri {
+			return false, false
+		}
+
+		nonce := ps["nonce"]
+		ha1 := md5_hex(cred.Username + ":" + auth_realm + ":" + cred.Password)
+		ha2 := md5_hex(req.method + ":" + ps["uri"])
+		expected := md5_hex(ha1 + ":" + nonce + ":" + ha2)
+		if qop := ps["qop"]; qop != "" {
+			expected = md5_hex(ha1 + ":" + nonce + ":" + ps["nc"] + ":" + ps["cnonce"] + ":" + qop + ":" + ha2)
+		}
+
+		if !secure_equal(ps["response"], expected) {
+			return false, false
+		}
+
+		return a.check_nonce(nonce)
+	}
+
+	return false, false
+}
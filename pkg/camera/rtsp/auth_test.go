@@ -0,0 +1,156 @@
+package camera_rtsp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+var test_credential = &Credential{Username: "viewer", Password: "secret"}
+
+const test_uri = "rtsp://localhost:8554/live/0"
+
+// challenge_nonce returns nonce and stale of digest challenge in response.
+func challenge_nonce(t *testing.T, res *response) (string, bool) {
+	for _, h := range res.header {
+		if !strings.HasPrefix(h, "WWW-Authenticate: Digest ") {
+			continue
+		}
+
+		ps := parse_auth_params(strings.TrimPrefix(h, "WWW-Authenticate: Digest "))
+		return ps["nonce"], ps["stale"] == "TRUE"
+	}
+
+	t.Fatalf("no digest challenge in %v", res.header)
+	return "", false
+}
+
+func new_auth_request(method, uri, authorization string) *request {
+	req := &request{
+		method: method,
+		uri:    uri,
+		header: textproto.MIMEHeader{},
+	}
+	req.header.Set("Authorization", authorization)
+
+	return req
+}
+
+func digest_authorization(username, password, nonce, method, uri string) string {
+	ha1 := md5_hex(username + ":" + auth_realm + ":" + password)
+	ha2 := md5_hex(method + ":" + uri)
+	response := md5_hex(ha1 + ":" + nonce + ":" + ha2)
+
+	return fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`, username, auth_realm, nonce, uri, response)
+}
+
+func TestDigestAuth(t *testing.T) {
+	a := new_authenticator(auth_nonce_ttl)
+	nonce, _ := challenge_nonce(t, a.challenge(new_response(401), test_credential, false))
+
+	for _, c := range []struct {
+		name     string
+		username string
+		password string
+		nonce    string
+		uri      string
+		ok       bool
+	}{
+		{"correct", "viewer", "secret", nonce, test_uri, true},
+		{"wrong password", "viewer", "wrong", nonce, test_uri, false},
+		{"wrong username", "other", "secret", nonce, test_uri, false},
+		{"unknown nonce", "viewer", "secret", "0123456789abcdef", test_uri, false},
+		{"mismatched uri", "viewer", "secret", nonce, "rtsp://localhost:8554/live/1", false},
+	} {
+		req := new_auth_request("DESCRIBE", test_uri, digest_authorization(c.username, c.password, c.nonce, "DESCRIBE", c.uri))
+
+		ok, stale := a.verify(req, test_credential)
+		if ok != c.ok || stale {
+			t.Errorf("%v: ok %v, stale %v, want %v", c.name, ok, stale, c.ok)
+		}
+	}
+}
+
+func TestDigestAuthQop(t *testing.T) {
+	a := new_authenticator(auth_nonce_ttl)
+	nonce, _ := challenge_nonce(t, a.challenge(new_response(401), test_credential, false))
+
+	ha1 := md5_hex("viewer:" + auth_realm + ":secret")
+	ha2 := md5_hex("DESCRIBE:" + test_uri)
+	response := md5_hex(ha1 + ":" + nonce + ":00000001:abcd:auth:" + ha2)
+	val := fmt.Sprintf(`Digest username="viewer", realm="%s", nonce="%s", uri="%s", qop=auth, nc=00000001, cnonce="abcd", response="%s"`, auth_realm, nonce, test_uri, response)
+
+	if ok, _ := a.verify(new_auth_request("DESCRIBE", test_uri, val), test_credential); !ok {
+		t.Errorf("digest with qop rejected")
+	}
+}
+
+func TestDigestAuthStaleNonce(t *testing.T) {
+	a := new_authenticator(10 * time.Millisecond)
+	nonce, _ := challenge_nonce(t, a.challenge(new_response(401), test_credential, false))
+
+	time.Sleep(20 * time.Millisecond)
+
+	req := new_auth_request("DESCRIBE", test_uri, digest_authorization("viewer", "secret", nonce, "DESCRIBE", test_uri))
+	ok, stale := a.verify(req, test_credential)
+	if ok || !stale {
+		t.Fatalf("expired nonce: ok %v, stale %v", ok, stale)
+	}
+
+	next, stale := challenge_nonce(t, a.challenge(new_response(401), test_credential, stale))
+	if !stale || next == nonce {
+		t.Errorf("challenge after stale: nonce %v, stale %v", next, stale)
+	}
+
+	// wrong password with expired nonce is not stale.
+	other, _ := challenge_nonce(t, a.challenge(new_response(401), test_credential, false))
+	time.Sleep(20 * time.Millisecond)
+	req = new_auth_request("DESCRIBE", test_uri, digest_authorization("viewer", "wrong", other, "DESCRIBE", test_uri))
+	if ok, stale = a.verify(req, test_credential); ok || stale {
+		t.Errorf("wrong password with expired nonce: ok %v, stale %v", ok, stale)
+	}
+}
+
+func TestDigestAuthFreshNonce(t *testing.T) {
+	a := new_authenticator(auth_nonce_ttl)
+
+	seen := map[string]bool{}
+	for i := 0; i < auth_max_nonces+10; i++ {
+		nonce, _ := challenge_nonce(t, a.challenge(new_response(401), test_credential, false))
+		if seen[nonce] {
+			t.Fatalf("nonce %v issued twice", nonce)
+		}
+		seen[nonce] = true
+	}
+
+	if len(a.nonces) > auth_max_nonces {
+		t.Errorf("%v nonces kept, max %v", len(a.nonces), auth_max_nonces)
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	a := new_authenticator(auth_nonce_ttl)
+
+	for _, c := range []struct {
+		userinfo string
+		ok       bool
+	}{
+		{"viewer:secret", true},
+		{"viewer:wrong", false},
+		{"viewer:secret2", false},
+	} {
+		val := "Basic " + base64.StdEncoding.EncodeToString([]byte(c.userinfo))
+		if ok, _ := a.verify(new_auth_request("DESCRIBE", test_uri, val), test_credential); ok != c.ok {
+			t.Errorf("basic %v: ok %v, want %v", c.userinfo, ok, c.ok)
+		}
+	}
+
+	digest_only := &Credential{Username: "viewer", Password: "secret", Scheme: AUTH_SCHEME_DIGEST}
+	val := "Basic " + base64.StdEncoding.EncodeToString([]byte("viewer:secret"))
+	if ok, _ := a.verify(new_auth_request("DESCRIBE", test_uri, val), digest_only); ok {
+		t.Errorf("basic accepted by digest only credential")
+	}
+}
@@ -0,0 +1,391 @@
+package camera_rtsp
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// session timeout announced to readers, they send keepalive in time.
+	session_timeout = 60 * time.Second
+	read_timeout    = 2 * session_timeout
+	write_timeout   = 10 * time.Second
+
+	reader_queue_size = 512
+)
+
+const (
+	method_options       = "OPTIONS"
+	method_describe      = "DESCRIBE"
+	method_announce      = "ANNOUNCE"
+	method_setup         = "SETUP"
+	method_play          = "PLAY"
+	method_record        = "RECORD"
+	method_teardown      = "TEARDOWN"
+	method_get_parameter = "GET_PARAMETER"
+	method_set_parameter = "SET_PARAMETER"
+)
+
+var public_methods = strings.Join([]string{
+	method_options,
+	method_describe,
+	method_announce,
+	method_setup,
+	method_play,
+	method_record,
+	method_teardown,
+	method_get_parameter,
+	method_set_parameter,
+}, ", ")
+
+// conn is rtsp connection of publisher or reader, session is bound to connection.
+type conn struct {
+	srv    *Server
+	nc     net.Conn
+	br     *bufio.Reader
+	logger log.FieldLogger
+	wmtx   sync.Mutex
+
+	session string
+	st      *stream
+
+	// publisher
+	announced bool
+	recording bool
+	sdp       []byte
+	controls  []string
+	channels  map[byte]int // interleaved channel to media index.
+
+	// reader
+	playing bool
+	tracks  map[int]byte // media index to interleaved channel.
+	queue   chan []byte
+	done    chan struct{}
+}
+
+func (c *conn) write(buf []byte) error {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+
+	c.nc.SetWriteDeadline(time.Now().Add(write_timeout))
+	_, err := c.nc.Write(buf)
+	return err
+}
+
+func (c *conn) serve() {
+	defer c.close()
+
+	for {
+		c.nc.SetReadDeadline(time.Now().Add(read_timeout))
+
+		b, err := c.br.Peek(1)
+		if err != nil {
+			return
+		}
+
+		if b[0] == frame_magic {
+			channel, payload, err := read_frame(c.br)
+			if err != nil {
+				return
+			}
+
+			// rtcp of readers is ignored.
+			if c.recording {
+				c.srv.dispatch(c, channel, payload)
+			}
+			continue
+		}
+
+		req, err := read_request(c.br)
+		if err != nil {
+			if err == ErrBadRequest {
+				c.write(new_response(400).bytes(""))
+			}
+			return
+		}
+
+		res := c.handle(req)
+		if err = c.write(res.bytes(req.header.Get("CSeq"))); err != nil {
+			return
+		}
+
+		switch {
+		case req.method == method_teardown:
+			return
+		case req.method == method_play && res.status == 200:
+			if !c.attach() {
+				return
+			}
+		}
+	}
+}
+
+// attach adds reader to stream after play responded, frames are sent from then.
+func (c *conn) attach() bool {
+	c.srv.mtx.Lock()
+	defer c.srv.mtx.Unlock()
+
+	if c.st.sdp == nil || c.srv.streams[c.st.path] != c.st {
+		return false
+	}
+
+	c.st.readers[c] = struct{}{}
+	c.playing = true
+	go c.write_frames()
+
+	c.logger.WithField("path", c.st.path).Infof("rtsp reader attached")
+
+	return true
+}
+
+func (c *conn) write_frames() {
+	for {
+		select {
+		case frame := <-c.queue:
+			if err := c.write(frame); err != nil {
+				c.nc.Close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *conn) close() {
+	c.srv.mtx.Lock()
+	if st := c.st; st != nil {
+		if st.pub == c {
+			c.srv.close_stream(st)
+			c.logger.WithField("path", st.path).Infof("rtsp publisher detached")
+		}
+		delete(st.readers, c)
+	}
+	delete(c.srv.conns, c)
+	c.srv.mtx.Unlock()
+
+	close(c.done)
+	c.nc.Close()
+}
+
+func (c *conn) handle(req *request) *response {
+	if val := req.header.Get("Session"); val != "" && c.session != "" {
+		if strings.TrimSpace(strings.Split(val, ";")[0]) != c.session {
+			return new_response(454)
+		}
+	}
+
+	var res *response
+	switch req.method {
+	case method_options:
+		res = new_response(200).set("Public", public_methods)
+	case method_describe:
+		res = c.describe(req)
+	case method_announce:
+		res = c.announce(req)
+	case method_setup:
+		res = c.setup(req)
+	case method_play:
+		res = c.play(req)
+	case method_record:
+		res = c.record(req)
+	case method_teardown, method_get_parameter, method_set_parameter:
+		res = new_response(200)
+	default:
+		return new_response(501)
+	}
+
+	if c.session != "" && res.status == 200 {
+		res.set("Session", fmt.Sprintf("%s;timeout=%d", c.session, int(session_timeout.Seconds())))
+	}
+
+	return res
+}
+
+// authorize returns response to reject reader, nil if authorized.
+func (c *conn) authorize(req *request) *response {
+	cred := c.srv.opt.Auth
+	if cred == nil {
+		return nil
+	}
+
+	ok, stale := c.srv.auth.verify(req, cred)
+	if ok {
+		return nil
+	}
+
+	return c.srv.auth.challenge(new_response(401), cred, stale)
+}
+
+func (c *conn) describe(req *request) *response {
+	if res := c.authorize(req); res != nil {
+		return res
+	}
+
+	p, err := req.path()
+	if err != nil {
+		return new_response(400)
+	}
+
+	c.srv.mtx.Lock()
+	defer c.srv.mtx.Unlock()
+
+	st := c.srv.streams[p]
+	if st == nil || st.sdp == nil {
+		return new_response(404)
+	}
+
+	return new_response(200).
+		set("Content-Base", strings.TrimSuffix(strings.SplitN(req.uri, "?", 2)[0], "/")+"/").
+		set("Content-Type", "application/sdp").
+		with_body(st.sdp)
+}
+
+func (c *conn) announce(req *request) *response {
+	if c.st != nil {
+		return new_response(455)
+	}
+
+	p, err := req.path()
+	if err != nil {
+		return new_response(400)
+	}
+
+	c.srv.mtx.Lock()
+	st := c.srv.streams[p]
+	c.srv.mtx.Unlock()
+
+	if st == nil {
+		return new_response(404)
+	}
+
+	if ok, stale := c.srv.auth.verify(req, st.publisher); !ok {
+		return c.srv.auth.challenge(new_response(401), st.publisher, stale)
+	}
+
+	controls, err := parse_sdp_controls(req.body)
+	if err != nil {
+		return new_response(400)
+	}
+
+	c.st = st
+	c.announced = true
+	c.sdp = req.body
+	c.controls = controls
+
+	return new_response(200)
+}
+
+func (c *conn) setup(req *request) *response {
+	if c.playing || c.recording {
+		return new_response(455)
+	}
+
+	tr := parse_transport(req.header.Get("Transport"))
+	if !tr.tcp {
+		return new_response(461)
+	}
+
+	var channel int
+	if c.announced {
+		track := match_control(c.controls, req.uri)
+		if track < 0 {
+			return new_response(404)
+		}
+
+		if channel = tr.interleaved; channel < 0 {
+			channel = 2 * track
+		}
+		c.channels[byte(channel)] = track
+	} else {
+		if res := c.authorize(req); res != nil {
+			return res
+		}
+
+		p, err := req.path()
+		if err != nil {
+			return new_response(400)
+		}
+
+		c.srv.mtx.Lock()
+		st := c.srv.lookup(p)
+		var controls []string
+		if st != nil {
+			controls = st.controls
+		}
+		published := st != nil && st.sdp != nil
+		c.srv.mtx.Unlock()
+
+		if !published {
+			return new_response(404)
+		}
+
+		if c.st != nil && c.st != st {
+			return new_response(455)
+		}
+
+		track := match_control(controls, req.uri)
+		if track < 0 {
+			return new_response(404)
+		}
+
+		if channel = tr.interleaved; channel < 0 {
+			channel = 2 * track
+		}
+		c.st = st
+		c.tracks[track] = byte(channel)
+	}
+
+	if c.session == "" {
+		c.session = random_hex(8)
+	}
+
+	val := fmt.Sprintf("RTP/AVP/TCP;unicast;interleaved=%d-%d", channel, channel+1)
+	if tr.record {
+		val += ";mode=record"
+	}
+
+	return new_response(200).set("Transport", val)
+}
+
+func (c *conn) play(req *request) *response {
+	if c.announced || len(c.tracks) == 0 {
+		return new_response(455)
+	}
+
+	return new_response(200).set("Range", "npt=0.000-")
+}
+
+func (c *conn) record(req *request) *response {
+	if !c.announced || len(c.channels) == 0 {
+		return new_response(455)
+	}
+
+	c.srv.mtx.Lock()
+	defer c.srv.mtx.Unlock()
+
+	st := c.st
+	if c.srv.streams[st.path] != st {
+		return new_response(404)
+	}
+
+	// replaces publisher of last framework, readers reconnect for new session.
+	if st.pub != nil {
+		c.srv.close_stream(st)
+	}
+
+	st.pub = c
+	st.sdp = c.sdp
+	st.controls = c.controls
+	c.recording = true
+
+	c.logger.WithField("path", st.path).Infof("rtsp publisher attached")
+
+	return new_response(200)
+}
@@ -0,0 +1,12 @@
+package camera_rtsp
+
+import (
+	"errors"
+)
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrInvalidSdp   = errors.New("invalid sdp")
+	ErrInvalidPath  = errors.New("invalid path")
+	ErrServerClosed = errors.New("server closed")
+)
@@ -0,0 +1,198 @@
+package camera_rtsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	rtsp_version = "RTSP/1.0"
+
+	// max size of request body, like sdp of announce.
+	max_body_size = 64 * 1024
+
+	frame_magic = '$'
+)
+
+var status_texts = map[int]string{
+	200: "OK",
+	400: "Bad Request",
+	401: "Unauthorized",
+	404: "Not Found",
+	454: "Session Not Found",
+	455: "Method Not Valid in This State",
+	461: "Unsupported Transport",
+	501: "Not Implemented",
+}
+
+type request struct {
+	method string
+	uri    string
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// path returns path of request uri without trailing slash.
+func (r *request) path() (string, error) {
+	u, err := url.Parse(r.uri)
+	if err != nil {
+		return "", ErrBadRequest
+	}
+
+	p := strings.TrimSuffix(u.Path, "/")
+	if p == "" {
+		p = "/"
+	}
+
+	return p, nil
+}
+
+func read_request(br *bufio.Reader) (*request, error) {
+	tr := textproto.NewReader(br)
+
+	var line string
+	var err error
+	// skip empty lines between requests.
+	for line == "" {
+		if line, err = tr.ReadLine(); err != nil {
+			return nil, err
+		}
+	}
+
+	fs := strings.Fields(line)
+	if len(fs) != 3 || fs[2] != rtsp_version {
+		return nil, ErrBadRequest
+	}
+
+	hdr, err := tr.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &request{method: fs[0], uri: fs[1], header: hdr}
+
+	if val := hdr.Get("Content-Length"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 || n > max_body_size {
+			return nil, ErrBadRequest
+		}
+
+		req.body = make([]byte, n)
+		if _, err = io.ReadFull(br, req.body); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+type response struct {
+	status int
+	header []string
+	body   []byte
+}
+
+func new_response(status int) *response {
+	return &response{status: status}
+}
+
+func (r *response) set(key, val string) *response {
+	r.header = append(r.header, key+": "+val)
+	return r
+}
+
+func (r *response) with_body(body []byte) *response {
+	r.body = body
+	return r
+}
+
+func (r *response) bytes(cseq string) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "%s %d %s\r\n", rtsp_version, r.status, status_texts[r.status])
+	if cseq != "" {
+		fmt.Fprintf(&buf, "CSeq: %s\r\n", cseq)
+	}
+	for _, h := range r.header {
+		buf.WriteString(h + "\r\n")
+	}
+	if len(r.body) > 0 {
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n", len(r.body))
+	}
+	buf.WriteString("\r\n")
+	buf.Write(r.body)
+
+	return buf.Bytes()
+}
+
+// read_frame reads interleaved frame, `$<channel><length><payload>`.
+func read_frame(br *bufio.Reader) (byte, []byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+
+	if hdr[0] != frame_magic {
+		return 0, nil, ErrBadRequest
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(hdr[2:]))
+	if _, err := io.ReadFull(br, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return hdr[1], payload, nil
+}
+
+func make_frame(channel byte, payload []byte) []byte {
+	buf := make([]byte, 4+len(payload))
+	buf[0] = frame_magic
+	buf[1] = channel
+	binary.BigEndian.PutUint16(buf[2:], uint16(len(payload)))
+	copy(buf[4:], payload)
+	return buf
+}
+
+type transport struct {
+	tcp         bool
+	interleaved int // -1 if not set.
+	record      bool
+}
+
+// parse_transport parses the first transport spec of `Transport` header,
+// like `RTP/AVP/TCP;unicast;interleaved=0-1;mode=record`.
+func parse_transport(val string) *transport {
+	tr := &transport{interleaved: -1}
+
+	spec := strings.Split(val, ",")[0]
+	for i, param := range strings.Split(spec, ";") {
+		param = strings.TrimSpace(param)
+		if i == 0 {
+			tr.tcp = strings.HasSuffix(strings.ToUpper(param), "/TCP")
+			continue
+		}
+
+		kv := strings.SplitN(param, "=", 2)
+		switch strings.ToLower(kv[0]) {
+		case "interleaved":
+			if len(kv) == 2 {
+				if n, err := strconv.Atoi(strings.Split(kv[1], "-")[0]); err == nil && n >= 0 && n < 255 {
+					tr.interleaved = n
+				}
+			}
+		case "mode":
+			if len(kv) == 2 {
+				tr.record = strings.EqualFold(strings.Trim(kv[1], `"`), "record")
+			}
+		}
+	}
+
+	return tr
+}
@@ -0,0 +1,48 @@
+package camera_rtsp
+
+import (
+	"strings"
+)
+
+// parse_sdp_controls returns control attribute of each media in order, empty if absent.
+func parse_sdp_controls(sdp []byte) ([]string, error) {
+	var controls []string
+
+	for _, line := range strings.Split(string(sdp), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "m="):
+			controls = append(controls, "")
+		case strings.HasPrefix(line, "a=control:") && len(controls) > 0:
+			controls[len(controls)-1] = strings.TrimPrefix(line, "a=control:")
+		}
+	}
+
+	if len(controls) == 0 {
+		return nil, ErrInvalidSdp
+	}
+
+	return controls, nil
+}
+
+// match_control returns media index of setup uri by control attribute, -1 if not found.
+func match_control(controls []string, uri string) int {
+	uri = strings.TrimSuffix(strings.SplitN(uri, "?", 2)[0], "/")
+
+	for i, c := range controls {
+		if c == "" || c == "*" {
+			continue
+		}
+
+		if uri == c || strings.HasSuffix(uri, "/"+c) {
+			return i
+		}
+	}
+
+	// single media without control is setup by aggregate uri.
+	if len(controls) == 1 {
+		return 0
+	}
+
+	return -1
+}
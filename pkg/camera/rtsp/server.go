@@ -0,0 +1,244 @@
+package camera_rtsp
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// user name of publisher, password is generated for each publish.
+	publisher_username = "publisher"
+)
+
+type ServerOption struct {
+	Address string
+	// Auth authenticates readers, nil means anonymous.
+	Auth   *Credential
+	Logger log.FieldLogger
+}
+
+// stream is published by one publisher and played by many readers.
+type stream struct {
+	path      string
+	publisher *Credential
+
+	pub      *conn
+	sdp      []byte // set when recording.
+	controls []string
+	readers  map[*conn]struct{}
+}
+
+// Server is rtsp server relays streams published by local framework to readers,
+// by rtp over rtsp interleaved tcp.
+type Server struct {
+	opt  *ServerOption
+	ln   net.Listener
+	auth *authenticator
+
+	mtx     sync.Mutex
+	streams map[string]*stream
+	conns   map[*conn]struct{}
+	closed  bool
+}
+
+func random_hex(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+func (s *Server) Addr() net.Addr {
+	return s.ln.Addr()
+}
+
+func (s *Server) Port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+// Publish registers stream of path, returns local url for publisher with credential,
+// current publisher and readers of path are disconnected.
+func (s *Server) Publish(p string) (string, error) {
+	if !strings.HasPrefix(p, "/") || path.Clean(p) != p || p == "/" {
+		return "", ErrInvalidPath
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.closed {
+		return "", ErrServerClosed
+	}
+
+	if st, ok := s.streams[p]; ok {
+		s.close_stream(st)
+	}
+
+	cred := &Credential{Username: publisher_username, Password: random_hex(16)}
+	s.streams[p] = &stream{
+		path:      p,
+		publisher: cred,
+		readers:   map[*conn]struct{}{},
+	}
+
+	addr := s.ln.Addr().(*net.TCPAddr)
+	host := addr.IP
+	if host.IsUnspecified() {
+		host = net.IPv4(127, 0, 0, 1)
+	}
+
+	u := &url.URL{
+		Scheme: "rtsp",
+		User:   url.UserPassword(cred.Username, cred.Password),
+		Host:   (&net.TCPAddr{IP: host, Port: addr.Port}).String(),
+		Path:   p,
+	}
+
+	return u.String(), nil
+}
+
+// Unpublish removes stream of path, publisher and readers are disconnected.
+func (s *Server) Unpublish(p string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if st, ok := s.streams[p]; ok {
+		s.close_stream(st)
+		delete(s.streams, p)
+	}
+}
+
+// NOTE: should be call after `mtx` locked!
+func (s *Server) close_stream(st *stream) {
+	if st.pub != nil {
+		st.pub.nc.Close()
+		st.pub = nil
+	}
+	st.sdp = nil
+
+	for r := range st.readers {
+		r.nc.Close()
+	}
+}
+
+// lookup returns stream of path or its parent, like setup uri of media.
+// NOTE: should be call after `mtx` locked!
+func (s *Server) lookup(p string) *stream {
+	if st, ok := s.streams[p]; ok {
+		return st
+	}
+
+	return s.streams[path.Dir(p)]
+}
+
+// dispatch forwards interleaved frame of publisher to readers of the same media,
+// frames are dropped for slow readers.
+func (s *Server) dispatch(pub *conn, channel byte, payload []byte) {
+	track, ok := pub.channels[channel&^1]
+	if !ok {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	st := pub.st
+	if st.pub != pub {
+		return
+	}
+
+	for r := range st.readers {
+		base, ok := r.tracks[track]
+		if !ok {
+			continue
+		}
+
+		select {
+		case r.queue <- make_frame(base+(channel&1), payload):
+		default:
+			r.logger.Debugf("drop frame for slow reader")
+		}
+	}
+}
+
+func (s *Server) serve() {
+	for {
+		nc, err := s.ln.Accept()
+		if err != nil {
+			s.mtx.Lock()
+			closed := s.closed
+			s.mtx.Unlock()
+
+			if !closed {
+				s.opt.Logger.WithError(err).Warningf("failed to accept rtsp connection")
+			}
+			return
+		}
+
+		c := &conn{
+			srv:      s,
+			nc:       nc,
+			br:       bufio.NewReader(nc),
+			logger:   s.opt.Logger.WithField("remote", nc.RemoteAddr().String()),
+			channels: map[byte]int{},
+			tracks:   map[int]byte{},
+			queue:    make(chan []byte, reader_queue_size),
+			done:     make(chan struct{}),
+		}
+
+		s.mtx.Lock()
+		if s.closed {
+			s.mtx.Unlock()
+			nc.Close()
+			return
+		}
+		s.conns[c] = struct{}{}
+		s.mtx.Unlock()
+
+		go c.serve()
+	}
+}
+
+// Close stops listening and disconnects all clients.
+func (s *Server) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	for c := range s.conns {
+		c.nc.Close()
+	}
+
+	return s.ln.Close()
+}
+
+func NewServer(opt *ServerOption) (*Server, error) {
+	ln, err := net.Listen("tcp", opt.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		opt:     opt,
+		ln:      ln,
+		auth:    new_authenticator(auth_nonce_ttl),
+		streams: map[string]*stream{},
+		conns:   map[*conn]struct{}{},
+	}
+	go s.serve()
+
+	return s, nil
+}
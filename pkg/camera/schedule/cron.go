@@ -0,0 +1,213 @@
+package camera_schedule
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCron   = errors.New("invalid cron expression")
+	ErrInvalidWindow = errors.New("invalid schedule window")
+)
+
+var cron_descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cron_month_names = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cron_weekday_names = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Cron is standard 5 fields cron expression, `minute hour day-of-month month day-of-week`,
+// day matches if either day-of-month or day-of-week matches when both restricted.
+type Cron struct {
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	dom_any bool
+	dow_any bool
+}
+
+// parse_cron_field parses field like `*`, `*/5`, `1-5`, `mon-fri`, `0,30` into bitset.
+func parse_cron_field(s string, min, max int, names map[string]int) (uint64, bool, error) {
+	var bits uint64
+
+	parse_value := func(v string) (int, error) {
+		if n, ok := names[strings.ToLower(v)]; ok {
+			return n, nil
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min || n > max {
+			return 0, ErrInvalidCron
+		}
+
+		return n, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, false, ErrInvalidCron
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.IndexByte(part, '-') > 0:
+			i := strings.IndexByte(part, '-')
+			var err error
+			if lo, err = parse_value(part[:i]); err != nil {
+				return 0, false, err
+			}
+			if hi, err = parse_value(part[i+1:]); err != nil {
+				return 0, false, err
+			}
+			if lo > hi {
+				return 0, false, ErrInvalidCron
+			}
+		default:
+			n, err := parse_value(part)
+			if err != nil {
+				return 0, false, err
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+
+	return bits, s == "*", nil
+}
+
+func ParseCron(s string) (*Cron, error) {
+	s = strings.TrimSpace(s)
+	if val, ok := cron_descriptors[strings.ToLower(s)]; ok {
+		s = val
+	}
+
+	fs := strings.Fields(s)
+	if len(fs) != 5 {
+		return nil, ErrInvalidCron
+	}
+
+	var err error
+	c := &Cron{}
+
+	if c.minute, _, err = parse_cron_field(fs[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+
+	if c.hour, _, err = parse_cron_field(fs[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+
+	if c.dom, c.dom_any, err = parse_cron_field(fs[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+
+	if c.month, _, err = parse_cron_field(fs[3], 1, 12, cron_month_names); err != nil {
+		return nil, err
+	}
+
+	if c.dow, c.dow_any, err = parse_cron_field(fs[4], 0, 7, cron_weekday_names); err != nil {
+		return nil, err
+	}
+	// 7 is sunday too.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	return c, nil
+}
+
+func (c *Cron) match_day(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.dom_any && c.dow_any:
+		return true
+	case c.dom_any:
+		return dow
+	case c.dow_any:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next returns the first matched minute after t, zero if not matched in search limit.
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+		case !c.match_day(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// Prev returns the last matched minute at or before t, zero if not matched in search limit.
+func (c *Cron) Prev(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute)
+	limit := t.AddDate(-5, 0, 0)
+
+	for t.After(limit) {
+		y, m, d := t.Date()
+		switch {
+		case c.month&(1<<uint(m)) == 0:
+			t = time.Date(y, m, 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !c.match_day(t):
+			t = time.Date(y, m, d, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Add(-time.Duration(t.Minute()+1) * time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,187 @@
+package camera_schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func must_load_location(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %v unavailable: %v", name, err)
+	}
+
+	return loc
+}
+
+func must_parse_cron(t *testing.T, s string) *Cron {
+	c, err := ParseCron(s)
+	if err != nil {
+		t.Fatalf("parse cron %q: %v", s, err)
+	}
+
+	return c
+}
+
+func TestParseCronField(t *testing.T) {
+	for _, c := range []struct {
+		field string
+		min   int
+		max   int
+		bits  uint64
+		any   bool
+		err   error
+	}{
+		{"*", 0, 5, 0x3f, true, nil},
+		{"*/2", 0, 5, 0x15, false, nil},
+		{"1-3", 0, 5, 0xe, false, nil},
+		{"1-5/2", 0, 5, 0x2a, false, nil},
+		{"2/2", 0, 5, 0x14, false, nil},
+		{"0,3", 0, 5, 0x9, false, nil},
+		{"mon-fri", 0, 7, 0x3e, false, nil},
+		{"SAT,sun", 0, 7, 0x41, false, nil},
+		{"6", 0, 5, 0, false, ErrInvalidCron},
+		{"3-1", 0, 5, 0, false, ErrInvalidCron},
+		{"*/0", 0, 5, 0, false, ErrInvalidCron},
+		{"x", 0, 5, 0, false, ErrInvalidCron},
+	} {
+		bits, any, err := parse_cron_field(c.field, c.min, c.max, cron_weekday_names)
+		if err != c.err {
+			t.Errorf("field %q: error %v, want %v", c.field, err, c.err)
+			continue
+		}
+
+		if bits != c.bits || any != c.any {
+			t.Errorf("field %q: %#x %v, want %#x %v", c.field, bits, any, c.bits, c.any)
+		}
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	for _, c := range []struct {
+		expr string
+		ok   bool
+	}{
+		{"0 9 * * 1-5", true},
+		{"*/15 * * * *", true},
+		{"0 0 1 jan *", true},
+		{"0 0 * * 7", true},
+		{"@daily", true},
+		{"0 9 * *", false},
+		{"60 9 * * *", false},
+		{"0 24 * * *", false},
+		{"0 0 0 * *", false},
+		{"0 0 * 13 *", false},
+		{"0 0 * * 8", false},
+	} {
+		_, err := ParseCron(c.expr)
+		if (err == nil) != c.ok {
+			t.Errorf("cron %q: error %v, want ok %v", c.expr, err, c.ok)
+		}
+	}
+
+	// 7 is sunday too.
+	if c := must_parse_cron(t, "0 0 * * 7"); c.dow&1 == 0 {
+		t.Errorf("dow 7 does not match sunday")
+	}
+}
+
+func TestCronNextPrev(t *testing.T) {
+	utc := time.UTC
+	shanghai := must_load_location(t, "Asia/Shanghai")
+	new_york := must_load_location(t, "America/New_York")
+
+	for _, c := range []struct {
+		name string
+		expr string
+		at   time.Time
+		next time.Time
+		prev time.Time
+	}{
+		{
+			name: "weekday",
+			expr: "0 9 * * mon-fri",
+			// saturday.
+			at:   time.Date(2021, 1, 2, 12, 0, 0, 0, utc),
+			next: time.Date(2021, 1, 4, 9, 0, 0, 0, utc),
+			prev: time.Date(2021, 1, 1, 9, 0, 0, 0, utc),
+		},
+		{
+			name: "exact minute",
+			expr: "30 12 * * *",
+			at:   time.Date(2021, 1, 2, 12, 30, 20, 0, utc),
+			next: time.Date(2021, 1, 3, 12, 30, 0, 0, utc),
+			prev: time.Date(2021, 1, 2, 12, 30, 0, 0, utc),
+		},
+		{
+			name: "month end",
+			expr: "0 0 31 * *",
+			at:   time.Date(2021, 2, 10, 0, 0, 0, 0, utc),
+			next: time.Date(2021, 3, 31, 0, 0, 0, 0, utc),
+			prev: time.Date(2021, 1, 31, 0, 0, 0, 0, utc),
+		},
+		{
+			name: "dom or dow",
+			expr: "0 0 1 * mon",
+			// wednesday.
+			at:   time.Date(2021, 9, 15, 12, 0, 0, 0, utc),
+			next: time.Date(2021, 9, 20, 0, 0, 0, 0, utc),
+			prev: time.Date(2021, 9, 13, 0, 0, 0, 0, utc),
+		},
+		{
+			name: "time zone",
+			expr: "0 9 * * *",
+			// 2021-01-02 09:30 in shanghai.
+			at:   time.Date(2021, 1, 2, 1, 30, 0, 0, utc).In(shanghai),
+			next: time.Date(2021, 1, 3, 9, 0, 0, 0, shanghai),
+			prev: time.Date(2021, 1, 2, 9, 0, 0, 0, shanghai),
+		},
+		{
+			// 02:30 does not exist when clocks spring forward.
+			name: "dst gap",
+			expr: "30 2 * * *",
+			at:   time.Date(2021, 3, 14, 0, 0, 0, 0, new_york),
+			next: time.Date(2021, 3, 15, 2, 30, 0, 0, new_york),
+			prev: time.Date(2021, 3, 13, 2, 30, 0, 0, new_york),
+		},
+		{
+			name: "dst spring forward hourly",
+			expr: "0 * * * *",
+			at:   time.Date(2021, 3, 14, 1, 30, 0, 0, new_york),
+			next: time.Date(2021, 3, 14, 3, 0, 0, 0, new_york),
+			prev: time.Date(2021, 3, 14, 1, 0, 0, 0, new_york),
+		},
+		{
+			// day is 25 hours when clocks fall back.
+			name: "dst fall back",
+			expr: "0 3 * * *",
+			at:   time.Date(2021, 11, 7, 0, 0, 0, 0, new_york),
+			next: time.Date(2021, 11, 7, 3, 0, 0, 0, new_york),
+			prev: time.Date(2021, 11, 6, 3, 0, 0, 0, new_york),
+		},
+	} {
+		cron := must_parse_cron(t, c.expr)
+
+		if next := cron.Next(c.at); !next.Equal(c.next) {
+			t.Errorf("%v: next %v, want %v", c.name, next, c.next)
+		}
+
+		if prev := cron.Prev(c.at); !prev.Equal(c.prev) {
+			t.Errorf("%v: prev %v, want %v", c.name, prev, c.prev)
+		}
+	}
+}
+
+func TestCronNotMatched(t *testing.T) {
+	// february 30th never comes.
+	cron := must_parse_cron(t, "0 0 30 feb *")
+	at := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if next := cron.Next(at); !next.IsZero() {
+		t.Errorf("next %v, want zero", next)
+	}
+
+	if prev := cron.Prev(at); !prev.IsZero() {
+		t.Errorf("prev %v, want zero", prev)
+	}
+}
@@ -0,0 +1,221 @@
+package camera_schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// max transitions checked for next state change.
+	max_transitions = 1000
+)
+
+// Schedule decides on or off state by weekly windows and start and stop crons,
+// state is on if any window contains the time, or the last start cron is not before the last stop cron.
+type Schedule struct {
+	loc     *time.Location
+	windows []*window
+	starts  []*Cron
+	stops   []*Cron
+}
+
+// window is on from start to stop, evaluated separately from other windows,
+// so overlapped windows are merged.
+type window struct {
+	start *Cron
+	stop  *Cron
+}
+
+func (w *window) contains(t time.Time) bool {
+	start := w.start.Prev(t)
+	return !start.IsZero() && start.After(w.stop.Prev(t))
+}
+
+func parse_clock(s string) (int, int, error) {
+	ss := strings.Split(s, ":")
+	if len(ss) != 2 {
+		return 0, 0, ErrInvalidWindow
+	}
+
+	h, err := strconv.Atoi(ss[0])
+	if err != nil || h < 0 || h > 24 {
+		return 0, 0, ErrInvalidWindow
+	}
+
+	m, err := strconv.Atoi(ss[1])
+	if err != nil || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, 0, ErrInvalidWindow
+	}
+
+	return h, m, nil
+}
+
+// ParseWindow parses weekly window like `mon-fri 09:00-18:00`, `sat,sun 10:00-02:00` or `08:00-20:00`,
+// window ends at next day if end is not after begin, returns start and stop crons of window.
+func ParseWindow(s string) (*Cron, *Cron, error) {
+	fs := strings.Fields(s)
+
+	days := "*"
+	switch len(fs) {
+	case 1:
+	case 2:
+		days = fs[0]
+		fs = fs[1:]
+	default:
+		return nil, nil, ErrInvalidWindow
+	}
+
+	ss := strings.Split(fs[0], "-")
+	if len(ss) != 2 {
+		return nil, nil, ErrInvalidWindow
+	}
+
+	bh, bm, err := parse_clock(ss[0])
+	if err != nil || bh == 24 {
+		return nil, nil, ErrInvalidWindow
+	}
+
+	eh, em, err := parse_clock(ss[1])
+	if err != nil {
+		return nil, nil, ErrInvalidWindow
+	}
+
+	if eh != 24 && eh*60+em == bh*60+bm {
+		return nil, nil, ErrInvalidWindow
+	}
+
+	dow, _, err := parse_cron_field(days, 0, 7, cron_weekday_names)
+	if err != nil {
+		return nil, nil, ErrInvalidWindow
+	}
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+	dow &= 0x7f
+
+	start := &Cron{
+		minute:  1 << uint(bm),
+		hour:    1 << uint(bh),
+		dom_any: true,
+		month:   0x1ffe,
+		dow:     dow,
+		dow_any: days == "*",
+	}
+
+	stop := *start
+	if eh*60+em <= bh*60+bm || eh == 24 {
+		// stop at next day.
+		stop.dow = (dow<<1 | dow>>6) & 0x7f
+		eh %= 24
+	}
+	stop.minute = 1 << uint(em)
+	stop.hour = 1 << uint(eh)
+
+	return start, &stop, nil
+}
+
+// State returns scheduled state at t, true means on.
+func (s *Schedule) State(t time.Time) bool {
+	t = t.In(s.loc)
+
+	for _, w := range s.windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+
+	var start, stop time.Time
+	for _, c := range s.starts {
+		if x := c.Prev(t); x.After(start) {
+			start = x
+		}
+	}
+
+	for _, c := range s.stops {
+		if x := c.Prev(t); x.After(stop) {
+			stop = x
+		}
+	}
+
+	return !start.IsZero() && !start.Before(stop)
+}
+
+func (s *Schedule) next_event(t time.Time) time.Time {
+	crons := append([]*Cron{}, s.starts...)
+	crons = append(crons, s.stops...)
+	for _, w := range s.windows {
+		crons = append(crons, w.start, w.stop)
+	}
+
+	var next time.Time
+	for _, c := range crons {
+		if x := c.Next(t); !x.IsZero() && (next.IsZero() || x.Before(next)) {
+			next = x
+		}
+	}
+
+	return next
+}
+
+// Next returns time and state of the next state change after t, zero time if not found.
+func (s *Schedule) Next(t time.Time) (time.Time, bool) {
+	t = t.In(s.loc)
+	cur := s.State(t)
+
+	for i := 0; i < max_transitions; i++ {
+		if t = s.next_event(t); t.IsZero() {
+			break
+		}
+
+		if st := s.State(t); st != cur {
+			return t, st
+		}
+	}
+
+	return time.Time{}, cur
+}
+
+func (s *Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// NewSchedule creates schedule by weekly windows and start and stop cron expressions,
+// nil location means local time.
+func NewSchedule(loc *time.Location, windows, starts, stops []string) (*Schedule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	s := &Schedule{loc: loc}
+
+	for _, w := range windows {
+		start, stop, err := ParseWindow(w)
+		if err != nil {
+			return nil, err
+		}
+		s.windows = append(s.windows, &window{start: start, stop: stop})
+	}
+
+	for _, x := range starts {
+		c, err := ParseCron(x)
+		if err != nil {
+			return nil, err
+		}
+		s.starts = append(s.starts, c)
+	}
+
+	for _, x := range stops {
+		c, err := ParseCron(x)
+		if err != nil {
+			return nil, err
+		}
+		s.stops = append(s.stops, c)
+	}
+
+	if len(s.windows) == 0 && len(s.starts) == 0 {
+		return nil, ErrInvalidWindow
+	}
+
+	return s, nil
+}
@@ -0,0 +1,181 @@
+package camera_schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	// 2021-01-04 is monday.
+	at := func(d, h, m int) time.Time {
+		return time.Date(2021, 1, 4+d, h, m, 0, 0, time.UTC)
+	}
+
+	for _, c := range []struct {
+		window string
+		err    error
+		// next start and stop after monday 00:00.
+		start time.Time
+		stop  time.Time
+	}{
+		{"mon-fri 09:00-18:00", nil, at(0, 9, 0), at(0, 18, 0)},
+		{"08:00-20:00", nil, at(0, 8, 0), at(0, 20, 0)},
+		// sunday window ends at monday.
+		{"sat,sun 10:00-02:00", nil, at(5, 10, 0), at(0, 2, 0)},
+		{"sun 22:00-06:00", nil, at(6, 22, 0), at(0, 6, 0)},
+		{"7 22:00-24:00", nil, at(6, 22, 0), at(0, 0, 0)},
+		{"tue 00:00-24:00", nil, at(1, 0, 0), at(2, 0, 0)},
+		{"mon 09:00-09:00", ErrInvalidWindow, time.Time{}, time.Time{}},
+		{"mon 24:00-09:00", ErrInvalidWindow, time.Time{}, time.Time{}},
+		{"mon 09:00-24:30", ErrInvalidWindow, time.Time{}, time.Time{}},
+		{"mon 9-18", ErrInvalidWindow, time.Time{}, time.Time{}},
+		{"xyz 09:00-18:00", ErrInvalidWindow, time.Time{}, time.Time{}},
+		{"mon 09:00-18:00 x", ErrInvalidWindow, time.Time{}, time.Time{}},
+	} {
+		start, stop, err := ParseWindow(c.window)
+		if err != c.err {
+			t.Errorf("window %q: error %v, want %v", c.window, err, c.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		from := at(0, 0, 0).Add(-time.Minute)
+		if x := start.Next(from); !x.Equal(c.start) {
+			t.Errorf("window %q: start %v, want %v", c.window, x, c.start)
+		}
+
+		if x := stop.Next(from); !x.Equal(c.stop) {
+			t.Errorf("window %q: stop %v, want %v", c.window, x, c.stop)
+		}
+	}
+}
+
+func TestScheduleState(t *testing.T) {
+	shanghai := must_load_location(t, "Asia/Shanghai")
+
+	// 2021-01-04 is monday.
+	at := func(d, h, m int) time.Time {
+		return time.Date(2021, 1, 4+d, h, m, 0, 0, shanghai)
+	}
+
+	for _, c := range []struct {
+		name    string
+		windows []string
+		starts  []string
+		stops   []string
+		at      time.Time
+		state   bool
+	}{
+		{"in window", []string{"mon 09:00-18:00"}, nil, nil, at(0, 9, 0), true},
+		{"window end", []string{"mon 09:00-18:00"}, nil, nil, at(0, 18, 0), false},
+		{"before window", []string{"mon 09:00-18:00"}, nil, nil, at(0, 8, 59), false},
+		{"overnight window", []string{"mon 22:00-06:00"}, nil, nil, at(1, 5, 0), true},
+		{"overlapped windows", []string{"mon 09:00-18:00", "mon 17:00-20:00"}, nil, nil, at(0, 18, 30), true},
+		{"overlapped windows end", []string{"mon 09:00-18:00", "mon 17:00-20:00"}, nil, nil, at(0, 20, 0), false},
+		{"contained window", []string{"mon 09:00-18:00", "mon 10:00-11:00"}, nil, nil, at(0, 12, 0), true},
+		{"cron started", nil, []string{"0 9 * * *"}, []string{"0 18 * * *"}, at(0, 12, 0), true},
+		{"cron stopped", nil, []string{"0 9 * * *"}, []string{"0 18 * * *"}, at(0, 19, 0), false},
+		{"cron start after window", []string{"mon 09:00-18:00"}, []string{"0 19 * * mon"}, []string{"0 21 * * mon"}, at(0, 20, 0), true},
+		{"cron stop in window", []string{"mon 09:00-18:00"}, []string{"0 8 * * mon"}, []string{"0 10 * * mon"}, at(0, 11, 0), true},
+	} {
+		s, err := NewSchedule(shanghai, c.windows, c.starts, c.stops)
+		if err != nil {
+			t.Fatalf("%v: %v", c.name, err)
+		}
+
+		if st := s.State(c.at); st != c.state {
+			t.Errorf("%v: state %v, want %v", c.name, st, c.state)
+		}
+
+		// state does not depend on location of time.
+		if st := s.State(c.at.UTC()); st != c.state {
+			t.Errorf("%v: state of utc %v, want %v", c.name, st, c.state)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	shanghai := must_load_location(t, "Asia/Shanghai")
+	new_york := must_load_location(t, "America/New_York")
+
+	for _, c := range []struct {
+		name    string
+		loc     *time.Location
+		windows []string
+		at      time.Time
+		next    time.Time
+		state   bool
+	}{
+		{
+			name:    "start",
+			loc:     shanghai,
+			windows: []string{"mon-fri 09:00-18:00"},
+			// saturday.
+			at:    time.Date(2021, 1, 2, 12, 0, 0, 0, shanghai),
+			next:  time.Date(2021, 1, 4, 9, 0, 0, 0, shanghai),
+			state: true,
+		},
+		{
+			name:    "overlapped windows",
+			loc:     shanghai,
+			windows: []string{"mon 09:00-18:00", "mon 17:00-20:00"},
+			at:      time.Date(2021, 1, 4, 10, 0, 0, 0, shanghai),
+			next:    time.Date(2021, 1, 4, 20, 0, 0, 0, shanghai),
+			state:   false,
+		},
+		{
+			name:    "adjacent windows",
+			loc:     shanghai,
+			windows: []string{"mon 09:00-12:00", "mon 12:00-18:00"},
+			at:      time.Date(2021, 1, 4, 10, 0, 0, 0, shanghai),
+			next:    time.Date(2021, 1, 4, 18, 0, 0, 0, shanghai),
+			state:   false,
+		},
+		{
+			name:    "dst spring forward",
+			loc:     new_york,
+			windows: []string{"sun 01:00-03:30"},
+			at:      time.Date(2021, 3, 14, 1, 30, 0, 0, new_york),
+			next:    time.Date(2021, 3, 14, 3, 30, 0, 0, new_york),
+			state:   false,
+		},
+		{
+			name:    "dst fall back",
+			loc:     new_york,
+			windows: []string{"sun 03:00-04:00"},
+			at:      time.Date(2021, 11, 7, 0, 0, 0, 0, new_york),
+			next:    time.Date(2021, 11, 7, 3, 0, 0, 0, new_york),
+			state:   true,
+		},
+	} {
+		s, err := NewSchedule(c.loc, c.windows, nil, nil)
+		if err != nil {
+			t.Fatalf("%v: %v", c.name, err)
+		}
+
+		next, st := s.Next(c.at)
+		if !next.Equal(c.next) || st != c.state {
+			t.Errorf("%v: next %v %v, want %v %v", c.name, next, st, c.next, c.state)
+		}
+	}
+}
+
+func TestNewScheduleInvalid(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		windows []string
+		starts  []string
+		stops   []string
+	}{
+		{"empty", nil, nil, nil},
+		{"stop only", nil, nil, []string{"0 18 * * *"}},
+		{"invalid window", []string{"mon 09:00"}, nil, nil},
+		{"invalid cron", nil, []string{"0 9 * *"}, nil},
+	} {
+		if _, err := NewSchedule(nil, c.windows, c.starts, c.stops); err == nil {
+			t.Errorf("%v: schedule created", c.name)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package camera_service
 
 import (
 	"context"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/any"
@@ -11,23 +12,65 @@ import (
 	"google.golang.org/grpc/status"
 
 	driver "github.com/nayotta/metathings-component-camera/pkg/camera/driver"
+	pb "github.com/nayotta/metathings-component-camera/proto"
 	component "github.com/nayotta/metathings/pkg/component"
 )
 
+const (
+	LIST_RECORDINGS_DEFAULT_PAGE_SIZE    = 100
+	LIST_MOTION_EVENTS_DEFAULT_PAGE_SIZE = 100
+)
+
 type CameraService struct {
-	module *component.Module
-	driver driver.CameraDriver
+	module  *component.Module
+	cameras map[string]*camera
+	names   []string
 }
 
 func (cs *CameraService) logger() log.FieldLogger {
 	return cs.module.Logger()
 }
 
+func copy_outputs(xs []*driver.CameraDriverOutput) []*pb.Output {
+	var ys []*pb.Output
+
+	for _, x := range xs {
+		ys = append(ys, &pb.Output{
+			Label:     x.Label,
+			Url:       x.Url,
+			LiveId:    x.LiveId,
+			Playbacks: x.Playbacks,
+		})
+	}
+
+	return ys
+}
+
+func copy_stats(x *driver.FrameworkStats) (*pb.Stats, error) {
+	update_at, err := ptypes.TimestampProto(x.UpdateAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Stats{
+		Frame:      x.Frame,
+		Fps:        x.Fps,
+		BitRate:    x.BitRate,
+		Size:       x.Size,
+		Time:       x.Time,
+		DupFrames:  x.DupFrames,
+		DropFrames: x.DropFrames,
+		Speed:      x.Speed,
+		UpdateAt:   update_at,
+	}, nil
+}
+
 func (cs *CameraService) HANDLE_GRPC_Start(ctx context.Context, in *any.Any) (*any.Any, error) {
 	var err error
-	req := &empty.Empty{}
+	req := &pb.StartRequest{}
 
-	if err = ptypes.UnmarshalAny(in, req); err != nil {
+	// early clients start camera by empty message.
+	if err = unmarshal_camera_request(in, req); err != nil {
 		return nil, err
 	}
 
@@ -44,23 +87,51 @@ func (cs *CameraService) HANDLE_GRPC_Start(ctx context.Context, in *any.Any) (*a
 	return out, nil
 }
 
-func (cs *CameraService) Start(ctx context.Context, _ *empty.Empty) (*empty.Empty, error) {
-	err := cs.driver.Start()
+func (cs *CameraService) Start(ctx context.Context, req *pb.StartRequest) (*pb.StartResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate start request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
 	if err != nil {
-		cs.logger().WithError(err).Errorf("failed to start camera")
+		return nil, err
+	}
+
+	opt := &driver.CameraDriverStartOption{
+		FrameSize: req.GetFrameSize(),
+		FrameRate: int(req.GetFrameRate()),
+		BitRate:   req.GetBitRate(),
+		Codec:     req.GetCodec(),
+		Output:    req.GetOutput(),
+	}
+
+	if audio := req.GetAudio(); audio != nil {
+		val := audio.GetValue()
+		opt.Audio = &val
+	}
+
+	outputs, err := cam.driver.Start(opt)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to start camera")
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
-	cs.logger().Infof("camera started")
+	cam.logger.Infof("camera started")
 
-	return &empty.Empty{}, nil
+	res := &pb.StartResponse{
+		Outputs: copy_outputs(outputs),
+	}
+
+	return res, nil
 }
 
 func (cs *CameraService) HANDLE_GRPC_Stop(ctx context.Context, in *any.Any) (*any.Any, error) {
 	var err error
-	req := &empty.Empty{}
+	req := &pb.StopRequest{}
 
-	if err = ptypes.UnmarshalAny(in, req); err != nil {
+	if err = unmarshal_camera_request(in, req); err != nil {
 		return nil, err
 	}
 
@@ -77,29 +148,1067 @@ func (cs *CameraService) HANDLE_GRPC_Stop(ctx context.Context, in *any.Any) (*an
 	return out, nil
 }
 
-func (cs *CameraService) Stop(ctx context.Context, _ *empty.Empty) (*empty.Empty, error) {
-	err := cs.driver.Stop()
+func (cs *CameraService) Stop(ctx context.Context, req *pb.StopRequest) (*empty.Empty, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate stop request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	err = cam.driver.Stop()
 	if err != nil {
-		cs.logger().WithError(err).Errorf("failed to stop camera")
+		cam.logger.WithError(err).Errorf("failed to stop camera")
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
-	cs.logger().Infof("camera stop")
+	cam.logger.Infof("camera stop")
 
 	return &empty.Empty{}, nil
 }
 
-func (cs *CameraService) InitModuleService(m *component.Module) error {
+func (cs *CameraService) HANDLE_GRPC_GetStatus(ctx context.Context, in *any.Any) (*any.Any, error) {
 	var err error
+	req := &pb.GetStatusRequest{}
 
-	cs.module = m
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
 
-	drv_opt := &driver.CameraDriverOption{cs.module.Kernel().Config().Sub("driver").Raw()}
-	cs.driver, err = driver.NewCameraDriver(drv_opt.GetString("name"), drv_opt, "logger", cs.logger(), "module", cs.module)
+	res, err := cs.GetStatus(ctx, req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	cs.logger().WithField("driver", drv_opt.GetString("name")).Debugf("init camera driver")
 
-	return nil
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate get status request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	st := cam.driver.Status()
+
+	res := &pb.GetStatusResponse{
+		State:          st.State.String(),
+		Outputs:        copy_outputs(st.Outputs),
+		Recording:      st.Recording,
+		Pid:            int32(st.Pid),
+		RestartCount:   int32(st.RestartCount),
+		LastExitReason: st.LastExitReason,
+		Logs:           st.Logs,
+	}
+
+	if !st.StartAt.IsZero() {
+		start_at, err := ptypes.TimestampProto(st.StartAt)
+		if err != nil {
+			cam.logger.WithError(err).Errorf("failed to convert start time")
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		res.StartAt = start_at
+		res.Uptime = ptypes.DurationProto(st.Uptime())
+	}
+
+	if st.LastError != nil {
+		res.LastError = st.LastError.Error()
+	}
+
+	if st.Stats != nil {
+		res.Stats, err = copy_stats(st.Stats)
+		if err != nil {
+			cam.logger.WithError(err).Errorf("failed to convert stats")
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	if st.Schedule != nil {
+		res.Schedule = &pb.ScheduleStatus{
+			Override:  st.Schedule.Override,
+			NextState: st.Schedule.NextState.String(),
+		}
+
+		if !st.Schedule.NextTransitionAt.IsZero() {
+			res.Schedule.NextTransitionAt, err = ptypes.TimestampProto(st.Schedule.NextTransitionAt)
+			if err != nil {
+				cam.logger.WithError(err).Errorf("failed to convert next transition time")
+				return nil, status.Errorf(codes.Internal, err.Error())
+			}
+		}
+	}
+
+	cam.logger.Debugf("get camera status")
+
+	return res, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_Snapshot(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.SnapshotRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.Snapshot(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) Snapshot(ctx context.Context, req *pb.SnapshotRequest) (*pb.SnapshotResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate snapshot request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshotter driver.CameraDriverSnapshotter
+	ok := driver.AsCameraDriver(cam.driver, &snapshotter)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "snapshot not supported by driver")
+	}
+
+	opt := &driver.CameraDriverSnapshotOption{
+		Format:    req.GetFormat(),
+		FrameSize: req.GetFrameSize(),
+		Quality:   int(req.GetQuality()),
+	}
+
+	if store := req.GetStore(); store != nil {
+		val := store.GetValue()
+		opt.Store = &val
+	}
+
+	snap, err := snapshotter.Snapshot(opt)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to snapshot camera")
+		if err == driver.ErrSnapshotUnavailable {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	timestamp, err := ptypes.TimestampProto(snap.Timestamp)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to convert snapshot time")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.WithField("object", snap.Object).Debugf("camera snapshot")
+
+	res := &pb.SnapshotResponse{
+		Content:   snap.Content,
+		Format:    snap.Format,
+		Object:    snap.Object,
+		Timestamp: timestamp,
+	}
+
+	return res, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_StartRecording(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.StartRecordingRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.StartRecording(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) StartRecording(ctx context.Context, req *pb.StartRecordingRequest) (*empty.Empty, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate start recording request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var recorder driver.CameraDriverRecorder
+	ok := driver.AsCameraDriver(cam.driver, &recorder)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "recording not supported by driver")
+	}
+
+	err = recorder.StartRecording()
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to start recording")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.Infof("recording started")
+
+	return &empty.Empty{}, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_StopRecording(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.StopRecordingRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.StopRecording(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) StopRecording(ctx context.Context, req *pb.StopRecordingRequest) (*empty.Empty, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate stop recording request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var recorder driver.CameraDriverRecorder
+	ok := driver.AsCameraDriver(cam.driver, &recorder)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "recording not supported by driver")
+	}
+
+	err = recorder.StopRecording()
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to stop recording")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.Infof("recording stopped")
+
+	return &empty.Empty{}, nil
+}
+
+func copy_recording(x *driver.CameraDriverRecording) (*pb.Recording, error) {
+	start_at, err := ptypes.TimestampProto(x.StartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	end_at, err := ptypes.TimestampProto(x.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Recording{
+		Name:      x.Name,
+		StartAt:   start_at,
+		EndAt:     end_at,
+		Duration:  ptypes.DurationProto(x.EndAt.Sub(x.StartAt)),
+		Size:      x.Size,
+		Format:    x.Format,
+		Recording: x.Recording,
+	}, nil
+}
+
+func copy_clip(x *driver.CameraDriverClip) (*pb.Clip, error) {
+	start_at, err := ptypes.TimestampProto(x.StartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	end_at, err := ptypes.TimestampProto(x.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Clip{
+		Object:  x.Object,
+		Parts:   x.Parts,
+		Size:    x.Size,
+		Format:  x.Format,
+		StartAt: start_at,
+		EndAt:   end_at,
+	}, nil
+}
+
+func recording_error_code(err error) codes.Code {
+	switch err {
+	case driver.ErrRecordingNotFound:
+		return codes.NotFound
+	case driver.ErrInvalidClipRange:
+		return codes.InvalidArgument
+	default:
+		return codes.Internal
+	}
+}
+
+// recording_store returns driver of camera as recording store, or unimplemented error.
+func (cs *CameraService) recording_store(cam *camera) (driver.CameraDriverRecordingStore, error) {
+	var store driver.CameraDriverRecordingStore
+	ok := driver.AsCameraDriver(cam.driver, &store)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "recording not supported by driver")
+	}
+
+	return store, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_ListRecordings(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.ListRecordingsRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.ListRecordings(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) ListRecordings(ctx context.Context, req *pb.ListRecordingsRequest) (*pb.ListRecordingsResponse, error) {
+	var begin, end time.Time
+
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate list recordings request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetBegin() != nil {
+		if begin, err = ptypes.Timestamp(req.GetBegin()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if req.GetEnd() != nil {
+		if end, err = ptypes.Timestamp(req.GetEnd()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	store, err := cs.recording_store(cam)
+	if err != nil {
+		return nil, err
+	}
+
+	recs, err := store.ListRecordings(begin, end)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to list recordings")
+		return nil, status.Errorf(recording_error_code(err), err.Error())
+	}
+
+	// page token is name of the first recording in page.
+	offset := 0
+	if token := req.GetPageToken(); token != "" {
+		offset = -1
+		for i, rec := range recs {
+			if rec.Name == token {
+				offset = i
+				break
+			}
+		}
+
+		if offset < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+		}
+	}
+
+	page_size := int(req.GetPageSize())
+	if page_size == 0 {
+		page_size = LIST_RECORDINGS_DEFAULT_PAGE_SIZE
+	}
+
+	res := &pb.ListRecordingsResponse{}
+	recs = recs[offset:]
+	if len(recs) > page_size {
+		res.NextPageToken = recs[page_size].Name
+		recs = recs[:page_size]
+	}
+
+	for _, rec := range recs {
+		x, err := copy_recording(rec)
+		if err != nil {
+			cam.logger.WithError(err).Errorf("failed to convert recording")
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		res.Recordings = append(res.Recordings, x)
+	}
+
+	cam.logger.Debugf("list recordings")
+
+	return res, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_GetRecording(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.GetRecordingRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.GetRecording(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) GetRecording(ctx context.Context, req *pb.GetRecordingRequest) (*pb.GetRecordingResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate get recording request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := cs.recording_store(cam)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := store.GetRecording(req.GetName())
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to get recording")
+		return nil, status.Errorf(recording_error_code(err), err.Error())
+	}
+
+	x, err := copy_recording(rec)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to convert recording")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.WithField("name", rec.Name).Debugf("get recording")
+
+	return &pb.GetRecordingResponse{Recording: x}, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_ExportClip(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.ExportClipRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.ExportClip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) ExportClip(ctx context.Context, req *pb.ExportClipRequest) (*pb.ExportClipResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate export clip request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	begin, err := ptypes.Timestamp(req.GetBegin())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	end, err := ptypes.Timestamp(req.GetEnd())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	store, err := cs.recording_store(cam)
+	if err != nil {
+		return nil, err
+	}
+
+	clip, err := store.ExportClip(begin, end)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to export clip")
+		return nil, status.Errorf(recording_error_code(err), err.Error())
+	}
+
+	x, err := copy_clip(clip)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to convert clip")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.WithFields(log.Fields{
+		"object": clip.Object,
+		"parts":  len(clip.Parts),
+	}).Infof("clip exported")
+
+	return &pb.ExportClipResponse{Clip: x}, nil
+}
+
+func copy_event(x *driver.CameraDriverEvent) (*pb.Event, error) {
+	trigger_at, err := ptypes.TimestampProto(x.TriggerAt)
+	if err != nil {
+		return nil, err
+	}
+
+	start_at, err := ptypes.TimestampProto(x.StartAt)
+	if err != nil {
+		return nil, err
+	}
+
+	end_at, err := ptypes.TimestampProto(x.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Event{
+		Object:    x.Object,
+		Label:     x.Label,
+		TriggerAt: trigger_at,
+		StartAt:   start_at,
+		EndAt:     end_at,
+	}, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_TriggerEvent(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.TriggerEventRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.TriggerEvent(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) TriggerEvent(ctx context.Context, req *pb.TriggerEventRequest) (*pb.TriggerEventResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate trigger event request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var trigger driver.CameraDriverEventTrigger
+	ok := driver.AsCameraDriver(cam.driver, &trigger)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "event not supported by driver")
+	}
+
+	opt := &driver.CameraDriverEventOption{
+		Label: req.GetLabel(),
+	}
+
+	if req.GetPre() != nil {
+		pre, err := ptypes.Duration(req.GetPre())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		opt.Pre = &pre
+	}
+
+	if req.GetPost() != nil {
+		post, err := ptypes.Duration(req.GetPost())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		opt.Post = &post
+	}
+
+	evt, err := trigger.TriggerEvent(opt)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to trigger event")
+		switch err {
+		case driver.ErrInvalidEventDuration:
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		case driver.ErrBufferUnavailable:
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
+
+	x, err := copy_event(evt)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to convert event")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.WithFields(log.Fields{
+		"object": evt.Object,
+		"label":  evt.Label,
+	}).Infof("event triggered")
+
+	return &pb.TriggerEventResponse{Event: x}, nil
+}
+
+func (cs *CameraService) InitModuleService(m *component.Module) error {
+	var err error
+
+	cs.module = m
+
+	if err = cs.init_cameras(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func copy_motion_event(x *driver.CameraDriverMotionEvent) (*pb.MotionEvent, error) {
+	ts, err := ptypes.TimestampProto(x.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.MotionEvent{
+		Id:        x.Id,
+		Timestamp: ts,
+		Score:     x.Score,
+		Region: &pb.Region{
+			X:      x.Region.X,
+			Y:      x.Region.Y,
+			Width:  x.Region.Width,
+			Height: x.Region.Height,
+		},
+		Object:   x.Object,
+		Snapshot: x.Snapshot,
+	}, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_ListMotionEvents(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.ListMotionEventsRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.ListMotionEvents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) ListMotionEvents(ctx context.Context, req *pb.ListMotionEventsRequest) (*pb.ListMotionEventsResponse, error) {
+	var begin, end time.Time
+
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate list motion events request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetBegin() != nil {
+		if begin, err = ptypes.Timestamp(req.GetBegin()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if req.GetEnd() != nil {
+		if end, err = ptypes.Timestamp(req.GetEnd()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	var detector driver.CameraDriverMotionDetector
+	ok := driver.AsCameraDriver(cam.driver, &detector)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "motion detection not supported by driver")
+	}
+
+	evts, err := detector.ListMotionEvents(begin, end)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to list motion events")
+		if err == driver.ErrMotionUnavailable {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	// page token is id of the first event in page.
+	offset := 0
+	if token := req.GetPageToken(); token != "" {
+		offset = -1
+		for i, evt := range evts {
+			if evt.Id == token {
+				offset = i
+				break
+			}
+		}
+
+		if offset < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+		}
+	}
+
+	page_size := int(req.GetPageSize())
+	if page_size == 0 {
+		page_size = LIST_MOTION_EVENTS_DEFAULT_PAGE_SIZE
+	}
+
+	res := &pb.ListMotionEventsResponse{}
+	evts = evts[offset:]
+	if len(evts) > page_size {
+		res.NextPageToken = evts[page_size].Id
+		evts = evts[:page_size]
+	}
+
+	for _, evt := range evts {
+		x, err := copy_motion_event(evt)
+		if err != nil {
+			cam.logger.WithError(err).Errorf("failed to convert motion event")
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		res.Events = append(res.Events, x)
+	}
+
+	cam.logger.Debugf("list motion events")
+
+	return res, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_AssembleTimelapse(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.AssembleTimelapseRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.AssembleTimelapse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) AssembleTimelapse(ctx context.Context, req *pb.AssembleTimelapseRequest) (*pb.AssembleTimelapseResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate assemble timelapse request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var timelapser driver.CameraDriverTimelapser
+	ok := driver.AsCameraDriver(cam.driver, &timelapser)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "timelapse not supported by driver")
+	}
+
+	tl, err := timelapser.AssembleTimelapse()
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to assemble timelapse")
+		if err == driver.ErrTimelapseEmpty {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	clip, err := copy_clip(tl.Clip)
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to convert clip")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.WithFields(log.Fields{
+		"sequence": tl.Sequence,
+		"object":   tl.Clip.Object,
+	}).Infof("timelapse assembled")
+
+	return &pb.AssembleTimelapseResponse{
+		Timelapse: &pb.Timelapse{
+			Sequence: tl.Sequence,
+			Frames:   uint32(tl.Frames),
+			Clip:     clip,
+		},
+	}, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_RotateStreamKey(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.RotateStreamKeyRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.RotateStreamKey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) RotateStreamKey(ctx context.Context, req *pb.RotateStreamKeyRequest) (*pb.RotateStreamKeyResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate rotate stream key request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var rotator driver.CameraDriverStreamKeyRotator
+	ok := driver.AsCameraDriver(cam.driver, &rotator)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "rotate stream key not supported by driver")
+	}
+
+	outputs, err := rotator.RotateStreamKey()
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to rotate stream key")
+		if err == driver.ErrNotStreaming {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.Infof("stream key rotated")
+
+	return &pb.RotateStreamKeyResponse{
+		Outputs: copy_outputs(outputs),
+	}, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_NegotiateWebrtc(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.NegotiateWebrtcRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.NegotiateWebrtc(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) NegotiateWebrtc(ctx context.Context, req *pb.NegotiateWebrtcRequest) (*pb.NegotiateWebrtcResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate negotiate webrtc request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var negotiator driver.CameraDriverWebrtcNegotiator
+	ok := driver.AsCameraDriver(cam.driver, &negotiator)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "webrtc not supported by driver")
+	}
+
+	sess, err := negotiator.NegotiateWebrtc(req.GetLabel(), req.GetOffer())
+	if err != nil {
+		cam.logger.WithError(err).Errorf("failed to negotiate webrtc")
+		switch err {
+		case driver.ErrInvalidWebrtcOffer:
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		case driver.ErrWebrtcOutputNotFound:
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		case driver.ErrNotStreaming:
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		case driver.ErrTooManyWebrtcSessions:
+			return nil, status.Errorf(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.WithFields(log.Fields{
+		"session": sess.Id,
+		"label":   sess.Label,
+	}).Infof("webrtc negotiated")
+
+	return &pb.NegotiateWebrtcResponse{
+		Session: sess.Id,
+		Label:   sess.Label,
+		Answer:  sess.Answer,
+	}, nil
+}
+
+func (cs *CameraService) HANDLE_GRPC_CloseWebrtc(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &pb.CloseWebrtcRequest{}
+
+	if err = unmarshal_camera_request(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.CloseWebrtc(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) CloseWebrtc(ctx context.Context, req *pb.CloseWebrtcRequest) (*empty.Empty, error) {
+	err := req.Validate()
+	if err != nil {
+		cs.logger().WithError(err).Warningf("failed to validate close webrtc request")
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	cam, err := cs.camera(req.GetCamera())
+	if err != nil {
+		return nil, err
+	}
+
+	var negotiator driver.CameraDriverWebrtcNegotiator
+	ok := driver.AsCameraDriver(cam.driver, &negotiator)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "webrtc not supported by driver")
+	}
+
+	if err = negotiator.CloseWebrtc(req.GetSession()); err != nil {
+		cam.logger.WithError(err).Errorf("failed to close webrtc")
+		if err == driver.ErrWebrtcSessionNotFound {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	cam.logger.WithField("session", req.GetSession()).Infof("webrtc closed")
+
+	return &empty.Empty{}, nil
 }
@@ -0,0 +1,184 @@
+package camera_service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	driver "github.com/nayotta/metathings-component-camera/pkg/camera/driver"
+	pb "github.com/nayotta/metathings-component-camera/proto"
+)
+
+/*
+ * Cameras: one component instance drives many cameras, each camera has own driver.
+ * Options:
+ *   cameras:
+ *     <name>:  // [a-z0-9_-]+
+ *       name: <driver>
+ *       [ work_dir: <dir> ]  // default `<tmp>/metathings-camera/cameras/<name>`.
+ *       ...  // same as `driver` section.
+ *     ...
+ *
+ * `cameras` and `driver` are exclusive, `driver` is the only camera, named empty.
+ * Requests select camera by `camera` field, it could be empty when only one camera.
+ * Objects of camera are put under `cameras/<name>/`, like `cameras/<name>/snapshot.jpg`.
+ * Listen addresses of outputs, like `rtsp.address` and `http.address`, should be different between cameras.
+ */
+
+const (
+	CAMERAS_WORK_DIR = "metathings-camera/cameras"
+)
+
+var (
+	camera_name_regexp = regexp.MustCompile(`^[a-z0-9_-]+$`)
+)
+
+type camera struct {
+	name        string
+	driver_name string
+	driver      driver.CameraDriver
+	logger      log.FieldLogger
+}
+
+// camera returns camera by name, empty name means the only camera.
+func (cs *CameraService) camera(name string) (*camera, error) {
+	name = strings.ToLower(name)
+
+	if name == "" {
+		if len(cs.names) != 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "camera required")
+		}
+		name = cs.names[0]
+	}
+
+	cam, ok := cs.cameras[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "camera not found")
+	}
+
+	return cam, nil
+}
+
+// unmarshal_camera_request unmarshals request of camera,
+// empty message from early clients is zero request of the only camera.
+func unmarshal_camera_request(in *any.Any, req proto.Message) error {
+	if ptypes.Is(in, &empty.Empty{}) {
+		return nil
+	}
+
+	return ptypes.UnmarshalAny(in, req)
+}
+
+func (cs *CameraService) HANDLE_GRPC_ListCameras(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &empty.Empty{}
+
+	if err = ptypes.UnmarshalAny(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.ListCameras(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) ListCameras(ctx context.Context, _ *empty.Empty) (*pb.ListCamerasResponse, error) {
+	var cameras []*pb.Camera
+
+	for _, name := range cs.names {
+		cam := cs.cameras[name]
+		cameras = append(cameras, &pb.Camera{
+			Name:   cam.name,
+			Driver: cam.driver_name,
+			State:  cam.driver.State().String(),
+		})
+	}
+
+	cs.logger().Debugf("list cameras")
+
+	return &pb.ListCamerasResponse{Cameras: cameras}, nil
+}
+
+func (cs *CameraService) add_camera(name string, drv_opt *driver.CameraDriverOption, mdl driver.CameraDriverModule) error {
+	logger := cs.logger()
+	if name != "" {
+		logger = logger.WithField("camera", name)
+	}
+
+	drv, err := driver.NewCameraDriver(drv_opt.GetString("name"), drv_opt, "logger", logger, "module", mdl)
+	if err != nil {
+		return err
+	}
+
+	cs.cameras[name] = &camera{name: name, driver_name: drv_opt.GetString("name"), driver: drv, logger: logger}
+	cs.names = append(cs.names, name)
+
+	logger.WithField("driver", drv_opt.GetString("name")).Debugf("init camera driver")
+
+	return nil
+}
+
+func (cs *CameraService) init_cameras() error {
+	v := cs.module.Kernel().Config().Raw()
+
+	cs.cameras = map[string]*camera{}
+	cs.names = nil
+
+	if !v.IsSet("cameras") {
+		drv_opt := &driver.CameraDriverOption{Viper: v.Sub("driver")}
+		return cs.add_camera("", drv_opt, driver.NewCameraDriverModule(cs.module, ""))
+	}
+
+	if v.IsSet("driver") {
+		return fmt.Errorf("cameras and driver are exclusive")
+	}
+
+	var names []string
+	for name := range v.GetStringMap("cameras") {
+		if !camera_name_regexp.MatchString(name) {
+			return fmt.Errorf("invalid camera name: %v", name)
+		}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("cameras required")
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sub := v.Sub("cameras." + name)
+		if sub == nil {
+			return fmt.Errorf("invalid camera: %v", name)
+		}
+
+		drv_opt := &driver.CameraDriverOption{Viper: sub}
+		drv_opt.SetDefault("work_dir", filepath.Join(os.TempDir(), CAMERAS_WORK_DIR, name))
+
+		if err := cs.add_camera(name, drv_opt, driver.NewCameraDriverModule(cs.module, "cameras/"+name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package camera_service
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	driver "github.com/nayotta/metathings-component-camera/pkg/camera/driver"
+	pb "github.com/nayotta/metathings-component-camera/proto"
+)
+
+type test_camera_driver struct {
+	name string
+	st   *driver.CameraDriverState
+}
+
+func (d *test_camera_driver) Start(opt *driver.CameraDriverStartOption) ([]*driver.CameraDriverOutput, error) {
+	d.st = driver.CAMERA_DRIVER_STATE_ON
+	return []*driver.CameraDriverOutput{{Label: "0", Url: "rtmp://localhost/live/" + d.name}}, nil
+}
+
+func (d *test_camera_driver) Stop() error {
+	d.st = driver.CAMERA_DRIVER_STATE_OFF
+	return nil
+}
+
+func (d *test_camera_driver) State() *driver.CameraDriverState {
+	return d.st
+}
+
+func (d *test_camera_driver) Status() *driver.CameraDriverStatus {
+	return &driver.CameraDriverStatus{State: d.st}
+}
+
+func (d *test_camera_driver) Snapshot(opt *driver.CameraDriverSnapshotOption) (*driver.CameraDriverSnapshot, error) {
+	return &driver.CameraDriverSnapshot{
+		Content:   []byte(d.name),
+		Format:    "jpeg",
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func new_test_camera_service(names ...string) *CameraService {
+	logger := log.New()
+	logger.Out = ioutil.Discard
+
+	cs := &CameraService{cameras: map[string]*camera{}}
+	for _, name := range names {
+		cs.cameras[name] = &camera{
+			name:   name,
+			driver: &test_camera_driver{name: name, st: driver.CAMERA_DRIVER_STATE_OFF},
+			logger: logger,
+		}
+		cs.names = append(cs.names, name)
+	}
+
+	return cs
+}
+
+func marshal_any(t *testing.T, msg proto.Message) *any.Any {
+	in, err := ptypes.MarshalAny(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return in
+}
+
+func get_status(t *testing.T, cs *CameraService, in *any.Any) (*pb.GetStatusResponse, error) {
+	out, err := cs.HANDLE_GRPC_GetStatus(context.TODO(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.GetStatusResponse{}
+	if err = ptypes.UnmarshalAny(out, res); err != nil {
+		t.Fatal(err)
+	}
+
+	return res, nil
+}
+
+func snapshot(t *testing.T, cs *CameraService, in *any.Any) (*pb.SnapshotResponse, error) {
+	out, err := cs.HANDLE_GRPC_Snapshot(context.TODO(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &pb.SnapshotResponse{}
+	if err = ptypes.UnmarshalAny(out, res); err != nil {
+		t.Fatal(err)
+	}
+
+	return res, nil
+}
+
+func TestCameraRequestOfOnlyCamera(t *testing.T) {
+	cs := new_test_camera_service("")
+	in := marshal_any(t, &empty.Empty{})
+
+	out, err := cs.HANDLE_GRPC_Start(context.TODO(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := &pb.StartResponse{}
+	if err = ptypes.UnmarshalAny(out, start); err != nil {
+		t.Fatal(err)
+	}
+	if len(start.Outputs) != 1 {
+		t.Errorf("outputs %v", start.Outputs)
+	}
+
+	st, err := get_status(t, cs, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.State != "on" {
+		t.Errorf("state %v, want on", st.State)
+	}
+
+	snap, err := snapshot(t, cs, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Format != "jpeg" {
+		t.Errorf("format %v, want jpeg", snap.Format)
+	}
+}
+
+func TestCameraRequestOfCameras(t *testing.T) {
+	cs := new_test_camera_service("front", "back")
+
+	for _, in := range []*any.Any{
+		marshal_any(t, &empty.Empty{}),
+		marshal_any(t, &pb.GetStatusRequest{}),
+	} {
+		if _, err := get_status(t, cs, in); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("request %v: error %v, want %v", in.TypeUrl, err, codes.InvalidArgument)
+		}
+	}
+
+	if _, err := cs.HANDLE_GRPC_Start(context.TODO(), marshal_any(t, &pb.StartRequest{Camera: "back"})); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, state := range map[string]string{"front": "off", "back": "on", "BACK": "on"} {
+		st, err := get_status(t, cs, marshal_any(t, &pb.GetStatusRequest{Camera: name}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.State != state {
+			t.Errorf("camera %v: state %v, want %v", name, st.State, state)
+		}
+	}
+
+	for _, name := range []string{"front", "back"} {
+		snap, err := snapshot(t, cs, marshal_any(t, &pb.SnapshotRequest{Camera: name}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(snap.Content) != name {
+			t.Errorf("camera %v: snapshot of %s", name, snap.Content)
+		}
+	}
+
+	if _, err := snapshot(t, cs, marshal_any(t, &empty.Empty{})); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("error %v, want %v", err, codes.InvalidArgument)
+	}
+
+	if _, err := snapshot(t, cs, marshal_any(t, &pb.SnapshotRequest{Camera: "side"})); status.Code(err) != codes.NotFound {
+		t.Errorf("error %v, want %v", err, codes.NotFound)
+	}
+}
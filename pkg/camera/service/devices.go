@@ -0,0 +1,108 @@
+package camera_service
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v4l2 "github.com/nayotta/metathings-component-camera/pkg/camera/v4l2"
+	pb "github.com/nayotta/metathings-component-camera/proto"
+)
+
+func copy_frame_size(x *v4l2.FrameSize) *pb.FrameSize {
+	y := &pb.FrameSize{
+		Width:             x.Width,
+		Height:            x.Height,
+		MaxWidth:          x.MaxWidth,
+		MaxHeight:         x.MaxHeight,
+		StepWidth:         x.StepWidth,
+		StepHeight:        x.StepHeight,
+		StepwiseIntervals: x.StepwiseIntervals,
+	}
+
+	for _, i := range x.Intervals {
+		y.Intervals = append(y.Intervals, &pb.FrameInterval{
+			Numerator:   i.Numerator,
+			Denominator: i.Denominator,
+		})
+	}
+
+	return y
+}
+
+func copy_device(x *v4l2.Device) *pb.Device {
+	y := &pb.Device{
+		Name:    x.Name,
+		Path:    x.Path,
+		Card:    x.Card,
+		Driver:  x.Driver,
+		BusInfo: x.BusInfo,
+		Capture: x.Capture,
+		Index:   int32(x.Index),
+		Vendor:  x.Vendor,
+		Product: x.Product,
+		Serial:  x.Serial,
+		ById:    x.ById,
+		ByPath:  x.ByPath,
+	}
+
+	for _, f := range x.Formats {
+		format := &pb.DeviceFormat{
+			PixelFormat: f.PixelFormat,
+			Description: f.Description,
+			Compressed:  f.Compressed,
+		}
+		for _, s := range f.FrameSizes {
+			format.FrameSizes = append(format.FrameSizes, copy_frame_size(s))
+		}
+		y.Formats = append(y.Formats, format)
+	}
+
+	if x.Error != nil {
+		y.Error = x.Error.Error()
+	}
+
+	return y
+}
+
+func (cs *CameraService) HANDLE_GRPC_ListDevices(ctx context.Context, in *any.Any) (*any.Any, error) {
+	var err error
+	req := &empty.Empty{}
+
+	if err = ptypes.UnmarshalAny(in, req); err != nil {
+		return nil, err
+	}
+
+	res, err := cs.ListDevices(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := ptypes.MarshalAny(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (cs *CameraService) ListDevices(ctx context.Context, _ *empty.Empty) (*pb.ListDevicesResponse, error) {
+	devs, err := v4l2.ListDevices(nil)
+	if err != nil {
+		cs.logger().WithError(err).Errorf("failed to list devices")
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	var devices []*pb.Device
+	for _, dev := range devs {
+		devices = append(devices, copy_device(dev))
+	}
+
+	cs.logger().WithField("count", len(devices)).Debugf("list devices")
+
+	return &pb.ListDevicesResponse{Devices: devices}, nil
+}
@@ -0,0 +1,406 @@
+package camera_v4l2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DEFAULT_SYSFS_ROOT = "/sys"
+	DEFAULT_DEV_ROOT   = "/dev"
+
+	// frame rates differ less than tolerance are same, like `29.97` and `30000/1001`.
+	frame_rate_tolerance = 0.01
+)
+
+type Interval struct {
+	Numerator   uint32
+	Denominator uint32
+}
+
+// FrameRate returns frames per second of interval.
+func (i *Interval) FrameRate() float64 {
+	if i.Numerator == 0 {
+		return 0
+	}
+
+	return float64(i.Denominator) / float64(i.Numerator)
+}
+
+func (i *Interval) String() string {
+	if i.Numerator == 1 {
+		return strconv.FormatUint(uint64(i.Denominator), 10)
+	}
+
+	return fmt.Sprintf("%d/%d", i.Denominator, i.Numerator)
+}
+
+// FrameSize is discrete frame size, or stepwise from Width x Height to MaxWidth x MaxHeight if MaxWidth set.
+type FrameSize struct {
+	Width      uint32
+	Height     uint32
+	MaxWidth   uint32
+	MaxHeight  uint32
+	StepWidth  uint32
+	StepHeight uint32
+	// Intervals are discrete intervals, or min and max intervals if StepwiseIntervals.
+	Intervals         []*Interval
+	StepwiseIntervals bool
+}
+
+func (s *FrameSize) Stepwise() bool {
+	return s.MaxWidth > 0
+}
+
+func (s *FrameSize) String() string {
+	if s.Stepwise() {
+		return fmt.Sprintf("%dx%d-%dx%d", s.Width, s.Height, s.MaxWidth, s.MaxHeight)
+	}
+
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+func in_step(v, min, max, step uint32) bool {
+	if v < min || v > max {
+		return false
+	}
+
+	return step <= 1 || (v-min)%step == 0
+}
+
+func (s *FrameSize) Contains(width, height uint32) bool {
+	if s.Stepwise() {
+		return in_step(width, s.Width, s.MaxWidth, s.StepWidth) && in_step(height, s.Height, s.MaxHeight, s.StepHeight)
+	}
+
+	return s.Width == width && s.Height == height
+}
+
+// FrameRates returns frame rates of intervals, like `30`, or range like `1-30` if stepwise.
+func (s *FrameSize) FrameRates() []string {
+	if s.StepwiseIntervals && len(s.Intervals) == 2 {
+		return []string{s.Intervals[1].String() + "-" + s.Intervals[0].String()}
+	}
+
+	var rates []string
+	for _, i := range s.Intervals {
+		rates = append(rates, i.String())
+	}
+
+	return rates
+}
+
+// SupportsFrameRate reports frame rate is supported, no intervals means unknown and supported.
+func (s *FrameSize) SupportsFrameRate(rate float64) bool {
+	if len(s.Intervals) == 0 {
+		return true
+	}
+
+	if s.StepwiseIntervals && len(s.Intervals) == 2 {
+		return rate <= s.Intervals[0].FrameRate()+frame_rate_tolerance && rate >= s.Intervals[1].FrameRate()-frame_rate_tolerance
+	}
+
+	for _, i := range s.Intervals {
+		if math.Abs(i.FrameRate()-rate) < frame_rate_tolerance {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Format struct {
+	// PixelFormat is fourcc of pixel format, like `YUYV` and `MJPG`.
+	PixelFormat string
+	Description string
+	Compressed  bool
+	FrameSizes  []*FrameSize
+}
+
+type Device struct {
+	// Name is name of device node, like `video0`.
+	Name    string
+	Path    string
+	Card    string
+	Driver  string
+	BusInfo string
+	// Index is index of device node in sysfs, capture node of usb camera is 0.
+	Index int
+	// Vendor, Product and Serial are usb vendor id, product id and serial number, empty if not usb device.
+	Vendor  string
+	Product string
+	Serial  string
+	// ById and ByPath are names of links in `/dev/v4l/by-id` and `/dev/v4l/by-path`.
+	ById   string
+	ByPath string
+	// Capture reports device captures video, false for metadata nodes of usb cameras.
+	Capture bool
+	Formats []*Format
+	// Error is error of querying device by ioctls, like permission denied, device info comes from sysfs only.
+	Error error
+}
+
+// FrameSizes returns distinct frame sizes of all formats.
+func (d *Device) FrameSizes() []string {
+	var sizes []string
+	seen := map[string]bool{}
+
+	for _, f := range d.Formats {
+		for _, s := range f.FrameSizes {
+			if str := s.String(); !seen[str] {
+				seen[str] = true
+				sizes = append(sizes, str)
+			}
+		}
+	}
+
+	return sizes
+}
+
+// FrameRates returns distinct frame rates of frame sizes contain width x height,
+// or of all frame sizes if width is zero.
+func (d *Device) FrameRates(width, height uint32) []string {
+	var rates []string
+	seen := map[string]bool{}
+
+	for _, f := range d.Formats {
+		for _, s := range f.FrameSizes {
+			if width > 0 && !s.Contains(width, height) {
+				continue
+			}
+
+			for _, r := range s.FrameRates() {
+				if !seen[r] {
+					seen[r] = true
+					rates = append(rates, r)
+				}
+			}
+		}
+	}
+
+	return rates
+}
+
+// SupportsFrameSize reports any format supports width x height.
+func (d *Device) SupportsFrameSize(width, height uint32) bool {
+	for _, f := range d.Formats {
+		for _, s := range f.FrameSizes {
+			if s.Contains(width, height) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SupportsFrameRate reports any format supports frame rate at width x height,
+// or at any frame size if width is zero.
+func (d *Device) SupportsFrameRate(width, height uint32, rate float64) bool {
+	for _, f := range d.Formats {
+		for _, s := range f.FrameSizes {
+			if width > 0 && !s.Contains(width, height) {
+				continue
+			}
+
+			if s.SupportsFrameRate(rate) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ParseFrameSize parses frame size like `640x480`.
+func ParseFrameSize(s string) (uint32, uint32, error) {
+	ss := strings.SplitN(s, "x", 2)
+	if len(ss) != 2 {
+		return 0, 0, ErrInvalidFrameSize
+	}
+
+	w, err := strconv.ParseUint(ss[0], 10, 32)
+	if err != nil || w == 0 {
+		return 0, 0, ErrInvalidFrameSize
+	}
+
+	h, err := strconv.ParseUint(ss[1], 10, 32)
+	if err != nil || h == 0 {
+		return 0, 0, ErrInvalidFrameSize
+	}
+
+	return uint32(w), uint32(h), nil
+}
+
+// ParseFrameRate parses frame rate like `30` or `30000/1001`.
+func ParseFrameRate(s string) (float64, error) {
+	ss := strings.SplitN(s, "/", 2)
+
+	n, err := strconv.ParseFloat(ss[0], 64)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidFrameRate
+	}
+
+	if len(ss) == 1 {
+		return n, nil
+	}
+
+	d, err := strconv.ParseFloat(ss[1], 64)
+	if err != nil || d <= 0 {
+		return 0, ErrInvalidFrameRate
+	}
+
+	return n / d, nil
+}
+
+type ListOption struct {
+	// SysfsRoot is mount point of sysfs, default `/sys`.
+	SysfsRoot string
+	// DevRoot is directory of device nodes, default `/dev`.
+	DevRoot string
+}
+
+func (o *ListOption) sysfs_root() string {
+	if o == nil || o.SysfsRoot == "" {
+		return DEFAULT_SYSFS_ROOT
+	}
+	return o.SysfsRoot
+}
+
+func (o *ListOption) dev_root() string {
+	if o == nil || o.DevRoot == "" {
+		return DEFAULT_DEV_ROOT
+	}
+	return o.DevRoot
+}
+
+func (o *ListOption) by_id_dir() string {
+	return filepath.Join(o.dev_root(), "v4l", "by-id")
+}
+
+func (o *ListOption) by_path_dir() string {
+	return filepath.Join(o.dev_root(), "v4l", "by-path")
+}
+
+func read_sysfs_string(file string) string {
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(buf))
+}
+
+// read_usb_info reads ids of usb device, which is ancestor of usb interface of device node.
+func read_usb_info(dev *Device, dir string) {
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < 3; i++ {
+		if vendor := read_sysfs_string(filepath.Join(dir, "idVendor")); vendor != "" {
+			dev.Vendor = vendor
+			dev.Product = read_sysfs_string(filepath.Join(dir, "idProduct"))
+			dev.Serial = read_sysfs_string(filepath.Join(dir, "serial"))
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// find_link returns name of the first link in dir to device node.
+func find_link(dir, name string) string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	for _, info := range infos {
+		if target, err := os.Readlink(filepath.Join(dir, info.Name())); err == nil && filepath.Base(target) == name {
+			return info.Name()
+		}
+	}
+
+	return ""
+}
+
+func video_index(name string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(name, "video"))
+	if err != nil {
+		return math.MaxInt32
+	}
+
+	return n
+}
+
+// ListDevices lists video devices in `class/video4linux` of sysfs, ordered by device number,
+// formats are queried by ioctls, failure of querying is set in Error of device.
+func ListDevices(opt *ListOption) ([]*Device, error) {
+	class := filepath.Join(opt.sysfs_root(), "class", "video4linux")
+
+	infos, err := ioutil.ReadDir(class)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var devs []*Device
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasPrefix(name, "video") {
+			continue
+		}
+
+		dir := filepath.Join(class, name)
+		dev := &Device{
+			Name: name,
+			Path: filepath.Join(opt.dev_root(), name),
+			Card: read_sysfs_string(filepath.Join(dir, "name")),
+		}
+
+		if drv, err := os.Readlink(filepath.Join(dir, "device", "driver")); err == nil {
+			dev.Driver = filepath.Base(drv)
+		}
+
+		if idx, err := strconv.Atoi(read_sysfs_string(filepath.Join(dir, "index"))); err == nil {
+			dev.Index = idx
+		}
+
+		read_usb_info(dev, filepath.Join(dir, "device"))
+		dev.ById = find_link(opt.by_id_dir(), name)
+		dev.ByPath = find_link(opt.by_path_dir(), name)
+
+		dev.Error = query(dev)
+
+		devs = append(devs, dev)
+	}
+
+	sort.Slice(devs, func(i, j int) bool {
+		return video_index(devs[i].Name) < video_index(devs[j].Name)
+	})
+
+	return devs, nil
+}
+
+// QueryDevice queries device of path by ioctls.
+func QueryDevice(path string) (*Device, error) {
+	dev := &Device{
+		Name: filepath.Base(path),
+		Path: path,
+	}
+
+	if err := query(dev); err != nil {
+		return nil, err
+	}
+
+	return dev, nil
+}
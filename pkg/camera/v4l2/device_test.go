@@ -0,0 +1,265 @@
+package camera_v4l2
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type test_video_device struct {
+	name  string
+	card  string
+	index string
+	// usb device in sysfs, empty if not usb device.
+	usb     string
+	vendor  string
+	product string
+	serial  string
+	by_id   string
+	by_path string
+}
+
+var test_video_devices = []test_video_device{
+	{"video0", "HD Pro Webcam C920", "0", "1-1", "046d", "082d", "A1B2C3D4", "usb-046d_HD_Pro_Webcam_C920_A1B2C3D4-video-index0", "platform-xhci-hcd.0-usb-0:1:1.0-video-index0"},
+	{"video1", "HD Pro Webcam C920", "1", "1-1", "046d", "082d", "A1B2C3D4", "usb-046d_HD_Pro_Webcam_C920_A1B2C3D4-video-index1", "platform-xhci-hcd.0-usb-0:1:1.0-video-index1"},
+	{"video2", "C270 HD WEBCAM", "0", "1-2", "046d", "0825", "", "usb-046d_C270_HD_WEBCAM-video-index0", "platform-xhci-hcd.0-usb-0:2:1.0-video-index0"},
+	{"video10", "bcm2835-codec-decode", "0", "", "", "", "", "", ""},
+}
+
+func write_test_file(t *testing.T, file, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(file, []byte(content+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func symlink_test_file(t *testing.T, target, link string) {
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// new_test_tree makes fake sysfs and device directories of test video devices,
+// device nodes are absent, querying devices fails.
+func new_test_tree(t *testing.T) (*ListOption, func()) {
+	dir, err := ioutil.TempDir("", "camera-v4l2-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opt := &ListOption{
+		SysfsRoot: filepath.Join(dir, "sys"),
+		DevRoot:   filepath.Join(dir, "dev"),
+	}
+
+	for _, d := range test_video_devices {
+		class := filepath.Join(opt.SysfsRoot, "class", "video4linux", d.name)
+		write_test_file(t, filepath.Join(class, "name"), d.card)
+		write_test_file(t, filepath.Join(class, "index"), d.index)
+
+		if d.usb != "" {
+			usb := filepath.Join(opt.SysfsRoot, "devices", "usb1", d.usb)
+			write_test_file(t, filepath.Join(usb, "idVendor"), d.vendor)
+			write_test_file(t, filepath.Join(usb, "idProduct"), d.product)
+			if d.serial != "" {
+				write_test_file(t, filepath.Join(usb, "serial"), d.serial)
+			}
+			intf := filepath.Join(usb, d.usb+":1.0")
+			if err = os.MkdirAll(intf, 0755); err != nil {
+				t.Fatal(err)
+			}
+			symlink_test_file(t, intf, filepath.Join(class, "device"))
+		}
+
+		if d.by_id != "" {
+			symlink_test_file(t, "../../"+d.name, filepath.Join(opt.by_id_dir(), d.by_id))
+		}
+
+		if d.by_path != "" {
+			symlink_test_file(t, "../../"+d.name, filepath.Join(opt.by_path_dir(), d.by_path))
+		}
+	}
+
+	return opt, func() { os.RemoveAll(dir) }
+}
+
+func TestListDevices(t *testing.T) {
+	opt, clean := new_test_tree(t)
+	defer clean()
+
+	devs, err := ListDevices(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(devs) != len(test_video_devices) {
+		t.Fatalf("listed %v devices, want %v", len(devs), len(test_video_devices))
+	}
+
+	for i, d := range test_video_devices {
+		dev := devs[i]
+
+		if dev.Name != d.name || dev.Path != filepath.Join(opt.DevRoot, d.name) || dev.Card != d.card {
+			t.Errorf("device %v: name %v, path %v, card %v", d.name, dev.Name, dev.Path, dev.Card)
+		}
+
+		if dev.Vendor != d.vendor || dev.Product != d.product || dev.Serial != d.serial {
+			t.Errorf("device %v: usb %v:%v %v", d.name, dev.Vendor, dev.Product, dev.Serial)
+		}
+
+		if dev.ById != d.by_id || dev.ByPath != d.by_path {
+			t.Errorf("device %v: by id %q, by path %q", d.name, dev.ById, dev.ByPath)
+		}
+
+		if dev.Error == nil {
+			t.Errorf("device %v: absent device queried", d.name)
+		}
+	}
+
+	if devs[1].Index != 1 || devs[2].Index != 0 {
+		t.Errorf("index %v %v", devs[1].Index, devs[2].Index)
+	}
+}
+
+func TestListDevicesWithoutVideo4linux(t *testing.T) {
+	dir, err := ioutil.TempDir("", "camera-v4l2-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	devs, err := ListDevices(&ListOption{SysfsRoot: dir, DevRoot: dir})
+	if err != nil || devs != nil {
+		t.Errorf("devices %v, error %v", devs, err)
+	}
+}
+
+func TestParseFrameSize(t *testing.T) {
+	for _, c := range []struct {
+		s      string
+		width  uint32
+		height uint32
+		err    error
+	}{
+		{"640x480", 640, 480, nil},
+		{"1920x1080", 1920, 1080, nil},
+		{"640", 0, 0, ErrInvalidFrameSize},
+		{"640x", 0, 0, ErrInvalidFrameSize},
+		{"0x480", 0, 0, ErrInvalidFrameSize},
+		{"-640x480", 0, 0, ErrInvalidFrameSize},
+		{"640X480", 0, 0, ErrInvalidFrameSize},
+		{"640x480x3", 0, 0, ErrInvalidFrameSize},
+	} {
+		w, h, err := ParseFrameSize(c.s)
+		if w != c.width || h != c.height || err != c.err {
+			t.Errorf("parse %q: %v, %v, %v", c.s, w, h, err)
+		}
+	}
+}
+
+func TestParseFrameRate(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		rate float64
+		err  error
+	}{
+		{"30", 30, nil},
+		{"7.5", 7.5, nil},
+		{"30000/1001", 30000.0 / 1001, nil},
+		{"1/5", 0.2, nil},
+		{"0", 0, ErrInvalidFrameRate},
+		{"-30", 0, ErrInvalidFrameRate},
+		{"30/0", 0, ErrInvalidFrameRate},
+		{"30/", 0, ErrInvalidFrameRate},
+		{"fps", 0, ErrInvalidFrameRate},
+	} {
+		rate, err := ParseFrameRate(c.s)
+		if rate != c.rate || err != c.err {
+			t.Errorf("parse %q: %v, %v", c.s, rate, err)
+		}
+	}
+}
+
+func TestFrameSizeSupportsFrameRate(t *testing.T) {
+	discrete := &FrameSize{
+		Width:  640,
+		Height: 480,
+		Intervals: []*Interval{
+			{Numerator: 1, Denominator: 30},
+			{Numerator: 1001, Denominator: 30000},
+			{Numerator: 1, Denominator: 15},
+		},
+	}
+
+	// 1 to 30 fps.
+	stepwise := &FrameSize{
+		Width:             320,
+		Height:            240,
+		MaxWidth:          1280,
+		MaxHeight:         720,
+		StepWidth:         16,
+		StepHeight:        8,
+		Intervals:         []*Interval{{Numerator: 1, Denominator: 30}, {Numerator: 1, Denominator: 1}},
+		StepwiseIntervals: true,
+	}
+
+	unknown := &FrameSize{Width: 640, Height: 480}
+
+	for _, c := range []struct {
+		size      *FrameSize
+		rate      float64
+		supported bool
+	}{
+		{discrete, 30, true},
+		{discrete, 29.97, true},
+		{discrete, 15, true},
+		{discrete, 25, false},
+		{stepwise, 30, true},
+		{stepwise, 1, true},
+		{stepwise, 12.5, true},
+		{stepwise, 0.5, false},
+		{stepwise, 60, false},
+		{unknown, 60, true},
+	} {
+		if ok := c.size.SupportsFrameRate(c.rate); ok != c.supported {
+			t.Errorf("frame size %v rate %v: supported %v, want %v", c.size, c.rate, ok, c.supported)
+		}
+	}
+
+	if rates := stepwise.FrameRates(); !reflect.DeepEqual(rates, []string{"1-30"}) {
+		t.Errorf("stepwise frame rates %v", rates)
+	}
+
+	if rates := discrete.FrameRates(); !reflect.DeepEqual(rates, []string{"30", "30000/1001", "15"}) {
+		t.Errorf("discrete frame rates %v", rates)
+	}
+}
+
+func TestFrameSizeContains(t *testing.T) {
+	stepwise := &FrameSize{Width: 320, Height: 240, MaxWidth: 1280, MaxHeight: 720, StepWidth: 16, StepHeight: 8}
+
+	for _, c := range []struct {
+		width, height uint32
+		contained     bool
+	}{
+		{320, 240, true},
+		{640, 480, true},
+		{1280, 720, true},
+		{648, 480, false},
+		{1296, 720, false},
+		{160, 120, false},
+	} {
+		if ok := stepwise.Contains(c.width, c.height); ok != c.contained {
+			t.Errorf("%vx%v: contained %v, want %v", c.width, c.height, ok, c.contained)
+		}
+	}
+}
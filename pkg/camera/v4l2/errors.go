@@ -0,0 +1,13 @@
+package camera_v4l2
+
+import (
+	"errors"
+)
+
+var (
+	ErrNotV4l2Device    = errors.New("not v4l2 device")
+	ErrInvalidFrameSize = errors.New("invalid frame size")
+	ErrInvalidFrameRate = errors.New("invalid frame rate")
+	ErrDeviceNotFound   = errors.New("device not found")
+	ErrAmbiguousDevice  = errors.New("ambiguous device")
+)
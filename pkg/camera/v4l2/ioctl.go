@@ -0,0 +1,206 @@
+package camera_v4l2
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// ioctl numbers follow generic linux encoding, like x86, arm and arm64.
+const (
+	ioc_write = 1
+	ioc_read  = 2
+
+	v4l2_buf_type_video_capture = 1
+
+	v4l2_cap_video_capture = 0x00000001
+	v4l2_cap_device_caps   = 0x80000000
+
+	v4l2_fmt_flag_compressed = 0x0001
+
+	v4l2_frmsize_type_discrete   = 1
+	v4l2_frmsize_type_continuous = 2
+	v4l2_frmsize_type_stepwise   = 3
+
+	v4l2_frmival_type_discrete = 1
+)
+
+type v4l2_capability struct {
+	driver       [16]byte
+	card         [32]byte
+	bus_info     [32]byte
+	version      uint32
+	capabilities uint32
+	device_caps  uint32
+	reserved     [3]uint32
+}
+
+type v4l2_fmtdesc struct {
+	index       uint32
+	typ         uint32
+	flags       uint32
+	description [32]byte
+	pixelformat uint32
+	mbus_code   uint32
+	reserved    [3]uint32
+}
+
+type v4l2_frmsizeenum struct {
+	index        uint32
+	pixel_format uint32
+	typ          uint32
+	// discrete: width, height,
+	// stepwise: min_width, max_width, step_width, min_height, max_height, step_height.
+	union    [6]uint32
+	reserved [2]uint32
+}
+
+type v4l2_frmivalenum struct {
+	index        uint32
+	pixel_format uint32
+	width        uint32
+	height       uint32
+	typ          uint32
+	// discrete: numerator, denominator,
+	// stepwise: min, max and step fractions.
+	union    [6]uint32
+	reserved [2]uint32
+}
+
+func ioc(dir, nr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | 'V'<<8 | nr
+}
+
+var (
+	vidioc_querycap            = ioc(ioc_read, 0, unsafe.Sizeof(v4l2_capability{}))
+	vidioc_enum_fmt            = ioc(ioc_read|ioc_write, 2, unsafe.Sizeof(v4l2_fmtdesc{}))
+	vidioc_enum_framesizes     = ioc(ioc_read|ioc_write, 74, unsafe.Sizeof(v4l2_frmsizeenum{}))
+	vidioc_enum_frameintervals = ioc(ioc_read|ioc_write, 75, unsafe.Sizeof(v4l2_frmivalenum{}))
+)
+
+func ioctl(fd uintptr, req uintptr, arg unsafe.Pointer) error {
+	for {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+		switch errno {
+		case 0:
+			return nil
+		case syscall.EINTR:
+			continue
+		default:
+			return errno
+		}
+	}
+}
+
+func cstring(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+func fourcc(v uint32) string {
+	return string(bytes.TrimRight([]byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)}, " \x00"))
+}
+
+// query fills driver, card, bus info and formats of device by ioctls.
+func query(dev *Device) error {
+	f, err := os.OpenFile(dev.Path, os.O_RDWR|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fd := f.Fd()
+
+	var cp v4l2_capability
+	if err = ioctl(fd, vidioc_querycap, unsafe.Pointer(&cp)); err != nil {
+		return ErrNotV4l2Device
+	}
+
+	dev.Driver = cstring(cp.driver[:])
+	dev.Card = cstring(cp.card[:])
+	dev.BusInfo = cstring(cp.bus_info[:])
+
+	caps := cp.capabilities
+	if caps&v4l2_cap_device_caps != 0 {
+		caps = cp.device_caps
+	}
+	dev.Capture = caps&v4l2_cap_video_capture != 0
+	if !dev.Capture {
+		return nil
+	}
+
+	dev.Formats = nil
+	for i := uint32(0); ; i++ {
+		desc := v4l2_fmtdesc{index: i, typ: v4l2_buf_type_video_capture}
+		if ioctl(fd, vidioc_enum_fmt, unsafe.Pointer(&desc)) != nil {
+			break
+		}
+
+		format := &Format{
+			PixelFormat: fourcc(desc.pixelformat),
+			Description: cstring(desc.description[:]),
+			Compressed:  desc.flags&v4l2_fmt_flag_compressed != 0,
+			FrameSizes:  query_frame_sizes(fd, desc.pixelformat),
+		}
+		dev.Formats = append(dev.Formats, format)
+	}
+
+	return nil
+}
+
+func query_frame_sizes(fd uintptr, pixel_format uint32) []*FrameSize {
+	var sizes []*FrameSize
+
+	for i := uint32(0); ; i++ {
+		fs := v4l2_frmsizeenum{index: i, pixel_format: pixel_format}
+		if ioctl(fd, vidioc_enum_framesizes, unsafe.Pointer(&fs)) != nil {
+			break
+		}
+
+		switch fs.typ {
+		case v4l2_frmsize_type_discrete:
+			size := &FrameSize{Width: fs.union[0], Height: fs.union[1]}
+			size.Intervals, size.StepwiseIntervals = query_frame_intervals(fd, pixel_format, size.Width, size.Height)
+			sizes = append(sizes, size)
+		case v4l2_frmsize_type_continuous, v4l2_frmsize_type_stepwise:
+			size := &FrameSize{
+				Width:      fs.union[0],
+				MaxWidth:   fs.union[1],
+				StepWidth:  fs.union[2],
+				Height:     fs.union[3],
+				MaxHeight:  fs.union[4],
+				StepHeight: fs.union[5],
+			}
+			// intervals of stepwise frame size are queried by max frame size.
+			size.Intervals, size.StepwiseIntervals = query_frame_intervals(fd, pixel_format, size.MaxWidth, size.MaxHeight)
+			return append(sizes, size)
+		}
+	}
+
+	return sizes
+}
+
+// query_frame_intervals returns discrete intervals, or min and max intervals if stepwise.
+func query_frame_intervals(fd uintptr, pixel_format, width, height uint32) ([]*Interval, bool) {
+	var intervals []*Interval
+
+	for i := uint32(0); ; i++ {
+		fi := v4l2_frmivalenum{index: i, pixel_format: pixel_format, width: width, height: height}
+		if ioctl(fd, vidioc_enum_frameintervals, unsafe.Pointer(&fi)) != nil {
+			break
+		}
+
+		if fi.typ != v4l2_frmival_type_discrete {
+			return []*Interval{
+				{Numerator: fi.union[0], Denominator: fi.union[1]},
+				{Numerator: fi.union[2], Denominator: fi.union[3]},
+			}, true
+		}
+
+		intervals = append(intervals, &Interval{Numerator: fi.union[0], Denominator: fi.union[1]})
+	}
+
+	return intervals, false
+}
@@ -0,0 +1,158 @@
+package camera_v4l2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Selector selects capture device by stable attributes, all set fields should match.
+type Selector struct {
+	// ById and ByPath are names of links in `/dev/v4l/by-id` and `/dev/v4l/by-path`, or absolute paths of links.
+	ById   string
+	ByPath string
+	// Vendor and Product are usb ids in hex, like `046d`.
+	Vendor  string
+	Product string
+	Serial  string
+	// Card is v4l2 card name, like `HD Pro Webcam C920`.
+	Card string
+}
+
+func (s *Selector) Empty() bool {
+	return *s == Selector{}
+}
+
+func (s *Selector) String() string {
+	var ss []string
+
+	for _, f := range []struct{ key, val string }{
+		{"by_id", s.ById},
+		{"by_path", s.ByPath},
+		{"vendor", s.Vendor},
+		{"product", s.Product},
+		{"serial", s.Serial},
+		{"card", s.Card},
+	} {
+		if f.val != "" {
+			ss = append(ss, fmt.Sprintf("%v=%q", f.key, f.val))
+		}
+	}
+
+	return strings.Join(ss, " ")
+}
+
+// SelectError is error of selecting device, Err is ErrDeviceNotFound or ErrAmbiguousDevice.
+type SelectError struct {
+	Err      error
+	Selector *Selector
+	// Paths are paths of matched devices if ambiguous.
+	Paths []string
+}
+
+func (e *SelectError) Error() string {
+	if len(e.Paths) > 0 {
+		return fmt.Sprintf("%v: %v, matched: %v", e.Err, e.Selector, strings.Join(e.Paths, ", "))
+	}
+
+	return fmt.Sprintf("%v: %v", e.Err, e.Selector)
+}
+
+// IsDeviceNotFound reports err is error of no device matched.
+func IsDeviceNotFound(err error) bool {
+	e, ok := err.(*SelectError)
+	return ok && e.Err == ErrDeviceNotFound
+}
+
+// link_target returns name of device node linked by link, link is relative to dir if not absolute.
+func link_target(dir, link string) string {
+	if !filepath.IsAbs(link) {
+		link = filepath.Join(dir, link)
+	}
+
+	target, err := os.Readlink(link)
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(target)
+}
+
+func (s *Selector) match(dev *Device, by_id, by_path string) bool {
+	if s.ById != "" && dev.Name != by_id {
+		return false
+	}
+
+	if s.ByPath != "" && dev.Name != by_path {
+		return false
+	}
+
+	if s.Vendor != "" && !strings.EqualFold(dev.Vendor, s.Vendor) {
+		return false
+	}
+
+	if s.Product != "" && !strings.EqualFold(dev.Product, s.Product) {
+		return false
+	}
+
+	if s.Serial != "" && dev.Serial != s.Serial {
+		return false
+	}
+
+	if s.Card != "" && dev.Card != s.Card {
+		return false
+	}
+
+	return true
+}
+
+// capture reports device captures video, device failed to query is guessed by index in sysfs.
+func capture(dev *Device) bool {
+	if dev.Error != nil {
+		return dev.Index == 0
+	}
+
+	return dev.Capture
+}
+
+// SelectDevice returns the only capture device matched by selector,
+// SelectError if no device or multiple devices matched.
+func SelectDevice(sel *Selector, opt *ListOption) (*Device, error) {
+	devs, err := ListDevices(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var by_id, by_path string
+	if sel.ById != "" {
+		if by_id = link_target(opt.by_id_dir(), sel.ById); by_id == "" {
+			return nil, &SelectError{Err: ErrDeviceNotFound, Selector: sel}
+		}
+	}
+	if sel.ByPath != "" {
+		if by_path = link_target(opt.by_path_dir(), sel.ByPath); by_path == "" {
+			return nil, &SelectError{Err: ErrDeviceNotFound, Selector: sel}
+		}
+	}
+
+	var matched []*Device
+	for _, dev := range devs {
+		if capture(dev) && sel.match(dev, by_id, by_path) {
+			matched = append(matched, dev)
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, &SelectError{Err: ErrDeviceNotFound, Selector: sel}
+	case 1:
+		return matched[0], nil
+	default:
+		var paths []string
+		for _, dev := range matched {
+			paths = append(paths, dev.Path)
+		}
+		return nil, &SelectError{Err: ErrAmbiguousDevice, Selector: sel, Paths: paths}
+	}
+}
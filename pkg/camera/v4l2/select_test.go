@@ -0,0 +1,61 @@
+package camera_v4l2
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSelectDevice(t *testing.T) {
+	opt, clean := new_test_tree(t)
+	defer clean()
+
+	for _, c := range []struct {
+		sel  *Selector
+		name string
+	}{
+		{&Selector{ById: "usb-046d_HD_Pro_Webcam_C920_A1B2C3D4-video-index0"}, "video0"},
+		{&Selector{ById: filepath.Join(opt.by_id_dir(), "usb-046d_C270_HD_WEBCAM-video-index0")}, "video2"},
+		{&Selector{ByPath: "platform-xhci-hcd.0-usb-0:2:1.0-video-index0"}, "video2"},
+		// metadata node of index 1 is not capture device.
+		{&Selector{Vendor: "046D", Product: "082d"}, "video0"},
+		{&Selector{Serial: "A1B2C3D4"}, "video0"},
+		{&Selector{Card: "C270 HD WEBCAM"}, "video2"},
+	} {
+		dev, err := SelectDevice(c.sel, opt)
+		if err != nil {
+			t.Errorf("select %v: %v", c.sel, err)
+			continue
+		}
+
+		if dev.Name != c.name {
+			t.Errorf("select %v: %v, want %v", c.sel, dev.Name, c.name)
+		}
+	}
+}
+
+func TestSelectDeviceError(t *testing.T) {
+	opt, clean := new_test_tree(t)
+	defer clean()
+
+	for _, sel := range []*Selector{
+		{ById: "usb-046d_HD_Pro_Webcam_C920_00000000-video-index0"},
+		{Vendor: "1234"},
+		{Card: "C270 HD WEBCAM", Serial: "A1B2C3D4"},
+	} {
+		if _, err := SelectDevice(sel, opt); !IsDeviceNotFound(err) {
+			t.Errorf("select %v: error %v, want not found", sel, err)
+		}
+	}
+
+	_, err := SelectDevice(&Selector{Vendor: "046d"}, opt)
+	e, ok := err.(*SelectError)
+	if !ok || e.Err != ErrAmbiguousDevice {
+		t.Fatalf("error %v, want ambiguous", err)
+	}
+
+	want := []string{filepath.Join(opt.DevRoot, "video0"), filepath.Join(opt.DevRoot, "video2")}
+	if !reflect.DeepEqual(e.Paths, want) {
+		t.Errorf("ambiguous paths %v, want %v", e.Paths, want)
+	}
+}
@@ -0,0 +1,12 @@
+package camera_webrtc
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidOffer    = errors.New("invalid offer")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrTooManySessions = errors.New("too many sessions")
+	ErrRelayClosed     = errors.New("relay closed")
+)
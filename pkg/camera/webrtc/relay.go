@@ -0,0 +1,315 @@
+package camera_webrtc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/webrtc/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// rtp payload size of framework, fits in common mtu after srtp overhead.
+	RTP_PACKET_SIZE = 1200
+
+	DEFAULT_MAX_SESSIONS   = 8
+	DEFAULT_GATHER_TIMEOUT = 5 * time.Second
+
+	h264_fmtp = "level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f"
+)
+
+// IceServer is stun or turn server.
+type IceServer struct {
+	Urls       []string
+	Username   string
+	Credential string
+}
+
+type RelayOption struct {
+	IceServers    []*IceServer
+	PortMin       uint16
+	PortMax       uint16
+	Nat1To1IPs    []string
+	MaxSessions   int
+	GatherTimeout time.Duration
+	Logger        log.FieldLogger
+}
+
+// Relay forwards h264 rtp packets from local framework to peer connections,
+// all sessions share one track, so one encoder.
+type Relay struct {
+	opt   *RelayOption
+	conn  *net.UDPConn
+	api   *webrtc.API
+	track *webrtc.TrackLocalStaticRTP
+
+	mtx      sync.Mutex
+	sessions map[string]*webrtc.PeerConnection
+	closed   bool
+}
+
+func new_session_id() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Url returns local rtp url for framework output.
+func (r *Relay) Url() string {
+	return fmt.Sprintf("rtp://%v?pkt_size=%d", r.conn.LocalAddr(), RTP_PACKET_SIZE)
+}
+
+func (r *Relay) forward() {
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			r.mtx.Lock()
+			closed := r.closed
+			r.mtx.Unlock()
+
+			if !closed {
+				r.opt.Logger.WithError(err).Warningf("failed to read rtp packet")
+			}
+			return
+		}
+
+		if _, err = r.track.Write(buf[:n]); err != nil {
+			r.opt.Logger.WithError(err).Debugf("failed to write rtp packet to track")
+		}
+	}
+}
+
+// Negotiate creates session by offer, returns session id and answer with gathered candidates.
+func (r *Relay) Negotiate(offer string) (string, string, error) {
+	r.mtx.Lock()
+	if r.closed {
+		r.mtx.Unlock()
+		return "", "", ErrRelayClosed
+	}
+	if len(r.sessions) >= r.opt.MaxSessions {
+		r.mtx.Unlock()
+		return "", "", ErrTooManySessions
+	}
+	r.mtx.Unlock()
+
+	var servers []webrtc.ICEServer
+	for _, s := range r.opt.IceServers {
+		servers = append(servers, webrtc.ICEServer{URLs: s.Urls, Username: s.Username, Credential: s.Credential})
+	}
+
+	pc, err := r.api.NewPeerConnection(webrtc.Configuration{ICEServers: servers})
+	if err != nil {
+		return "", "", err
+	}
+
+	answer, err := r.answer(pc, offer)
+	if err != nil {
+		pc.Close()
+		return "", "", err
+	}
+
+	id := new_session_id()
+	logger := r.opt.Logger.WithField("session", id)
+
+	pc.OnConnectionStateChange(func(st webrtc.PeerConnectionState) {
+		logger.WithField("state", st.String()).Debugf("webrtc session state changed")
+
+		switch st {
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateClosed:
+			r.Close(id)
+		}
+	})
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.closed || len(r.sessions) >= r.opt.MaxSessions {
+		pc.Close()
+		return "", "", ErrTooManySessions
+	}
+	r.sessions[id] = pc
+
+	logger.Infof("webrtc session created")
+
+	return id, answer, nil
+}
+
+func (r *Relay) answer(pc *webrtc.PeerConnection, offer string) (string, error) {
+	sender, err := pc.AddTrack(r.track)
+	if err != nil {
+		return "", err
+	}
+
+	// reads rtcp for interceptors, like nack.
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			if _, _, err := sender.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err = pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer}); err != nil {
+		return "", ErrInvalidOffer
+	}
+
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return "", err
+	}
+
+	// signaling is request and response, candidates are not trickled.
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err = pc.SetLocalDescription(answer); err != nil {
+		return "", err
+	}
+
+	select {
+	case <-gathered:
+	case <-time.After(r.opt.GatherTimeout):
+		r.opt.Logger.Debugf("gather candidates timeout")
+	}
+
+	return pc.LocalDescription().SDP, nil
+}
+
+// Close closes session.
+func (r *Relay) Close(id string) error {
+	r.mtx.Lock()
+	pc, ok := r.sessions[id]
+	delete(r.sessions, id)
+	r.mtx.Unlock()
+
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	r.opt.Logger.WithField("session", id).Infof("webrtc session closed")
+
+	return pc.Close()
+}
+
+// Has reports session exists.
+func (r *Relay) Has(id string) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	_, ok := r.sessions[id]
+	return ok
+}
+
+// Sessions returns count of sessions.
+func (r *Relay) Sessions() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return len(r.sessions)
+}
+
+// CloseSessions closes all sessions, relay keeps receiving.
+func (r *Relay) CloseSessions() {
+	r.mtx.Lock()
+	sessions := r.sessions
+	r.sessions = map[string]*webrtc.PeerConnection{}
+	r.mtx.Unlock()
+
+	for _, pc := range sessions {
+		pc.Close()
+	}
+}
+
+// Shutdown closes all sessions and stops receiving.
+func (r *Relay) Shutdown() error {
+	r.mtx.Lock()
+	r.closed = true
+	r.mtx.Unlock()
+
+	r.CloseSessions()
+
+	return r.conn.Close()
+}
+
+func new_api(opt *RelayOption) (*webrtc.API, error) {
+	m := &webrtc.MediaEngine{}
+	err := m.RegisterCodec(webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{
+			MimeType:    webrtc.MimeTypeH264,
+			ClockRate:   90000,
+			SDPFmtpLine: h264_fmtp,
+			RTCPFeedback: []webrtc.RTCPFeedback{
+				{Type: "nack"},
+				{Type: "nack", Parameter: "pli"},
+			},
+		},
+		PayloadType: 96,
+	}, webrtc.RTPCodecTypeVideo)
+	if err != nil {
+		return nil, err
+	}
+
+	ir := &interceptor.Registry{}
+	if err = webrtc.RegisterDefaultInterceptors(m, ir); err != nil {
+		return nil, err
+	}
+
+	se := webrtc.SettingEngine{}
+	if opt.PortMin > 0 || opt.PortMax > 0 {
+		if err = se.SetEphemeralUDPPortRange(opt.PortMin, opt.PortMax); err != nil {
+			return nil, err
+		}
+	}
+	if len(opt.Nat1To1IPs) > 0 {
+		se.SetNAT1To1IPs(opt.Nat1To1IPs, webrtc.ICECandidateTypeHost)
+	}
+
+	return webrtc.NewAPI(webrtc.WithMediaEngine(m), webrtc.WithInterceptorRegistry(ir), webrtc.WithSettingEngine(se)), nil
+}
+
+func NewRelay(opt *RelayOption) (*Relay, error) {
+	if opt.MaxSessions <= 0 {
+		opt.MaxSessions = DEFAULT_MAX_SESSIONS
+	}
+
+	if opt.GatherTimeout <= 0 {
+		opt.GatherTimeout = DEFAULT_GATHER_TIMEOUT
+	}
+
+	api, err := new_api(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	track, err := webrtc.NewTrackLocalStaticRTP(webrtc.RTPCodecCapability{
+		MimeType:    webrtc.MimeTypeH264,
+		ClockRate:   90000,
+		SDPFmtpLine: h264_fmtp,
+	}, "video", "camera")
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Relay{
+		opt:      opt,
+		conn:     conn,
+		api:      api,
+		track:    track,
+		sessions: map[string]*webrtc.PeerConnection{},
+	}
+	go r.forward()
+
+	return r, nil
+}
@@ -0,0 +1,279 @@
+package camera_webrtc
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+const test_timeout = 10 * time.Second
+
+func new_test_relay(t *testing.T, max_sessions int) *Relay {
+	logger := log.New()
+	logger.Out = ioutil.Discard
+
+	r, err := NewRelay(&RelayOption{
+		MaxSessions:   max_sessions,
+		GatherTimeout: time.Second,
+		Logger:        logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+// test_viewer is receive only peer, sequence numbers of received packets are sent to packets.
+type test_viewer struct {
+	pc      *webrtc.PeerConnection
+	id      string
+	packets chan uint16
+}
+
+func new_test_viewer(t *testing.T, r *Relay) *test_viewer {
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = pc.AddTransceiverFromKind(webrtc.RTPCodecTypeVideo, webrtc.RTPTransceiverInit{
+		Direction: webrtc.RTPTransceiverDirectionRecvonly,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	v := &test_viewer{pc: pc, packets: make(chan uint16, 1024)}
+	pc.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		for {
+			pkt, _, err := track.ReadRTP()
+			if err != nil {
+				return
+			}
+
+			select {
+			case v.packets <- pkt.SequenceNumber:
+			default:
+			}
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err = pc.SetLocalDescription(offer); err != nil {
+		t.Fatal(err)
+	}
+	<-gathered
+
+	id, answer, err := r.Negotiate(pc.LocalDescription().SDP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.id = id
+
+	if err = pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer}); err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+// test_sender writes h264 rtp packets to relay like framework.
+type test_sender struct {
+	conn net.Conn
+	seq  uint16
+}
+
+func new_test_sender(t *testing.T, r *Relay) *test_sender {
+	conn, err := net.Dial("udp", r.conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &test_sender{conn: conn}
+}
+
+func (s *test_sender) send(t *testing.T) {
+	s.seq++
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: s.seq,
+			Timestamp:      uint32(s.seq) * 3000,
+			SSRC:           1,
+		},
+		// single nal unit of idr slice.
+		Payload: []byte{0x65, 0x88, 0x84, 0x00},
+	}
+
+	buf, err := pkt.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = s.conn.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// wait_packets sends packets until all viewers received one after now.
+func wait_packets(t *testing.T, s *test_sender, viewers ...*test_viewer) {
+	// drop packets received before.
+	for _, v := range viewers {
+		for len(v.packets) > 0 {
+			<-v.packets
+		}
+	}
+
+	received := make([]bool, len(viewers))
+	deadline := time.Now().Add(test_timeout)
+	for time.Now().Before(deadline) {
+		s.send(t)
+		time.Sleep(20 * time.Millisecond)
+
+		done := true
+		for i, v := range viewers {
+			select {
+			case <-v.packets:
+				received[i] = true
+			default:
+			}
+			done = done && received[i]
+		}
+
+		if done {
+			return
+		}
+	}
+
+	t.Fatalf("packets not received by viewers: %v", received)
+}
+
+func TestRelayFanOut(t *testing.T) {
+	r := new_test_relay(t, 0)
+	defer r.Shutdown()
+
+	s := new_test_sender(t, r)
+	defer s.conn.Close()
+
+	var viewers []*test_viewer
+	for i := 0; i < 3; i++ {
+		v := new_test_viewer(t, r)
+		defer v.pc.Close()
+		viewers = append(viewers, v)
+	}
+
+	if n := r.Sessions(); n != len(viewers) {
+		t.Fatalf("sessions %v, want %v", n, len(viewers))
+	}
+
+	// all viewers share one track.
+	wait_packets(t, s, viewers...)
+}
+
+func TestRelayViewerLeave(t *testing.T) {
+	r := new_test_relay(t, 0)
+	defer r.Shutdown()
+
+	s := new_test_sender(t, r)
+	defer s.conn.Close()
+
+	a := new_test_viewer(t, r)
+	defer a.pc.Close()
+	b := new_test_viewer(t, r)
+	defer b.pc.Close()
+
+	wait_packets(t, s, a, b)
+
+	if err := r.Close(a.id); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Has(a.id) || !r.Has(b.id) || r.Sessions() != 1 {
+		t.Fatalf("sessions after leave: has %v %v, count %v", r.Has(a.id), r.Has(b.id), r.Sessions())
+	}
+
+	if err := r.Close(a.id); err != ErrSessionNotFound {
+		t.Errorf("close again: error %v, want %v", err, ErrSessionNotFound)
+	}
+
+	// remaining viewer keeps receiving.
+	wait_packets(t, s, b)
+
+	// viewer joins after others left.
+	c := new_test_viewer(t, r)
+	defer c.pc.Close()
+
+	wait_packets(t, s, b, c)
+}
+
+func TestRelayMaxSessions(t *testing.T) {
+	r := new_test_relay(t, 1)
+	defer r.Shutdown()
+
+	v := new_test_viewer(t, r)
+	defer v.pc.Close()
+
+	if _, _, err := r.Negotiate(v.pc.LocalDescription().SDP); err != ErrTooManySessions {
+		t.Errorf("error %v, want %v", err, ErrTooManySessions)
+	}
+}
+
+func TestRelayInvalidOffer(t *testing.T) {
+	r := new_test_relay(t, 0)
+	defer r.Shutdown()
+
+	if _, _, err := r.Negotiate("invalid"); err != ErrInvalidOffer {
+		t.Errorf("error %v, want %v", err, ErrInvalidOffer)
+	}
+
+	if n := r.Sessions(); n != 0 {
+		t.Errorf("sessions %v, want 0", n)
+	}
+}
+
+func TestRelayShutdown(t *testing.T) {
+	r := new_test_relay(t, 0)
+
+	s := new_test_sender(t, r)
+	defer s.conn.Close()
+
+	a := new_test_viewer(t, r)
+	defer a.pc.Close()
+	b := new_test_viewer(t, r)
+	defer b.pc.Close()
+
+	r.CloseSessions()
+	if n := r.Sessions(); n != 0 {
+		t.Fatalf("sessions %v after close sessions, want 0", n)
+	}
+
+	// relay keeps receiving after sessions closed.
+	c := new_test_viewer(t, r)
+	defer c.pc.Close()
+
+	wait_packets(t, s, c)
+
+	if err := r.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Has(c.id) || r.Sessions() != 0 {
+		t.Errorf("sessions kept after shutdown")
+	}
+
+	if _, _, err := r.Negotiate(c.pc.LocalDescription().SDP); err != ErrRelayClosed {
+		t.Errorf("error %v, want %v", err, ErrRelayClosed)
+	}
+}
@@ -7,7 +7,11 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	empty "github.com/golang/protobuf/ptypes/empty"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
+	_ "github.com/mwitkow/go-proto-validators"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -25,20 +29,2776 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type Camera struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// driver name, like `simple`.
+	Driver               string   `protobuf:"bytes,2,opt,name=driver,proto3" json:"driver,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Camera) Reset()         { *m = Camera{} }
+func (m *Camera) String() string { return proto.CompactTextString(m) }
+func (*Camera) ProtoMessage()    {}
+func (*Camera) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{0}
+}
+
+func (m *Camera) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Camera.Unmarshal(m, b)
+}
+func (m *Camera) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Camera.Marshal(b, m, deterministic)
+}
+func (m *Camera) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Camera.Merge(m, src)
+}
+func (m *Camera) XXX_Size() int {
+	return xxx_messageInfo_Camera.Size(m)
+}
+func (m *Camera) XXX_DiscardUnknown() {
+	xxx_messageInfo_Camera.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Camera proto.InternalMessageInfo
+
+func (m *Camera) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Camera) GetDriver() string {
+	if m != nil {
+		return m.Driver
+	}
+	return ""
+}
+
+func (m *Camera) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type ListCamerasResponse struct {
+	Cameras              []*Camera `protobuf:"bytes,1,rep,name=cameras,proto3" json:"cameras,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListCamerasResponse) Reset()         { *m = ListCamerasResponse{} }
+func (m *ListCamerasResponse) String() string { return proto.CompactTextString(m) }
+func (*ListCamerasResponse) ProtoMessage()    {}
+func (*ListCamerasResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{1}
+}
+
+func (m *ListCamerasResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCamerasResponse.Unmarshal(m, b)
+}
+func (m *ListCamerasResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCamerasResponse.Marshal(b, m, deterministic)
+}
+func (m *ListCamerasResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCamerasResponse.Merge(m, src)
+}
+func (m *ListCamerasResponse) XXX_Size() int {
+	return xxx_messageInfo_ListCamerasResponse.Size(m)
+}
+func (m *ListCamerasResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCamerasResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCamerasResponse proto.InternalMessageInfo
+
+func (m *ListCamerasResponse) GetCameras() []*Camera {
+	if m != nil {
+		return m.Cameras
+	}
+	return nil
+}
+
+type Output struct {
+	Label                string            `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Url                  string            `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	LiveId               string            `protobuf:"bytes,3,opt,name=live_id,json=liveId,proto3" json:"live_id,omitempty"`
+	Playbacks            map[string]string `protobuf:"bytes,4,rep,name=playbacks,proto3" json:"playbacks,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Output) Reset()         { *m = Output{} }
+func (m *Output) String() string { return proto.CompactTextString(m) }
+func (*Output) ProtoMessage()    {}
+func (*Output) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{2}
+}
+
+func (m *Output) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Output.Unmarshal(m, b)
+}
+func (m *Output) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Output.Marshal(b, m, deterministic)
+}
+func (m *Output) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Output.Merge(m, src)
+}
+func (m *Output) XXX_Size() int {
+	return xxx_messageInfo_Output.Size(m)
+}
+func (m *Output) XXX_DiscardUnknown() {
+	xxx_messageInfo_Output.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Output proto.InternalMessageInfo
+
+func (m *Output) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *Output) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Output) GetLiveId() string {
+	if m != nil {
+		return m.LiveId
+	}
+	return ""
+}
+
+func (m *Output) GetPlaybacks() map[string]string {
+	if m != nil {
+		return m.Playbacks
+	}
+	return nil
+}
+
+type StartRequest struct {
+	// overrides for this session only, empty means use config.
+	FrameSize string              `protobuf:"bytes,1,opt,name=frame_size,json=frameSize,proto3" json:"frame_size,omitempty"`
+	FrameRate uint32              `protobuf:"varint,2,opt,name=frame_rate,json=frameRate,proto3" json:"frame_rate,omitempty"`
+	BitRate   string              `protobuf:"bytes,3,opt,name=bit_rate,json=bitRate,proto3" json:"bit_rate,omitempty"`
+	Codec     string              `protobuf:"bytes,4,opt,name=codec,proto3" json:"codec,omitempty"`
+	Output    string              `protobuf:"bytes,5,opt,name=output,proto3" json:"output,omitempty"`
+	Audio     *wrappers.BoolValue `protobuf:"bytes,6,opt,name=audio,proto3" json:"audio,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,7,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartRequest) Reset()         { *m = StartRequest{} }
+func (m *StartRequest) String() string { return proto.CompactTextString(m) }
+func (*StartRequest) ProtoMessage()    {}
+func (*StartRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{3}
+}
+
+func (m *StartRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartRequest.Unmarshal(m, b)
+}
+func (m *StartRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartRequest.Marshal(b, m, deterministic)
+}
+func (m *StartRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartRequest.Merge(m, src)
+}
+func (m *StartRequest) XXX_Size() int {
+	return xxx_messageInfo_StartRequest.Size(m)
+}
+func (m *StartRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartRequest proto.InternalMessageInfo
+
+func (m *StartRequest) GetFrameSize() string {
+	if m != nil {
+		return m.FrameSize
+	}
+	return ""
+}
+
+func (m *StartRequest) GetFrameRate() uint32 {
+	if m != nil {
+		return m.FrameRate
+	}
+	return 0
+}
+
+func (m *StartRequest) GetBitRate() string {
+	if m != nil {
+		return m.BitRate
+	}
+	return ""
+}
+
+func (m *StartRequest) GetCodec() string {
+	if m != nil {
+		return m.Codec
+	}
+	return ""
+}
+
+func (m *StartRequest) GetOutput() string {
+	if m != nil {
+		return m.Output
+	}
+	return ""
+}
+
+func (m *StartRequest) GetAudio() *wrappers.BoolValue {
+	if m != nil {
+		return m.Audio
+	}
+	return nil
+}
+
+func (m *StartRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type StartResponse struct {
+	Outputs              []*Output `protobuf:"bytes,1,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *StartResponse) Reset()         { *m = StartResponse{} }
+func (m *StartResponse) String() string { return proto.CompactTextString(m) }
+func (*StartResponse) ProtoMessage()    {}
+func (*StartResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{4}
+}
+
+func (m *StartResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartResponse.Unmarshal(m, b)
+}
+func (m *StartResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartResponse.Marshal(b, m, deterministic)
+}
+func (m *StartResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartResponse.Merge(m, src)
+}
+func (m *StartResponse) XXX_Size() int {
+	return xxx_messageInfo_StartResponse.Size(m)
+}
+func (m *StartResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartResponse proto.InternalMessageInfo
+
+func (m *StartResponse) GetOutputs() []*Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+type StopRequest struct {
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,1,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StopRequest) Reset()         { *m = StopRequest{} }
+func (m *StopRequest) String() string { return proto.CompactTextString(m) }
+func (*StopRequest) ProtoMessage()    {}
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{5}
+}
+
+func (m *StopRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopRequest.Unmarshal(m, b)
+}
+func (m *StopRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StopRequest.Marshal(b, m, deterministic)
+}
+func (m *StopRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopRequest.Merge(m, src)
+}
+func (m *StopRequest) XXX_Size() int {
+	return xxx_messageInfo_StopRequest.Size(m)
+}
+func (m *StopRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StopRequest proto.InternalMessageInfo
+
+func (m *StopRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type Stats struct {
+	Frame                int64                `protobuf:"varint,1,opt,name=frame,proto3" json:"frame,omitempty"`
+	Fps                  float64              `protobuf:"fixed64,2,opt,name=fps,proto3" json:"fps,omitempty"`
+	BitRate              float64              `protobuf:"fixed64,3,opt,name=bit_rate,json=bitRate,proto3" json:"bit_rate,omitempty"`
+	Size                 string               `protobuf:"bytes,4,opt,name=size,proto3" json:"size,omitempty"`
+	Time                 string               `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	DupFrames            int64                `protobuf:"varint,6,opt,name=dup_frames,json=dupFrames,proto3" json:"dup_frames,omitempty"`
+	DropFrames           int64                `protobuf:"varint,7,opt,name=drop_frames,json=dropFrames,proto3" json:"drop_frames,omitempty"`
+	Speed                float64              `protobuf:"fixed64,8,opt,name=speed,proto3" json:"speed,omitempty"`
+	UpdateAt             *timestamp.Timestamp `protobuf:"bytes,9,opt,name=update_at,json=updateAt,proto3" json:"update_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Stats) Reset()         { *m = Stats{} }
+func (m *Stats) String() string { return proto.CompactTextString(m) }
+func (*Stats) ProtoMessage()    {}
+func (*Stats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{6}
+}
+
+func (m *Stats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Stats.Unmarshal(m, b)
+}
+func (m *Stats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Stats.Marshal(b, m, deterministic)
+}
+func (m *Stats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Stats.Merge(m, src)
+}
+func (m *Stats) XXX_Size() int {
+	return xxx_messageInfo_Stats.Size(m)
+}
+func (m *Stats) XXX_DiscardUnknown() {
+	xxx_messageInfo_Stats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Stats proto.InternalMessageInfo
+
+func (m *Stats) GetFrame() int64 {
+	if m != nil {
+		return m.Frame
+	}
+	return 0
+}
+
+func (m *Stats) GetFps() float64 {
+	if m != nil {
+		return m.Fps
+	}
+	return 0
+}
+
+func (m *Stats) GetBitRate() float64 {
+	if m != nil {
+		return m.BitRate
+	}
+	return 0
+}
+
+func (m *Stats) GetSize() string {
+	if m != nil {
+		return m.Size
+	}
+	return ""
+}
+
+func (m *Stats) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+func (m *Stats) GetDupFrames() int64 {
+	if m != nil {
+		return m.DupFrames
+	}
+	return 0
+}
+
+func (m *Stats) GetDropFrames() int64 {
+	if m != nil {
+		return m.DropFrames
+	}
+	return 0
+}
+
+func (m *Stats) GetSpeed() float64 {
+	if m != nil {
+		return m.Speed
+	}
+	return 0
+}
+
+func (m *Stats) GetUpdateAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.UpdateAt
+	}
+	return nil
+}
+
+type GetStatusRequest struct {
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,1,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{7}
+}
+
+func (m *GetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusRequest.Unmarshal(m, b)
+}
+func (m *GetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusRequest.Merge(m, src)
+}
+func (m *GetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatusRequest.Size(m)
+}
+func (m *GetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusRequest proto.InternalMessageInfo
+
+func (m *GetStatusRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type GetStatusResponse struct {
+	// `on`, `off`, or `disconnected` if started camera waits for device plugged back.
+	State          string               `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Outputs        []*Output            `protobuf:"bytes,2,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Pid            int32                `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	StartAt        *timestamp.Timestamp `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	Uptime         *duration.Duration   `protobuf:"bytes,5,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	RestartCount   int32                `protobuf:"varint,6,opt,name=restart_count,json=restartCount,proto3" json:"restart_count,omitempty"`
+	LastError      string               `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastExitReason string               `protobuf:"bytes,8,opt,name=last_exit_reason,json=lastExitReason,proto3" json:"last_exit_reason,omitempty"`
+	Stats          *Stats               `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`
+	Logs           []string             `protobuf:"bytes,10,rep,name=logs,proto3" json:"logs,omitempty"`
+	Recording      bool                 `protobuf:"varint,11,opt,name=recording,proto3" json:"recording,omitempty"`
+	// only set if driver scheduled.
+	Schedule             *ScheduleStatus `protobuf:"bytes,12,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetStatusResponse) Reset()         { *m = GetStatusResponse{} }
+func (m *GetStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStatusResponse) ProtoMessage()    {}
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{8}
+}
+
+func (m *GetStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusResponse.Unmarshal(m, b)
+}
+func (m *GetStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusResponse.Merge(m, src)
+}
+func (m *GetStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStatusResponse.Size(m)
+}
+func (m *GetStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusResponse proto.InternalMessageInfo
+
+func (m *GetStatusResponse) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *GetStatusResponse) GetOutputs() []*Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *GetStatusResponse) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *GetStatusResponse) GetStartAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartAt
+	}
+	return nil
+}
+
+func (m *GetStatusResponse) GetUptime() *duration.Duration {
+	if m != nil {
+		return m.Uptime
+	}
+	return nil
+}
+
+func (m *GetStatusResponse) GetRestartCount() int32 {
+	if m != nil {
+		return m.RestartCount
+	}
+	return 0
+}
+
+func (m *GetStatusResponse) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *GetStatusResponse) GetLastExitReason() string {
+	if m != nil {
+		return m.LastExitReason
+	}
+	return ""
+}
+
+func (m *GetStatusResponse) GetStats() *Stats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *GetStatusResponse) GetLogs() []string {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+func (m *GetStatusResponse) GetRecording() bool {
+	if m != nil {
+		return m.Recording
+	}
+	return false
+}
+
+func (m *GetStatusResponse) GetSchedule() *ScheduleStatus {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
+}
+
+type ScheduleStatus struct {
+	// manual Start or Stop overrides schedule until next transition.
+	Override bool `protobuf:"varint,1,opt,name=override,proto3" json:"override,omitempty"`
+	// empty if no more transition.
+	NextTransitionAt     *timestamp.Timestamp `protobuf:"bytes,2,opt,name=next_transition_at,json=nextTransitionAt,proto3" json:"next_transition_at,omitempty"`
+	NextState            string               `protobuf:"bytes,3,opt,name=next_state,json=nextState,proto3" json:"next_state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ScheduleStatus) Reset()         { *m = ScheduleStatus{} }
+func (m *ScheduleStatus) String() string { return proto.CompactTextString(m) }
+func (*ScheduleStatus) ProtoMessage()    {}
+func (*ScheduleStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{9}
+}
+
+func (m *ScheduleStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleStatus.Unmarshal(m, b)
+}
+func (m *ScheduleStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleStatus.Marshal(b, m, deterministic)
+}
+func (m *ScheduleStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleStatus.Merge(m, src)
+}
+func (m *ScheduleStatus) XXX_Size() int {
+	return xxx_messageInfo_ScheduleStatus.Size(m)
+}
+func (m *ScheduleStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleStatus proto.InternalMessageInfo
+
+func (m *ScheduleStatus) GetOverride() bool {
+	if m != nil {
+		return m.Override
+	}
+	return false
+}
+
+func (m *ScheduleStatus) GetNextTransitionAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.NextTransitionAt
+	}
+	return nil
+}
+
+func (m *ScheduleStatus) GetNextState() string {
+	if m != nil {
+		return m.NextState
+	}
+	return ""
+}
+
+type SnapshotRequest struct {
+	// overrides for this snapshot only, empty means use config.
+	Format    string              `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	FrameSize string              `protobuf:"bytes,2,opt,name=frame_size,json=frameSize,proto3" json:"frame_size,omitempty"`
+	Quality   uint32              `protobuf:"varint,3,opt,name=quality,proto3" json:"quality,omitempty"`
+	Store     *wrappers.BoolValue `protobuf:"bytes,4,opt,name=store,proto3" json:"store,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,5,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotRequest) Reset()         { *m = SnapshotRequest{} }
+func (m *SnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequest) ProtoMessage()    {}
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{10}
+}
+
+func (m *SnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequest.Unmarshal(m, b)
+}
+func (m *SnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequest.Merge(m, src)
+}
+func (m *SnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequest.Size(m)
+}
+func (m *SnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequest proto.InternalMessageInfo
+
+func (m *SnapshotRequest) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *SnapshotRequest) GetFrameSize() string {
+	if m != nil {
+		return m.FrameSize
+	}
+	return ""
+}
+
+func (m *SnapshotRequest) GetQuality() uint32 {
+	if m != nil {
+		return m.Quality
+	}
+	return 0
+}
+
+func (m *SnapshotRequest) GetStore() *wrappers.BoolValue {
+	if m != nil {
+		return m.Store
+	}
+	return nil
+}
+
+func (m *SnapshotRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type SnapshotResponse struct {
+	Content              []byte               `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Format               string               `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Object               string               `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SnapshotResponse) Reset()         { *m = SnapshotResponse{} }
+func (m *SnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*SnapshotResponse) ProtoMessage()    {}
+func (*SnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{11}
+}
+
+func (m *SnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotResponse.Unmarshal(m, b)
+}
+func (m *SnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *SnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotResponse.Merge(m, src)
+}
+func (m *SnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_SnapshotResponse.Size(m)
+}
+func (m *SnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotResponse proto.InternalMessageInfo
+
+func (m *SnapshotResponse) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *SnapshotResponse) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *SnapshotResponse) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *SnapshotResponse) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type StartRecordingRequest struct {
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,1,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartRecordingRequest) Reset()         { *m = StartRecordingRequest{} }
+func (m *StartRecordingRequest) String() string { return proto.CompactTextString(m) }
+func (*StartRecordingRequest) ProtoMessage()    {}
+func (*StartRecordingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{12}
+}
+
+func (m *StartRecordingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartRecordingRequest.Unmarshal(m, b)
+}
+func (m *StartRecordingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartRecordingRequest.Marshal(b, m, deterministic)
+}
+func (m *StartRecordingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartRecordingRequest.Merge(m, src)
+}
+func (m *StartRecordingRequest) XXX_Size() int {
+	return xxx_messageInfo_StartRecordingRequest.Size(m)
+}
+func (m *StartRecordingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartRecordingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartRecordingRequest proto.InternalMessageInfo
+
+func (m *StartRecordingRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type StopRecordingRequest struct {
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,1,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StopRecordingRequest) Reset()         { *m = StopRecordingRequest{} }
+func (m *StopRecordingRequest) String() string { return proto.CompactTextString(m) }
+func (*StopRecordingRequest) ProtoMessage()    {}
+func (*StopRecordingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{13}
+}
+
+func (m *StopRecordingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopRecordingRequest.Unmarshal(m, b)
+}
+func (m *StopRecordingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StopRecordingRequest.Marshal(b, m, deterministic)
+}
+func (m *StopRecordingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopRecordingRequest.Merge(m, src)
+}
+func (m *StopRecordingRequest) XXX_Size() int {
+	return xxx_messageInfo_StopRecordingRequest.Size(m)
+}
+func (m *StopRecordingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopRecordingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StopRecordingRequest proto.InternalMessageInfo
+
+func (m *StopRecordingRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type Recording struct {
+	Name                 string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	StartAt              *timestamp.Timestamp `protobuf:"bytes,2,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	EndAt                *timestamp.Timestamp `protobuf:"bytes,3,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
+	Duration             *duration.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Size                 int64                `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Format               string               `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	Recording            bool                 `protobuf:"varint,7,opt,name=recording,proto3" json:"recording,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Recording) Reset()         { *m = Recording{} }
+func (m *Recording) String() string { return proto.CompactTextString(m) }
+func (*Recording) ProtoMessage()    {}
+func (*Recording) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{14}
+}
+
+func (m *Recording) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Recording.Unmarshal(m, b)
+}
+func (m *Recording) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Recording.Marshal(b, m, deterministic)
+}
+func (m *Recording) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Recording.Merge(m, src)
+}
+func (m *Recording) XXX_Size() int {
+	return xxx_messageInfo_Recording.Size(m)
+}
+func (m *Recording) XXX_DiscardUnknown() {
+	xxx_messageInfo_Recording.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Recording proto.InternalMessageInfo
+
+func (m *Recording) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Recording) GetStartAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartAt
+	}
+	return nil
+}
+
+func (m *Recording) GetEndAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndAt
+	}
+	return nil
+}
+
+func (m *Recording) GetDuration() *duration.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+func (m *Recording) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Recording) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *Recording) GetRecording() bool {
+	if m != nil {
+		return m.Recording
+	}
+	return false
+}
+
+type ListRecordingsRequest struct {
+	// optional time range, recordings overlapped with range are listed.
+	Begin     *timestamp.Timestamp `protobuf:"bytes,1,opt,name=begin,proto3" json:"begin,omitempty"`
+	End       *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	PageSize  uint32               `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string               `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,5,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRecordingsRequest) Reset()         { *m = ListRecordingsRequest{} }
+func (m *ListRecordingsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRecordingsRequest) ProtoMessage()    {}
+func (*ListRecordingsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{15}
+}
+
+func (m *ListRecordingsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRecordingsRequest.Unmarshal(m, b)
+}
+func (m *ListRecordingsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRecordingsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRecordingsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRecordingsRequest.Merge(m, src)
+}
+func (m *ListRecordingsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRecordingsRequest.Size(m)
+}
+func (m *ListRecordingsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRecordingsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRecordingsRequest proto.InternalMessageInfo
+
+func (m *ListRecordingsRequest) GetBegin() *timestamp.Timestamp {
+	if m != nil {
+		return m.Begin
+	}
+	return nil
+}
+
+func (m *ListRecordingsRequest) GetEnd() *timestamp.Timestamp {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (m *ListRecordingsRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListRecordingsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListRecordingsRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type ListRecordingsResponse struct {
+	Recordings           []*Recording `protobuf:"bytes,1,rep,name=recordings,proto3" json:"recordings,omitempty"`
+	NextPageToken        string       `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListRecordingsResponse) Reset()         { *m = ListRecordingsResponse{} }
+func (m *ListRecordingsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRecordingsResponse) ProtoMessage()    {}
+func (*ListRecordingsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{16}
+}
+
+func (m *ListRecordingsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRecordingsResponse.Unmarshal(m, b)
+}
+func (m *ListRecordingsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRecordingsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListRecordingsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRecordingsResponse.Merge(m, src)
+}
+func (m *ListRecordingsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRecordingsResponse.Size(m)
+}
+func (m *ListRecordingsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRecordingsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRecordingsResponse proto.InternalMessageInfo
+
+func (m *ListRecordingsResponse) GetRecordings() []*Recording {
+	if m != nil {
+		return m.Recordings
+	}
+	return nil
+}
+
+func (m *ListRecordingsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type GetRecordingRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,2,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRecordingRequest) Reset()         { *m = GetRecordingRequest{} }
+func (m *GetRecordingRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRecordingRequest) ProtoMessage()    {}
+func (*GetRecordingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{17}
+}
+
+func (m *GetRecordingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRecordingRequest.Unmarshal(m, b)
+}
+func (m *GetRecordingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRecordingRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRecordingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRecordingRequest.Merge(m, src)
+}
+func (m *GetRecordingRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRecordingRequest.Size(m)
+}
+func (m *GetRecordingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRecordingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRecordingRequest proto.InternalMessageInfo
+
+func (m *GetRecordingRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetRecordingRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type GetRecordingResponse struct {
+	Recording            *Recording `protobuf:"bytes,1,opt,name=recording,proto3" json:"recording,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetRecordingResponse) Reset()         { *m = GetRecordingResponse{} }
+func (m *GetRecordingResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRecordingResponse) ProtoMessage()    {}
+func (*GetRecordingResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{18}
+}
+
+func (m *GetRecordingResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRecordingResponse.Unmarshal(m, b)
+}
+func (m *GetRecordingResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRecordingResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRecordingResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRecordingResponse.Merge(m, src)
+}
+func (m *GetRecordingResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRecordingResponse.Size(m)
+}
+func (m *GetRecordingResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRecordingResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRecordingResponse proto.InternalMessageInfo
+
+func (m *GetRecordingResponse) GetRecording() *Recording {
+	if m != nil {
+		return m.Recording
+	}
+	return nil
+}
+
+type Clip struct {
+	Object               string               `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Parts                []string             `protobuf:"bytes,2,rep,name=parts,proto3" json:"parts,omitempty"`
+	Size                 int64                `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Format               string               `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	StartAt              *timestamp.Timestamp `protobuf:"bytes,5,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	EndAt                *timestamp.Timestamp `protobuf:"bytes,6,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Clip) Reset()         { *m = Clip{} }
+func (m *Clip) String() string { return proto.CompactTextString(m) }
+func (*Clip) ProtoMessage()    {}
+func (*Clip) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{19}
+}
+
+func (m *Clip) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Clip.Unmarshal(m, b)
+}
+func (m *Clip) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Clip.Marshal(b, m, deterministic)
+}
+func (m *Clip) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Clip.Merge(m, src)
+}
+func (m *Clip) XXX_Size() int {
+	return xxx_messageInfo_Clip.Size(m)
+}
+func (m *Clip) XXX_DiscardUnknown() {
+	xxx_messageInfo_Clip.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Clip proto.InternalMessageInfo
+
+func (m *Clip) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *Clip) GetParts() []string {
+	if m != nil {
+		return m.Parts
+	}
+	return nil
+}
+
+func (m *Clip) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Clip) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *Clip) GetStartAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartAt
+	}
+	return nil
+}
+
+func (m *Clip) GetEndAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndAt
+	}
+	return nil
+}
+
+type ExportClipRequest struct {
+	Begin *timestamp.Timestamp `protobuf:"bytes,1,opt,name=begin,proto3" json:"begin,omitempty"`
+	End   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,3,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportClipRequest) Reset()         { *m = ExportClipRequest{} }
+func (m *ExportClipRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportClipRequest) ProtoMessage()    {}
+func (*ExportClipRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{20}
+}
+
+func (m *ExportClipRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportClipRequest.Unmarshal(m, b)
+}
+func (m *ExportClipRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportClipRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportClipRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportClipRequest.Merge(m, src)
+}
+func (m *ExportClipRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportClipRequest.Size(m)
+}
+func (m *ExportClipRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportClipRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportClipRequest proto.InternalMessageInfo
+
+func (m *ExportClipRequest) GetBegin() *timestamp.Timestamp {
+	if m != nil {
+		return m.Begin
+	}
+	return nil
+}
+
+func (m *ExportClipRequest) GetEnd() *timestamp.Timestamp {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (m *ExportClipRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type ExportClipResponse struct {
+	Clip                 *Clip    `protobuf:"bytes,1,opt,name=clip,proto3" json:"clip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportClipResponse) Reset()         { *m = ExportClipResponse{} }
+func (m *ExportClipResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportClipResponse) ProtoMessage()    {}
+func (*ExportClipResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{21}
+}
+
+func (m *ExportClipResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportClipResponse.Unmarshal(m, b)
+}
+func (m *ExportClipResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportClipResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportClipResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportClipResponse.Merge(m, src)
+}
+func (m *ExportClipResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportClipResponse.Size(m)
+}
+func (m *ExportClipResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportClipResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportClipResponse proto.InternalMessageInfo
+
+func (m *ExportClipResponse) GetClip() *Clip {
+	if m != nil {
+		return m.Clip
+	}
+	return nil
+}
+
+type Event struct {
+	Object               string               `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Label                string               `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	TriggerAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=trigger_at,json=triggerAt,proto3" json:"trigger_at,omitempty"`
+	StartAt              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	EndAt                *timestamp.Timestamp `protobuf:"bytes,5,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{22}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *Event) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *Event) GetTriggerAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.TriggerAt
+	}
+	return nil
+}
+
+func (m *Event) GetStartAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartAt
+	}
+	return nil
+}
+
+func (m *Event) GetEndAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndAt
+	}
+	return nil
+}
+
+type TriggerEventRequest struct {
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// pre-event and post-event durations, empty means use config.
+	Pre  *duration.Duration `protobuf:"bytes,2,opt,name=pre,proto3" json:"pre,omitempty"`
+	Post *duration.Duration `protobuf:"bytes,3,opt,name=post,proto3" json:"post,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,4,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TriggerEventRequest) Reset()         { *m = TriggerEventRequest{} }
+func (m *TriggerEventRequest) String() string { return proto.CompactTextString(m) }
+func (*TriggerEventRequest) ProtoMessage()    {}
+func (*TriggerEventRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{23}
+}
+
+func (m *TriggerEventRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TriggerEventRequest.Unmarshal(m, b)
+}
+func (m *TriggerEventRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TriggerEventRequest.Marshal(b, m, deterministic)
+}
+func (m *TriggerEventRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TriggerEventRequest.Merge(m, src)
+}
+func (m *TriggerEventRequest) XXX_Size() int {
+	return xxx_messageInfo_TriggerEventRequest.Size(m)
+}
+func (m *TriggerEventRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TriggerEventRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TriggerEventRequest proto.InternalMessageInfo
+
+func (m *TriggerEventRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *TriggerEventRequest) GetPre() *duration.Duration {
+	if m != nil {
+		return m.Pre
+	}
+	return nil
+}
+
+func (m *TriggerEventRequest) GetPost() *duration.Duration {
+	if m != nil {
+		return m.Post
+	}
+	return nil
+}
+
+func (m *TriggerEventRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type TriggerEventResponse struct {
+	Event                *Event   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TriggerEventResponse) Reset()         { *m = TriggerEventResponse{} }
+func (m *TriggerEventResponse) String() string { return proto.CompactTextString(m) }
+func (*TriggerEventResponse) ProtoMessage()    {}
+func (*TriggerEventResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{24}
+}
+
+func (m *TriggerEventResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TriggerEventResponse.Unmarshal(m, b)
+}
+func (m *TriggerEventResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TriggerEventResponse.Marshal(b, m, deterministic)
+}
+func (m *TriggerEventResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TriggerEventResponse.Merge(m, src)
+}
+func (m *TriggerEventResponse) XXX_Size() int {
+	return xxx_messageInfo_TriggerEventResponse.Size(m)
+}
+func (m *TriggerEventResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TriggerEventResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TriggerEventResponse proto.InternalMessageInfo
+
+func (m *TriggerEventResponse) GetEvent() *Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+// Region is normalized coordinates, 0 to 1 of frame width and height.
+type Region struct {
+	X                    float64  `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    float64  `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Width                float64  `protobuf:"fixed64,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height               float64  `protobuf:"fixed64,4,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Region) Reset()         { *m = Region{} }
+func (m *Region) String() string { return proto.CompactTextString(m) }
+func (*Region) ProtoMessage()    {}
+func (*Region) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{25}
+}
+
+func (m *Region) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Region.Unmarshal(m, b)
+}
+func (m *Region) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Region.Marshal(b, m, deterministic)
+}
+func (m *Region) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Region.Merge(m, src)
+}
+func (m *Region) XXX_Size() int {
+	return xxx_messageInfo_Region.Size(m)
+}
+func (m *Region) XXX_DiscardUnknown() {
+	xxx_messageInfo_Region.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Region proto.InternalMessageInfo
+
+func (m *Region) GetX() float64 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *Region) GetY() float64 {
+	if m != nil {
+		return m.Y
+	}
+	return 0
+}
+
+func (m *Region) GetWidth() float64 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
+}
+
+func (m *Region) GetHeight() float64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type MotionEvent struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// changed ratio of detected pixels.
+	Score float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`
+	// bounding region of changed pixels.
+	Region *Region `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	Object string  `protobuf:"bytes,5,opt,name=object,proto3" json:"object,omitempty"`
+	// snapshot object, empty if snapshot disabled.
+	Snapshot             string   `protobuf:"bytes,6,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MotionEvent) Reset()         { *m = MotionEvent{} }
+func (m *MotionEvent) String() string { return proto.CompactTextString(m) }
+func (*MotionEvent) ProtoMessage()    {}
+func (*MotionEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{26}
+}
+
+func (m *MotionEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MotionEvent.Unmarshal(m, b)
+}
+func (m *MotionEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MotionEvent.Marshal(b, m, deterministic)
+}
+func (m *MotionEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MotionEvent.Merge(m, src)
+}
+func (m *MotionEvent) XXX_Size() int {
+	return xxx_messageInfo_MotionEvent.Size(m)
+}
+func (m *MotionEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_MotionEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MotionEvent proto.InternalMessageInfo
+
+func (m *MotionEvent) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *MotionEvent) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *MotionEvent) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *MotionEvent) GetRegion() *Region {
+	if m != nil {
+		return m.Region
+	}
+	return nil
+}
+
+func (m *MotionEvent) GetObject() string {
+	if m != nil {
+		return m.Object
+	}
+	return ""
+}
+
+func (m *MotionEvent) GetSnapshot() string {
+	if m != nil {
+		return m.Snapshot
+	}
+	return ""
+}
+
+type ListMotionEventsRequest struct {
+	// optional time range.
+	Begin     *timestamp.Timestamp `protobuf:"bytes,1,opt,name=begin,proto3" json:"begin,omitempty"`
+	End       *timestamp.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	PageSize  uint32               `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string               `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,5,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListMotionEventsRequest) Reset()         { *m = ListMotionEventsRequest{} }
+func (m *ListMotionEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListMotionEventsRequest) ProtoMessage()    {}
+func (*ListMotionEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{27}
+}
+
+func (m *ListMotionEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMotionEventsRequest.Unmarshal(m, b)
+}
+func (m *ListMotionEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMotionEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListMotionEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMotionEventsRequest.Merge(m, src)
+}
+func (m *ListMotionEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListMotionEventsRequest.Size(m)
+}
+func (m *ListMotionEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMotionEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMotionEventsRequest proto.InternalMessageInfo
+
+func (m *ListMotionEventsRequest) GetBegin() *timestamp.Timestamp {
+	if m != nil {
+		return m.Begin
+	}
+	return nil
+}
+
+func (m *ListMotionEventsRequest) GetEnd() *timestamp.Timestamp {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (m *ListMotionEventsRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListMotionEventsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListMotionEventsRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type ListMotionEventsResponse struct {
+	Events               []*MotionEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken        string         `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListMotionEventsResponse) Reset()         { *m = ListMotionEventsResponse{} }
+func (m *ListMotionEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListMotionEventsResponse) ProtoMessage()    {}
+func (*ListMotionEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{28}
+}
+
+func (m *ListMotionEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMotionEventsResponse.Unmarshal(m, b)
+}
+func (m *ListMotionEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMotionEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListMotionEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMotionEventsResponse.Merge(m, src)
+}
+func (m *ListMotionEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListMotionEventsResponse.Size(m)
+}
+func (m *ListMotionEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMotionEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMotionEventsResponse proto.InternalMessageInfo
+
+func (m *ListMotionEventsResponse) GetEvents() []*MotionEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *ListMotionEventsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type Timelapse struct {
+	Sequence             string   `protobuf:"bytes,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Frames               uint32   `protobuf:"varint,2,opt,name=frames,proto3" json:"frames,omitempty"`
+	Clip                 *Clip    `protobuf:"bytes,3,opt,name=clip,proto3" json:"clip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Timelapse) Reset()         { *m = Timelapse{} }
+func (m *Timelapse) String() string { return proto.CompactTextString(m) }
+func (*Timelapse) ProtoMessage()    {}
+func (*Timelapse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{29}
+}
+
+func (m *Timelapse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Timelapse.Unmarshal(m, b)
+}
+func (m *Timelapse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Timelapse.Marshal(b, m, deterministic)
+}
+func (m *Timelapse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Timelapse.Merge(m, src)
+}
+func (m *Timelapse) XXX_Size() int {
+	return xxx_messageInfo_Timelapse.Size(m)
+}
+func (m *Timelapse) XXX_DiscardUnknown() {
+	xxx_messageInfo_Timelapse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Timelapse proto.InternalMessageInfo
+
+func (m *Timelapse) GetSequence() string {
+	if m != nil {
+		return m.Sequence
+	}
+	return ""
+}
+
+func (m *Timelapse) GetFrames() uint32 {
+	if m != nil {
+		return m.Frames
+	}
+	return 0
+}
+
+func (m *Timelapse) GetClip() *Clip {
+	if m != nil {
+		return m.Clip
+	}
+	return nil
+}
+
+type AssembleTimelapseRequest struct {
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,1,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AssembleTimelapseRequest) Reset()         { *m = AssembleTimelapseRequest{} }
+func (m *AssembleTimelapseRequest) String() string { return proto.CompactTextString(m) }
+func (*AssembleTimelapseRequest) ProtoMessage()    {}
+func (*AssembleTimelapseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{30}
+}
+
+func (m *AssembleTimelapseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssembleTimelapseRequest.Unmarshal(m, b)
+}
+func (m *AssembleTimelapseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssembleTimelapseRequest.Marshal(b, m, deterministic)
+}
+func (m *AssembleTimelapseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssembleTimelapseRequest.Merge(m, src)
+}
+func (m *AssembleTimelapseRequest) XXX_Size() int {
+	return xxx_messageInfo_AssembleTimelapseRequest.Size(m)
+}
+func (m *AssembleTimelapseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssembleTimelapseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssembleTimelapseRequest proto.InternalMessageInfo
+
+func (m *AssembleTimelapseRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type AssembleTimelapseResponse struct {
+	Timelapse            *Timelapse `protobuf:"bytes,1,opt,name=timelapse,proto3" json:"timelapse,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *AssembleTimelapseResponse) Reset()         { *m = AssembleTimelapseResponse{} }
+func (m *AssembleTimelapseResponse) String() string { return proto.CompactTextString(m) }
+func (*AssembleTimelapseResponse) ProtoMessage()    {}
+func (*AssembleTimelapseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{31}
+}
+
+func (m *AssembleTimelapseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssembleTimelapseResponse.Unmarshal(m, b)
+}
+func (m *AssembleTimelapseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssembleTimelapseResponse.Marshal(b, m, deterministic)
+}
+func (m *AssembleTimelapseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssembleTimelapseResponse.Merge(m, src)
+}
+func (m *AssembleTimelapseResponse) XXX_Size() int {
+	return xxx_messageInfo_AssembleTimelapseResponse.Size(m)
+}
+func (m *AssembleTimelapseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssembleTimelapseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssembleTimelapseResponse proto.InternalMessageInfo
+
+func (m *AssembleTimelapseResponse) GetTimelapse() *Timelapse {
+	if m != nil {
+		return m.Timelapse
+	}
+	return nil
+}
+
+type RotateStreamKeyRequest struct {
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,1,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RotateStreamKeyRequest) Reset()         { *m = RotateStreamKeyRequest{} }
+func (m *RotateStreamKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*RotateStreamKeyRequest) ProtoMessage()    {}
+func (*RotateStreamKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{32}
+}
+
+func (m *RotateStreamKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateStreamKeyRequest.Unmarshal(m, b)
+}
+func (m *RotateStreamKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateStreamKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *RotateStreamKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateStreamKeyRequest.Merge(m, src)
+}
+func (m *RotateStreamKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_RotateStreamKeyRequest.Size(m)
+}
+func (m *RotateStreamKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateStreamKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateStreamKeyRequest proto.InternalMessageInfo
+
+func (m *RotateStreamKeyRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type RotateStreamKeyResponse struct {
+	Outputs              []*Output `protobuf:"bytes,1,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RotateStreamKeyResponse) Reset()         { *m = RotateStreamKeyResponse{} }
+func (m *RotateStreamKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*RotateStreamKeyResponse) ProtoMessage()    {}
+func (*RotateStreamKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{33}
+}
+
+func (m *RotateStreamKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RotateStreamKeyResponse.Unmarshal(m, b)
+}
+func (m *RotateStreamKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RotateStreamKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *RotateStreamKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RotateStreamKeyResponse.Merge(m, src)
+}
+func (m *RotateStreamKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_RotateStreamKeyResponse.Size(m)
+}
+func (m *RotateStreamKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RotateStreamKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RotateStreamKeyResponse proto.InternalMessageInfo
+
+func (m *RotateStreamKeyResponse) GetOutputs() []*Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+type NegotiateWebrtcRequest struct {
+	// label of webrtc output, empty means the first webrtc output.
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	// sdp offer of browser, candidates are gathered before sent.
+	Offer string `protobuf:"bytes,2,opt,name=offer,proto3" json:"offer,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,3,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NegotiateWebrtcRequest) Reset()         { *m = NegotiateWebrtcRequest{} }
+func (m *NegotiateWebrtcRequest) String() string { return proto.CompactTextString(m) }
+func (*NegotiateWebrtcRequest) ProtoMessage()    {}
+func (*NegotiateWebrtcRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{34}
+}
+
+func (m *NegotiateWebrtcRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NegotiateWebrtcRequest.Unmarshal(m, b)
+}
+func (m *NegotiateWebrtcRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NegotiateWebrtcRequest.Marshal(b, m, deterministic)
+}
+func (m *NegotiateWebrtcRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NegotiateWebrtcRequest.Merge(m, src)
+}
+func (m *NegotiateWebrtcRequest) XXX_Size() int {
+	return xxx_messageInfo_NegotiateWebrtcRequest.Size(m)
+}
+func (m *NegotiateWebrtcRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NegotiateWebrtcRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NegotiateWebrtcRequest proto.InternalMessageInfo
+
+func (m *NegotiateWebrtcRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *NegotiateWebrtcRequest) GetOffer() string {
+	if m != nil {
+		return m.Offer
+	}
+	return ""
+}
+
+func (m *NegotiateWebrtcRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type NegotiateWebrtcResponse struct {
+	Session string `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Label   string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	// sdp answer with gathered candidates.
+	Answer               string   `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NegotiateWebrtcResponse) Reset()         { *m = NegotiateWebrtcResponse{} }
+func (m *NegotiateWebrtcResponse) String() string { return proto.CompactTextString(m) }
+func (*NegotiateWebrtcResponse) ProtoMessage()    {}
+func (*NegotiateWebrtcResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{35}
+}
+
+func (m *NegotiateWebrtcResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NegotiateWebrtcResponse.Unmarshal(m, b)
+}
+func (m *NegotiateWebrtcResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NegotiateWebrtcResponse.Marshal(b, m, deterministic)
+}
+func (m *NegotiateWebrtcResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NegotiateWebrtcResponse.Merge(m, src)
+}
+func (m *NegotiateWebrtcResponse) XXX_Size() int {
+	return xxx_messageInfo_NegotiateWebrtcResponse.Size(m)
+}
+func (m *NegotiateWebrtcResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NegotiateWebrtcResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NegotiateWebrtcResponse proto.InternalMessageInfo
+
+func (m *NegotiateWebrtcResponse) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *NegotiateWebrtcResponse) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *NegotiateWebrtcResponse) GetAnswer() string {
+	if m != nil {
+		return m.Answer
+	}
+	return ""
+}
+
+type CloseWebrtcRequest struct {
+	Session string `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	// camera name, empty means the only camera.
+	Camera               string   `protobuf:"bytes,2,opt,name=camera,proto3" json:"camera,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloseWebrtcRequest) Reset()         { *m = CloseWebrtcRequest{} }
+func (m *CloseWebrtcRequest) String() string { return proto.CompactTextString(m) }
+func (*CloseWebrtcRequest) ProtoMessage()    {}
+func (*CloseWebrtcRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{36}
+}
+
+func (m *CloseWebrtcRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloseWebrtcRequest.Unmarshal(m, b)
+}
+func (m *CloseWebrtcRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloseWebrtcRequest.Marshal(b, m, deterministic)
+}
+func (m *CloseWebrtcRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloseWebrtcRequest.Merge(m, src)
+}
+func (m *CloseWebrtcRequest) XXX_Size() int {
+	return xxx_messageInfo_CloseWebrtcRequest.Size(m)
+}
+func (m *CloseWebrtcRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloseWebrtcRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloseWebrtcRequest proto.InternalMessageInfo
+
+func (m *CloseWebrtcRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *CloseWebrtcRequest) GetCamera() string {
+	if m != nil {
+		return m.Camera
+	}
+	return ""
+}
+
+type FrameInterval struct {
+	Numerator            uint32   `protobuf:"varint,1,opt,name=numerator,proto3" json:"numerator,omitempty"`
+	Denominator          uint32   `protobuf:"varint,2,opt,name=denominator,proto3" json:"denominator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FrameInterval) Reset()         { *m = FrameInterval{} }
+func (m *FrameInterval) String() string { return proto.CompactTextString(m) }
+func (*FrameInterval) ProtoMessage()    {}
+func (*FrameInterval) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{37}
+}
+
+func (m *FrameInterval) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FrameInterval.Unmarshal(m, b)
+}
+func (m *FrameInterval) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FrameInterval.Marshal(b, m, deterministic)
+}
+func (m *FrameInterval) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FrameInterval.Merge(m, src)
+}
+func (m *FrameInterval) XXX_Size() int {
+	return xxx_messageInfo_FrameInterval.Size(m)
+}
+func (m *FrameInterval) XXX_DiscardUnknown() {
+	xxx_messageInfo_FrameInterval.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FrameInterval proto.InternalMessageInfo
+
+func (m *FrameInterval) GetNumerator() uint32 {
+	if m != nil {
+		return m.Numerator
+	}
+	return 0
+}
+
+func (m *FrameInterval) GetDenominator() uint32 {
+	if m != nil {
+		return m.Denominator
+	}
+	return 0
+}
+
+type FrameSize struct {
+	Width  uint32 `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height uint32 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// stepwise frame size from width x height to max_width x max_height if max_width set.
+	MaxWidth   uint32 `protobuf:"varint,3,opt,name=max_width,json=maxWidth,proto3" json:"max_width,omitempty"`
+	MaxHeight  uint32 `protobuf:"varint,4,opt,name=max_height,json=maxHeight,proto3" json:"max_height,omitempty"`
+	StepWidth  uint32 `protobuf:"varint,5,opt,name=step_width,json=stepWidth,proto3" json:"step_width,omitempty"`
+	StepHeight uint32 `protobuf:"varint,6,opt,name=step_height,json=stepHeight,proto3" json:"step_height,omitempty"`
+	// discrete intervals, or min and max intervals if stepwise_intervals.
+	Intervals            []*FrameInterval `protobuf:"bytes,7,rep,name=intervals,proto3" json:"intervals,omitempty"`
+	StepwiseIntervals    bool             `protobuf:"varint,8,opt,name=stepwise_intervals,json=stepwiseIntervals,proto3" json:"stepwise_intervals,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *FrameSize) Reset()         { *m = FrameSize{} }
+func (m *FrameSize) String() string { return proto.CompactTextString(m) }
+func (*FrameSize) ProtoMessage()    {}
+func (*FrameSize) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{38}
+}
+
+func (m *FrameSize) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FrameSize.Unmarshal(m, b)
+}
+func (m *FrameSize) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FrameSize.Marshal(b, m, deterministic)
+}
+func (m *FrameSize) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FrameSize.Merge(m, src)
+}
+func (m *FrameSize) XXX_Size() int {
+	return xxx_messageInfo_FrameSize.Size(m)
+}
+func (m *FrameSize) XXX_DiscardUnknown() {
+	xxx_messageInfo_FrameSize.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FrameSize proto.InternalMessageInfo
+
+func (m *FrameSize) GetWidth() uint32 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
+}
+
+func (m *FrameSize) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *FrameSize) GetMaxWidth() uint32 {
+	if m != nil {
+		return m.MaxWidth
+	}
+	return 0
+}
+
+func (m *FrameSize) GetMaxHeight() uint32 {
+	if m != nil {
+		return m.MaxHeight
+	}
+	return 0
+}
+
+func (m *FrameSize) GetStepWidth() uint32 {
+	if m != nil {
+		return m.StepWidth
+	}
+	return 0
+}
+
+func (m *FrameSize) GetStepHeight() uint32 {
+	if m != nil {
+		return m.StepHeight
+	}
+	return 0
+}
+
+func (m *FrameSize) GetIntervals() []*FrameInterval {
+	if m != nil {
+		return m.Intervals
+	}
+	return nil
+}
+
+func (m *FrameSize) GetStepwiseIntervals() bool {
+	if m != nil {
+		return m.StepwiseIntervals
+	}
+	return false
+}
+
+type DeviceFormat struct {
+	// fourcc of pixel format, like `YUYV` and `MJPG`.
+	PixelFormat          string       `protobuf:"bytes,1,opt,name=pixel_format,json=pixelFormat,proto3" json:"pixel_format,omitempty"`
+	Description          string       `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Compressed           bool         `protobuf:"varint,3,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	FrameSizes           []*FrameSize `protobuf:"bytes,4,rep,name=frame_sizes,json=frameSizes,proto3" json:"frame_sizes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *DeviceFormat) Reset()         { *m = DeviceFormat{} }
+func (m *DeviceFormat) String() string { return proto.CompactTextString(m) }
+func (*DeviceFormat) ProtoMessage()    {}
+func (*DeviceFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{39}
+}
+
+func (m *DeviceFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceFormat.Unmarshal(m, b)
+}
+func (m *DeviceFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceFormat.Marshal(b, m, deterministic)
+}
+func (m *DeviceFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceFormat.Merge(m, src)
+}
+func (m *DeviceFormat) XXX_Size() int {
+	return xxx_messageInfo_DeviceFormat.Size(m)
+}
+func (m *DeviceFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceFormat proto.InternalMessageInfo
+
+func (m *DeviceFormat) GetPixelFormat() string {
+	if m != nil {
+		return m.PixelFormat
+	}
+	return ""
+}
+
+func (m *DeviceFormat) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *DeviceFormat) GetCompressed() bool {
+	if m != nil {
+		return m.Compressed
+	}
+	return false
+}
+
+func (m *DeviceFormat) GetFrameSizes() []*FrameSize {
+	if m != nil {
+		return m.FrameSizes
+	}
+	return nil
+}
+
+type Device struct {
+	// device node name, like `video0`.
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path    string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Card    string `protobuf:"bytes,3,opt,name=card,proto3" json:"card,omitempty"`
+	Driver  string `protobuf:"bytes,4,opt,name=driver,proto3" json:"driver,omitempty"`
+	BusInfo string `protobuf:"bytes,5,opt,name=bus_info,json=busInfo,proto3" json:"bus_info,omitempty"`
+	// false for metadata nodes of usb cameras.
+	Capture bool            `protobuf:"varint,6,opt,name=capture,proto3" json:"capture,omitempty"`
+	Formats []*DeviceFormat `protobuf:"bytes,7,rep,name=formats,proto3" json:"formats,omitempty"`
+	// error of querying device, like permission denied.
+	Error string `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	// index of device node in sysfs, capture node of usb camera is 0.
+	Index int32 `protobuf:"varint,9,opt,name=index,proto3" json:"index,omitempty"`
+	// usb vendor id, product id and serial number, empty if not usb device.
+	Vendor  string `protobuf:"bytes,10,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Product string `protobuf:"bytes,11,opt,name=product,proto3" json:"product,omitempty"`
+	Serial  string `protobuf:"bytes,12,opt,name=serial,proto3" json:"serial,omitempty"`
+	// link names in `/dev/v4l/by-id` and `/dev/v4l/by-path`, used to select device stably.
+	ById                 string   `protobuf:"bytes,13,opt,name=by_id,json=byId,proto3" json:"by_id,omitempty"`
+	ByPath               string   `protobuf:"bytes,14,opt,name=by_path,json=byPath,proto3" json:"by_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Device) Reset()         { *m = Device{} }
+func (m *Device) String() string { return proto.CompactTextString(m) }
+func (*Device) ProtoMessage()    {}
+func (*Device) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{40}
+}
+
+func (m *Device) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Device.Unmarshal(m, b)
+}
+func (m *Device) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Device.Marshal(b, m, deterministic)
+}
+func (m *Device) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Device.Merge(m, src)
+}
+func (m *Device) XXX_Size() int {
+	return xxx_messageInfo_Device.Size(m)
+}
+func (m *Device) XXX_DiscardUnknown() {
+	xxx_messageInfo_Device.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Device proto.InternalMessageInfo
+
+func (m *Device) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Device) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *Device) GetCard() string {
+	if m != nil {
+		return m.Card
+	}
+	return ""
+}
+
+func (m *Device) GetDriver() string {
+	if m != nil {
+		return m.Driver
+	}
+	return ""
+}
+
+func (m *Device) GetBusInfo() string {
+	if m != nil {
+		return m.BusInfo
+	}
+	return ""
+}
+
+func (m *Device) GetCapture() bool {
+	if m != nil {
+		return m.Capture
+	}
+	return false
+}
+
+func (m *Device) GetFormats() []*DeviceFormat {
+	if m != nil {
+		return m.Formats
+	}
+	return nil
+}
+
+func (m *Device) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *Device) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Device) GetVendor() string {
+	if m != nil {
+		return m.Vendor
+	}
+	return ""
+}
+
+func (m *Device) GetProduct() string {
+	if m != nil {
+		return m.Product
+	}
+	return ""
+}
+
+func (m *Device) GetSerial() string {
+	if m != nil {
+		return m.Serial
+	}
+	return ""
+}
+
+func (m *Device) GetById() string {
+	if m != nil {
+		return m.ById
+	}
+	return ""
+}
+
+func (m *Device) GetByPath() string {
+	if m != nil {
+		return m.ByPath
+	}
+	return ""
+}
+
+type ListDevicesResponse struct {
+	Devices              []*Device `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListDevicesResponse) Reset()         { *m = ListDevicesResponse{} }
+func (m *ListDevicesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDevicesResponse) ProtoMessage()    {}
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a0b84a42fa06f626, []int{41}
+}
+
+func (m *ListDevicesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDevicesResponse.Unmarshal(m, b)
+}
+func (m *ListDevicesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDevicesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDevicesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDevicesResponse.Merge(m, src)
+}
+func (m *ListDevicesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDevicesResponse.Size(m)
+}
+func (m *ListDevicesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDevicesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDevicesResponse proto.InternalMessageInfo
+
+func (m *ListDevicesResponse) GetDevices() []*Device {
+	if m != nil {
+		return m.Devices
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Camera)(nil), "ai.metathings.component.service.camera.Camera")
+	proto.RegisterType((*ListCamerasResponse)(nil), "ai.metathings.component.service.camera.ListCamerasResponse")
+	proto.RegisterType((*Output)(nil), "ai.metathings.component.service.camera.Output")
+	proto.RegisterMapType((map[string]string)(nil), "ai.metathings.component.service.camera.Output.PlaybacksEntry")
+	proto.RegisterType((*StartRequest)(nil), "ai.metathings.component.service.camera.StartRequest")
+	proto.RegisterType((*StartResponse)(nil), "ai.metathings.component.service.camera.StartResponse")
+	proto.RegisterType((*StopRequest)(nil), "ai.metathings.component.service.camera.StopRequest")
+	proto.RegisterType((*Stats)(nil), "ai.metathings.component.service.camera.Stats")
+	proto.RegisterType((*GetStatusRequest)(nil), "ai.metathings.component.service.camera.GetStatusRequest")
+	proto.RegisterType((*GetStatusResponse)(nil), "ai.metathings.component.service.camera.GetStatusResponse")
+	proto.RegisterType((*ScheduleStatus)(nil), "ai.metathings.component.service.camera.ScheduleStatus")
+	proto.RegisterType((*SnapshotRequest)(nil), "ai.metathings.component.service.camera.SnapshotRequest")
+	proto.RegisterType((*SnapshotResponse)(nil), "ai.metathings.component.service.camera.SnapshotResponse")
+	proto.RegisterType((*StartRecordingRequest)(nil), "ai.metathings.component.service.camera.StartRecordingRequest")
+	proto.RegisterType((*StopRecordingRequest)(nil), "ai.metathings.component.service.camera.StopRecordingRequest")
+	proto.RegisterType((*Recording)(nil), "ai.metathings.component.service.camera.Recording")
+	proto.RegisterType((*ListRecordingsRequest)(nil), "ai.metathings.component.service.camera.ListRecordingsRequest")
+	proto.RegisterType((*ListRecordingsResponse)(nil), "ai.metathings.component.service.camera.ListRecordingsResponse")
+	proto.RegisterType((*GetRecordingRequest)(nil), "ai.metathings.component.service.camera.GetRecordingRequest")
+	proto.RegisterType((*GetRecordingResponse)(nil), "ai.metathings.component.service.camera.GetRecordingResponse")
+	proto.RegisterType((*Clip)(nil), "ai.metathings.component.service.camera.Clip")
+	proto.RegisterType((*ExportClipRequest)(nil), "ai.metathings.component.service.camera.ExportClipRequest")
+	proto.RegisterType((*ExportClipResponse)(nil), "ai.metathings.component.service.camera.ExportClipResponse")
+	proto.RegisterType((*Event)(nil), "ai.metathings.component.service.camera.Event")
+	proto.RegisterType((*TriggerEventRequest)(nil), "ai.metathings.component.service.camera.TriggerEventRequest")
+	proto.RegisterType((*TriggerEventResponse)(nil), "ai.metathings.component.service.camera.TriggerEventResponse")
+	proto.RegisterType((*Region)(nil), "ai.metathings.component.service.camera.Region")
+	proto.RegisterType((*MotionEvent)(nil), "ai.metathings.component.service.camera.MotionEvent")
+	proto.RegisterType((*ListMotionEventsRequest)(nil), "ai.metathings.component.service.camera.ListMotionEventsRequest")
+	proto.RegisterType((*ListMotionEventsResponse)(nil), "ai.metathings.component.service.camera.ListMotionEventsResponse")
+	proto.RegisterType((*Timelapse)(nil), "ai.metathings.component.service.camera.Timelapse")
+	proto.RegisterType((*AssembleTimelapseRequest)(nil), "ai.metathings.component.service.camera.AssembleTimelapseRequest")
+	proto.RegisterType((*AssembleTimelapseResponse)(nil), "ai.metathings.component.service.camera.AssembleTimelapseResponse")
+	proto.RegisterType((*RotateStreamKeyRequest)(nil), "ai.metathings.component.service.camera.RotateStreamKeyRequest")
+	proto.RegisterType((*RotateStreamKeyResponse)(nil), "ai.metathings.component.service.camera.RotateStreamKeyResponse")
+	proto.RegisterType((*NegotiateWebrtcRequest)(nil), "ai.metathings.component.service.camera.NegotiateWebrtcRequest")
+	proto.RegisterType((*NegotiateWebrtcResponse)(nil), "ai.metathings.component.service.camera.NegotiateWebrtcResponse")
+	proto.RegisterType((*CloseWebrtcRequest)(nil), "ai.metathings.component.service.camera.CloseWebrtcRequest")
+	proto.RegisterType((*FrameInterval)(nil), "ai.metathings.component.service.camera.FrameInterval")
+	proto.RegisterType((*FrameSize)(nil), "ai.metathings.component.service.camera.FrameSize")
+	proto.RegisterType((*DeviceFormat)(nil), "ai.metathings.component.service.camera.DeviceFormat")
+	proto.RegisterType((*Device)(nil), "ai.metathings.component.service.camera.Device")
+	proto.RegisterType((*ListDevicesResponse)(nil), "ai.metathings.component.service.camera.ListDevicesResponse")
+}
+
 func init() { proto.RegisterFile("service.proto", fileDescriptor_a0b84a42fa06f626) }
 
 var fileDescriptor_a0b84a42fa06f626 = []byte{
-	// 154 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2d, 0x4e, 0x2d, 0x2a,
-	0xcb, 0x4c, 0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x52, 0x4b, 0xcc, 0xd4, 0xcb, 0x4d,
-	0x2d, 0x49, 0x2c, 0xc9, 0xc8, 0xcc, 0x4b, 0x2f, 0xd6, 0x4b, 0xce, 0xcf, 0x2d, 0xc8, 0xcf, 0x4b,
-	0xcd, 0x2b, 0xd1, 0x83, 0x29, 0x4b, 0x4e, 0xcc, 0x4d, 0x2d, 0x4a, 0x94, 0x92, 0x4e, 0xcf, 0xcf,
-	0x4f, 0xcf, 0x49, 0xd5, 0x07, 0xeb, 0x4a, 0x2a, 0x4d, 0xd3, 0x4f, 0xcd, 0x2d, 0x28, 0xa9, 0x84,
-	0x18, 0x62, 0xd4, 0xc2, 0xc8, 0xc5, 0xeb, 0x0c, 0x56, 0x17, 0x0c, 0xd1, 0x25, 0x64, 0xc9, 0xc5,
-	0x1a, 0x5c, 0x92, 0x58, 0x54, 0x22, 0x24, 0xa6, 0x07, 0xd1, 0xa8, 0x07, 0xd3, 0xa8, 0xe7, 0x0a,
-	0xd2, 0x28, 0x85, 0x43, 0x5c, 0x89, 0x41, 0xc8, 0x82, 0x8b, 0x25, 0xb8, 0x24, 0xbf, 0x80, 0x74,
-	0x9d, 0x4e, 0x1c, 0x51, 0x6c, 0x10, 0xd7, 0x26, 0xb1, 0x81, 0xe5, 0x8c, 0x01, 0x01, 0x00, 0x00,
-	0xff, 0xff, 0x43, 0x34, 0x04, 0x1c, 0xed, 0x00, 0x00, 0x00,
+	// 2690 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x19, 0x4d, 0x6f, 0x1b, 0xc7,
+	0x35, 0xcb, 0xef, 0x7d, 0x14, 0x65, 0x79, 0xec, 0xc8, 0x6b, 0xc6, 0x0e, 0x95, 0xad, 0x1b, 0xa8,
+	0x71, 0x48, 0xd9, 0x8e, 0x93, 0xc8, 0x8e, 0x5d, 0x47, 0x52, 0xec, 0xd8, 0x49, 0xf3, 0xd1, 0x91,
+	0x9b, 0xb4, 0x51, 0x25, 0x62, 0xc8, 0x1d, 0x51, 0x1b, 0x93, 0xbb, 0x9b, 0x9d, 0xa1, 0x3e, 0xdc,
+	0x14, 0x45, 0x0a, 0xb8, 0x87, 0xf6, 0x50, 0x14, 0xfd, 0x02, 0xfa, 0x0b, 0x7a, 0xef, 0xa9, 0xbf,
+	0xa0, 0x40, 0xef, 0xbd, 0xf4, 0x22, 0x40, 0xe8, 0x25, 0x40, 0x51, 0xa0, 0xff, 0xa0, 0x98, 0x8f,
+	0x5d, 0x2e, 0x29, 0xc9, 0x5a, 0xd2, 0x39, 0xf5, 0x36, 0x6f, 0xe6, 0xbd, 0x37, 0xef, 0x6b, 0xe6,
+	0xcd, 0x7b, 0x03, 0x15, 0x46, 0xc3, 0x6d, 0xb7, 0x4d, 0x1b, 0x41, 0xe8, 0x73, 0x1f, 0xbd, 0x4c,
+	0xdc, 0x46, 0x8f, 0x72, 0xc2, 0xb7, 0x5c, 0xaf, 0xc3, 0x1a, 0x6d, 0xbf, 0x17, 0xf8, 0x1e, 0xf5,
+	0x78, 0x23, 0x42, 0x6b, 0x93, 0x1e, 0x0d, 0x49, 0xf5, 0x85, 0x8e, 0xef, 0x77, 0xba, 0x74, 0x41,
+	0x52, 0xb5, 0xfa, 0x9b, 0x0b, 0xb4, 0x17, 0xf0, 0x3d, 0xc5, 0xa4, 0x5a, 0x1b, 0x5d, 0xe4, 0x6e,
+	0x8f, 0x32, 0x4e, 0x7a, 0x81, 0x46, 0x78, 0x71, 0x14, 0xc1, 0xe9, 0x87, 0x84, 0xbb, 0xbe, 0x77,
+	0xdc, 0xfa, 0x4e, 0x48, 0x82, 0x80, 0x86, 0x4c, 0xaf, 0xbf, 0xd1, 0x71, 0xf9, 0x56, 0xbf, 0x25,
+	0xc4, 0x5b, 0xe8, 0xed, 0xb8, 0xfc, 0x91, 0xbf, 0xb3, 0xd0, 0xf1, 0xeb, 0x72, 0xb1, 0xbe, 0x4d,
+	0xba, 0xae, 0x43, 0xb8, 0x1f, 0xb2, 0x85, 0x78, 0xa8, 0xe8, 0xec, 0xf7, 0xa0, 0xb0, 0x22, 0xe5,
+	0x47, 0x08, 0x72, 0x1e, 0xe9, 0x51, 0xcb, 0x98, 0x33, 0xe6, 0x4d, 0x2c, 0xc7, 0x68, 0x16, 0x0a,
+	0x4e, 0xe8, 0x6e, 0xd3, 0xd0, 0xca, 0xc8, 0x59, 0x0d, 0xa1, 0xb3, 0x90, 0x67, 0x9c, 0x70, 0x6a,
+	0x65, 0xe5, 0xb4, 0x02, 0xec, 0x26, 0x9c, 0xf9, 0x9e, 0xcb, 0xb8, 0xe2, 0xc7, 0x30, 0x65, 0x81,
+	0xef, 0x31, 0x8a, 0xee, 0x43, 0x51, 0x99, 0x88, 0x59, 0xc6, 0x5c, 0x76, 0xbe, 0x7c, 0xad, 0xd1,
+	0x48, 0x67, 0xd2, 0x86, 0xe2, 0x84, 0x23, 0x72, 0xfb, 0x5f, 0x06, 0x14, 0x3e, 0xea, 0xf3, 0xa0,
+	0xcf, 0x85, 0x04, 0x5d, 0xd2, 0xa2, 0x5d, 0x2d, 0xae, 0x02, 0xd0, 0x0c, 0x64, 0xfb, 0x61, 0x57,
+	0x0b, 0x2b, 0x86, 0xe8, 0x1c, 0x14, 0xbb, 0xee, 0x36, 0x6d, 0xba, 0x8e, 0x96, 0xb5, 0x20, 0xc0,
+	0x07, 0x0e, 0x5a, 0x03, 0x33, 0xe8, 0x92, 0xbd, 0x16, 0x69, 0x3f, 0x62, 0x56, 0x4e, 0xca, 0x75,
+	0x3b, 0xad, 0x5c, 0x4a, 0x86, 0xc6, 0xc7, 0x11, 0xfd, 0x5d, 0x8f, 0x87, 0x7b, 0x78, 0xc0, 0xaf,
+	0x7a, 0x0b, 0xa6, 0x87, 0x17, 0x85, 0x64, 0x8f, 0xe8, 0x9e, 0x96, 0x56, 0x0c, 0x85, 0x06, 0xdb,
+	0xa4, 0xdb, 0xa7, 0x5a, 0x5a, 0x05, 0xdc, 0xcc, 0x2c, 0x1a, 0xf6, 0x57, 0x59, 0x98, 0x5a, 0xe5,
+	0x24, 0xe4, 0x98, 0x7e, 0xd1, 0xa7, 0x8c, 0xa3, 0x25, 0x80, 0xcd, 0x90, 0xf4, 0x68, 0x93, 0xb9,
+	0x8f, 0xb5, 0x83, 0x96, 0xed, 0x83, 0xfd, 0xda, 0x8b, 0x70, 0x61, 0x63, 0x7e, 0xed, 0x6a, 0xfd,
+	0xc6, 0xfa, 0xda, 0x95, 0xfa, 0x8d, 0xf5, 0x57, 0x76, 0x13, 0xe3, 0xef, 0xdc, 0xb9, 0x84, 0x4d,
+	0x49, 0xb5, 0xea, 0x3e, 0xa6, 0xe8, 0xe5, 0x88, 0x45, 0x48, 0xb8, 0xda, 0xb2, 0xb2, 0x5c, 0x3c,
+	0xd8, 0xaf, 0x65, 0xad, 0xff, 0x1a, 0x1a, 0x0f, 0x13, 0x4e, 0xd1, 0x22, 0x94, 0x5a, 0x2e, 0x57,
+	0x58, 0xd2, 0x60, 0xcb, 0x17, 0x0f, 0xf6, 0x6b, 0xe7, 0xe1, 0xdc, 0xd0, 0x46, 0x6b, 0x8f, 0xde,
+	0xef, 0x7d, 0xb0, 0x7e, 0x47, 0xec, 0x51, 0x6c, 0xb9, 0x5c, 0x52, 0x5e, 0x86, 0x7c, 0xdb, 0x77,
+	0x68, 0xdb, 0xca, 0x49, 0xb2, 0xe7, 0x0f, 0xf6, 0x6b, 0xa7, 0xe1, 0xd4, 0xc6, 0xda, 0x52, 0xfd,
+	0x33, 0x52, 0x7f, 0x7c, 0xa5, 0x7e, 0xa3, 0xb9, 0xfe, 0xca, 0x25, 0xac, 0x70, 0xd0, 0x6d, 0x28,
+	0xf8, 0xd2, 0x88, 0x56, 0x5e, 0x62, 0x7f, 0xfb, 0x60, 0xbf, 0xf6, 0x12, 0xd4, 0x36, 0xe6, 0xd7,
+	0x48, 0xfd, 0xf1, 0xfa, 0x9a, 0x22, 0xb8, 0xdc, 0xa8, 0xaf, 0xbf, 0x72, 0x73, 0x61, 0x61, 0x6d,
+	0xe3, 0xc7, 0x6c, 0xfd, 0xb2, 0xd8, 0x4c, 0x13, 0xa1, 0x2b, 0x90, 0x27, 0x7d, 0xc7, 0xf5, 0xad,
+	0xc2, 0x9c, 0x31, 0x5f, 0xbe, 0x56, 0x6d, 0xa8, 0xd3, 0xd1, 0x88, 0x4e, 0x47, 0x63, 0xd9, 0xf7,
+	0xbb, 0x9f, 0x08, 0x83, 0x62, 0x85, 0x88, 0x1a, 0x50, 0x50, 0xce, 0xb3, 0x8a, 0x72, 0xc3, 0xd9,
+	0x83, 0xfd, 0x1a, 0x82, 0x99, 0xa4, 0x78, 0x75, 0x21, 0x9f, 0xc6, 0xb2, 0x7f, 0x04, 0x15, 0xed,
+	0x82, 0x41, 0x14, 0xab, 0xcd, 0xc7, 0x8e, 0x62, 0x15, 0x2d, 0x38, 0x22, 0xb7, 0x6f, 0x43, 0x79,
+	0x95, 0xfb, 0x41, 0xe4, 0xdc, 0x81, 0x64, 0x46, 0x2a, 0xc9, 0xbe, 0xca, 0x40, 0x7e, 0x95, 0x13,
+	0xce, 0x44, 0x04, 0x49, 0xc7, 0x49, 0xc2, 0x2c, 0x56, 0x80, 0x88, 0xb4, 0xcd, 0x80, 0x49, 0x17,
+	0x1b, 0x58, 0x0c, 0xd1, 0xf9, 0x11, 0x9f, 0x1a, 0x03, 0xa7, 0x21, 0xc8, 0xc9, 0x98, 0xca, 0xa9,
+	0x43, 0x2f, 0xc6, 0x62, 0x4e, 0xdc, 0x4e, 0xca, 0x33, 0x58, 0x8e, 0xd1, 0x45, 0x00, 0xa7, 0x1f,
+	0x34, 0xe5, 0x0e, 0x4c, 0x5a, 0x3d, 0x8b, 0x4d, 0xa7, 0x1f, 0xdc, 0x93, 0x13, 0xa8, 0x06, 0x65,
+	0x27, 0xf4, 0xe3, 0xf5, 0xa2, 0x5c, 0x07, 0x31, 0xa5, 0x11, 0xc4, 0x85, 0x11, 0x50, 0xea, 0x58,
+	0x25, 0xb9, 0xbf, 0x02, 0xd0, 0x9b, 0x60, 0xf6, 0x03, 0x87, 0x70, 0xda, 0x24, 0xdc, 0x32, 0x8f,
+	0x71, 0xe5, 0xc3, 0xe8, 0xa6, 0xc4, 0x25, 0x85, 0xbc, 0xc4, 0xed, 0x65, 0x98, 0x79, 0x97, 0x72,
+	0x61, 0x85, 0x3e, 0x9b, 0xd4, 0x8e, 0x7f, 0xce, 0xc1, 0xe9, 0x04, 0x13, 0xed, 0xe6, 0xf8, 0x66,
+	0x33, 0x12, 0x37, 0x5b, 0xd2, 0xf9, 0x99, 0x67, 0x72, 0xbe, 0xf0, 0x4e, 0xa0, 0xef, 0xa2, 0x3c,
+	0x16, 0x43, 0xf4, 0x3a, 0x94, 0x98, 0x88, 0x34, 0x61, 0x83, 0xdc, 0x89, 0x36, 0x28, 0x4a, 0xdc,
+	0x25, 0x8e, 0xae, 0x42, 0xa1, 0x1f, 0xc4, 0x7e, 0x2a, 0x5f, 0x3b, 0x7f, 0x88, 0xe8, 0x1d, 0x9d,
+	0x41, 0xb0, 0x46, 0x44, 0xdf, 0x82, 0x4a, 0x48, 0xd5, 0x5e, 0x6d, 0xbf, 0xef, 0x71, 0xe9, 0xc7,
+	0x3c, 0x9e, 0xd2, 0x93, 0x2b, 0x62, 0x4e, 0x78, 0xba, 0x4b, 0x18, 0x6f, 0xd2, 0x30, 0xf4, 0x43,
+	0x75, 0x58, 0xb0, 0x29, 0x66, 0xee, 0x8a, 0x09, 0x34, 0x0f, 0x33, 0x6a, 0x79, 0x57, 0x44, 0x14,
+	0x25, 0xcc, 0xf7, 0xa4, 0x4f, 0x4d, 0x3c, 0x2d, 0x91, 0x76, 0x5d, 0x8e, 0xe5, 0x2c, 0x5a, 0x51,
+	0x96, 0x64, 0xda, 0xb1, 0xf5, 0xb4, 0x16, 0x93, 0xb1, 0xad, 0x0c, 0xcf, 0x44, 0x2c, 0x76, 0xfd,
+	0x0e, 0xb3, 0x60, 0x2e, 0x2b, 0x62, 0x51, 0x8c, 0xd1, 0x05, 0x30, 0x43, 0xda, 0xf6, 0x43, 0xc7,
+	0xf5, 0x3a, 0x56, 0x79, 0xce, 0x98, 0x2f, 0xe1, 0xc1, 0x04, 0xc2, 0x50, 0x62, 0xed, 0x2d, 0xea,
+	0xf4, 0xbb, 0xd4, 0x9a, 0x92, 0x3b, 0xbf, 0x91, 0x7a, 0x67, 0x4d, 0xa7, 0x43, 0x22, 0xe6, 0x63,
+	0xff, 0xde, 0x80, 0xe9, 0xe1, 0x45, 0x54, 0x85, 0x92, 0xbf, 0x4d, 0xc3, 0xd0, 0x75, 0x54, 0xa8,
+	0x94, 0x70, 0x0c, 0xa3, 0xfb, 0x80, 0x3c, 0xba, 0xcb, 0x9b, 0x3c, 0x24, 0x1e, 0x73, 0x85, 0x0b,
+	0x84, 0x6f, 0x33, 0x27, 0xfa, 0x76, 0x46, 0x50, 0x3d, 0x8c, 0x89, 0x96, 0xa4, 0x33, 0x24, 0xa7,
+	0x64, 0xb2, 0x35, 0xc5, 0xcc, 0xaa, 0x4c, 0xb8, 0x4f, 0x32, 0x70, 0x6a, 0xd5, 0x23, 0x01, 0xdb,
+	0xf2, 0xe3, 0x5c, 0x71, 0x19, 0x0a, 0x9b, 0x7e, 0xd8, 0x23, 0x5c, 0x1f, 0x83, 0x33, 0x07, 0xfb,
+	0xb5, 0x53, 0x50, 0xd9, 0x98, 0xff, 0x3c, 0xa0, 0x9d, 0x2f, 0x03, 0xaf, 0x23, 0xef, 0x51, 0x85,
+	0x32, 0x92, 0x58, 0x32, 0x93, 0x24, 0x96, 0x39, 0x28, 0x7e, 0xd1, 0x27, 0x5d, 0x97, 0xef, 0x49,
+	0xf9, 0x2a, 0xcb, 0x85, 0x83, 0xfd, 0x5a, 0xc6, 0xa2, 0x38, 0x9a, 0x16, 0x97, 0x35, 0xe3, 0x7e,
+	0x48, 0xad, 0xdc, 0xc9, 0x97, 0xb5, 0x44, 0x4c, 0x1c, 0xe5, 0x7c, 0xaa, 0xa3, 0xfc, 0x07, 0x03,
+	0x66, 0x06, 0x76, 0xd0, 0x27, 0xd9, 0x82, 0x62, 0xdb, 0xf7, 0x38, 0xf5, 0x94, 0x25, 0xa6, 0x70,
+	0x04, 0x8a, 0x57, 0x8d, 0x36, 0x91, 0x7e, 0xd5, 0x28, 0x48, 0xcc, 0xfb, 0xad, 0xcf, 0x69, 0x9b,
+	0x47, 0x4f, 0x05, 0x05, 0xa1, 0x45, 0x30, 0xe3, 0xe7, 0x5a, 0x8a, 0x23, 0x3a, 0x40, 0xb6, 0xdf,
+	0x85, 0xe7, 0x75, 0x16, 0xd1, 0xe1, 0x39, 0xe9, 0x65, 0x75, 0x0f, 0xce, 0xaa, 0x9c, 0xf1, 0x8c,
+	0x7c, 0x7e, 0x99, 0x01, 0x33, 0x66, 0x72, 0xe4, 0x93, 0x2f, 0x79, 0x1d, 0x65, 0xc6, 0xba, 0x8e,
+	0xa8, 0xe7, 0x34, 0x89, 0xb2, 0xdd, 0xd3, 0x89, 0xf2, 0xd4, 0x73, 0x96, 0xb8, 0xd8, 0x29, 0x7a,
+	0xe4, 0x5a, 0xb9, 0x93, 0xee, 0xb0, 0x18, 0x35, 0x4e, 0x59, 0x79, 0x99, 0x64, 0xe4, 0x38, 0xe1,
+	0xd1, 0xc2, 0x90, 0x47, 0x87, 0xae, 0x8a, 0xe2, 0xc8, 0x55, 0x61, 0xff, 0xdb, 0x80, 0xe7, 0xc5,
+	0x83, 0x35, 0x36, 0x48, 0x9c, 0x4b, 0xae, 0x40, 0xbe, 0x45, 0x3b, 0xae, 0x67, 0x19, 0x27, 0x2b,
+	0x23, 0x11, 0xd1, 0xab, 0x90, 0xa5, 0x9e, 0x93, 0xc2, 0x62, 0x02, 0x0d, 0x5d, 0x02, 0x33, 0x20,
+	0x1d, 0x7d, 0xec, 0xb2, 0x89, 0xc7, 0xd8, 0xd7, 0x45, 0x5c, 0x12, 0x2b, 0xf2, 0x68, 0x5d, 0x04,
+	0x90, 0x58, 0xdc, 0x7f, 0x44, 0x3d, 0x9d, 0xa2, 0x25, 0xdd, 0x43, 0x31, 0x31, 0xf6, 0x29, 0xf9,
+	0xad, 0x01, 0xb3, 0xa3, 0xea, 0xea, 0xb3, 0xf2, 0x7d, 0x80, 0xd8, 0x2c, 0xd1, 0xfb, 0xe6, 0x6a,
+	0xda, 0x6b, 0x73, 0x10, 0x94, 0x09, 0x26, 0xe8, 0x65, 0x38, 0x25, 0xaf, 0xae, 0x84, 0x06, 0xea,
+	0xb4, 0x55, 0xc4, 0xf4, 0xc7, 0x91, 0x16, 0xf6, 0x16, 0x9c, 0x79, 0x97, 0x1e, 0x3e, 0x20, 0x2f,
+	0x25, 0x43, 0x73, 0xb9, 0x72, 0xb0, 0x5f, 0x33, 0xa1, 0xb8, 0xb1, 0xb6, 0xb1, 0xb0, 0x7e, 0xf9,
+	0x92, 0x8e, 0xd4, 0x81, 0xfe, 0x99, 0x54, 0xfa, 0x77, 0xe0, 0xec, 0xf0, 0x4e, 0x5a, 0xf9, 0x8f,
+	0x92, 0x41, 0xa2, 0x1c, 0x3e, 0x81, 0xee, 0x89, 0xb8, 0xfa, 0xbb, 0x01, 0xb9, 0x95, 0xae, 0x1b,
+	0x24, 0x2e, 0x14, 0x63, 0xe8, 0x42, 0x39, 0x0b, 0xf9, 0x80, 0x84, 0xfa, 0x31, 0x61, 0x62, 0x05,
+	0xc4, 0x81, 0x9d, 0x3d, 0x32, 0xb0, 0x73, 0x43, 0x81, 0x9d, 0x3c, 0xa5, 0xf9, 0x49, 0x4e, 0x69,
+	0x21, 0xe5, 0x29, 0xb5, 0xff, 0x62, 0xc0, 0xe9, 0xbb, 0xbb, 0x81, 0x1f, 0x72, 0xa1, 0x52, 0xe4,
+	0x9e, 0xc5, 0xd4, 0x07, 0x44, 0xe5, 0x83, 0x39, 0x23, 0x3a, 0x28, 0xd7, 0x53, 0x1e, 0x94, 0x98,
+	0x4e, 0x1e, 0x98, 0x81, 0xaf, 0xb3, 0xa9, 0x7c, 0xfd, 0x09, 0xa0, 0xa4, 0xd0, 0xda, 0xd3, 0x6f,
+	0x43, 0xae, 0xdd, 0x75, 0x03, 0x2d, 0xf4, 0xab, 0xa9, 0xcb, 0x50, 0xc1, 0x43, 0x52, 0xda, 0xff,
+	0x34, 0x20, 0x7f, 0x77, 0x5b, 0x27, 0x91, 0xe3, 0x7c, 0xab, 0x0a, 0xd3, 0x4c, 0xb2, 0x30, 0xbd,
+	0x01, 0xc0, 0x43, 0xb7, 0xd3, 0xa1, 0x61, 0xba, 0x2b, 0xd2, 0xd4, 0xd8, 0x4b, 0xfc, 0x19, 0xde,
+	0x87, 0xda, 0xd5, 0xf9, 0xb4, 0xae, 0xfe, 0x87, 0x01, 0x67, 0x1e, 0xaa, 0x7d, 0xa5, 0x8e, 0x91,
+	0xb3, 0xaf, 0x0d, 0xd5, 0xda, 0xcb, 0x17, 0x0e, 0xf6, 0x6b, 0x16, 0xcc, 0x26, 0x6d, 0xdf, 0xa8,
+	0xaf, 0xff, 0xe4, 0xca, 0xab, 0x6f, 0x5c, 0xff, 0xe9, 0xa5, 0x48, 0xe1, 0xcb, 0x90, 0x0d, 0x42,
+	0x6a, 0x65, 0x4e, 0xba, 0xd7, 0x05, 0x16, 0xaa, 0x43, 0x2e, 0xf0, 0x59, 0x64, 0x97, 0xa7, 0x60,
+	0x4b, 0xb4, 0x44, 0x30, 0xe4, 0x52, 0x05, 0xc3, 0x1a, 0x9c, 0x1d, 0x56, 0x4b, 0x87, 0xc3, 0x0a,
+	0xe4, 0xe9, 0x76, 0xf4, 0x3e, 0x18, 0xe3, 0x85, 0xaa, 0xb8, 0x28, 0x5a, 0x1b, 0x43, 0x01, 0xd3,
+	0x8e, 0x48, 0x4c, 0x53, 0x60, 0xec, 0x4a, 0x56, 0x06, 0x36, 0x76, 0x05, 0xb4, 0xa7, 0x8b, 0x30,
+	0x43, 0x16, 0xfb, 0x3b, 0xae, 0xc3, 0xb7, 0x74, 0xfd, 0xa5, 0x00, 0x11, 0x43, 0x5b, 0xd4, 0xed,
+	0x6c, 0x29, 0xc7, 0x1a, 0x58, 0x43, 0xf6, 0xd7, 0x06, 0x94, 0x3f, 0xf0, 0x85, 0xc6, 0x2a, 0xd6,
+	0xa6, 0x21, 0xe3, 0x3a, 0x3a, 0xce, 0x32, 0xae, 0x33, 0xfc, 0x20, 0xc9, 0x8c, 0xf1, 0x20, 0x91,
+	0xe5, 0x4d, 0x5b, 0xbc, 0xc5, 0xb4, 0x1c, 0x12, 0x40, 0xf7, 0xa0, 0x10, 0x4a, 0x1d, 0x74, 0x80,
+	0x35, 0xd2, 0x5f, 0x7f, 0x1d, 0x59, 0x60, 0x28, 0xea, 0xc4, 0x99, 0xc8, 0x0f, 0x9d, 0x89, 0x2a,
+	0x94, 0x98, 0x7e, 0x9e, 0xe9, 0x04, 0x1d, 0xc3, 0xf6, 0x7f, 0x0c, 0x38, 0x27, 0xb2, 0x52, 0x42,
+	0xdf, 0xff, 0xef, 0x34, 0xfc, 0x6b, 0x03, 0xac, 0xc3, 0x0a, 0xeb, 0x90, 0x7c, 0x1f, 0x0a, 0x32,
+	0xac, 0xa2, 0x24, 0xfc, 0x5a, 0x5a, 0x4f, 0x24, 0xb8, 0x61, 0xcd, 0x22, 0x75, 0x0a, 0xfe, 0xca,
+	0x00, 0x53, 0x58, 0xa6, 0x4b, 0x02, 0x46, 0xa5, 0xb3, 0x84, 0xfd, 0xbd, 0x76, 0xf4, 0x30, 0x8c,
+	0x61, 0x99, 0x8e, 0x54, 0x89, 0x2f, 0x3b, 0x48, 0x58, 0x43, 0xf1, 0xc5, 0x9a, 0x9d, 0xf8, 0x62,
+	0x7d, 0x0f, 0xac, 0x25, 0xc6, 0x68, 0xaf, 0xd5, 0xa5, 0xb1, 0x28, 0x93, 0x3e, 0x72, 0xbb, 0x70,
+	0xfe, 0x08, 0x5e, 0x83, 0x6c, 0xcf, 0xa3, 0xc9, 0x71, 0xb3, 0xfd, 0x80, 0xdb, 0x80, 0x87, 0x7d,
+	0x1f, 0x66, 0xb1, 0xcf, 0x09, 0xa7, 0xab, 0x3c, 0xa4, 0xa4, 0xf7, 0x3e, 0xdd, 0x9b, 0x54, 0xee,
+	0x36, 0x9c, 0x3b, 0xc4, 0xe9, 0x1b, 0xef, 0x3e, 0x7d, 0x09, 0xb3, 0x1f, 0xd2, 0x8e, 0xcf, 0x5d,
+	0xc2, 0xe9, 0xa7, 0xb4, 0x15, 0xf2, 0x76, 0x24, 0xee, 0xd1, 0x2d, 0xd5, 0x0b, 0x90, 0xf7, 0x37,
+	0x37, 0xa3, 0x0e, 0xb0, 0xca, 0xca, 0x3f, 0x34, 0xb0, 0x9a, 0x1c, 0x3b, 0x2f, 0x13, 0x38, 0x77,
+	0x68, 0xf7, 0x41, 0xbd, 0xc6, 0x28, 0x63, 0xe2, 0x16, 0x52, 0x02, 0x44, 0xe0, 0x31, 0x29, 0x75,
+	0x16, 0x0a, 0xc4, 0x63, 0x3b, 0x34, 0x8c, 0xaa, 0x35, 0x05, 0xd9, 0x9b, 0x80, 0x56, 0xba, 0x3e,
+	0x1b, 0x51, 0x6e, 0x6e, 0x84, 0x7b, 0xac, 0x48, 0xbc, 0xcb, 0xb8, 0xcf, 0xc9, 0x8f, 0xa0, 0x22,
+	0x9b, 0x5b, 0x0f, 0x3c, 0x4e, 0xc3, 0x6d, 0x22, 0x2c, 0x65, 0x7a, 0x7d, 0xb1, 0xc4, 0xfd, 0x50,
+	0x6e, 0x52, 0xc1, 0x83, 0x09, 0x34, 0x07, 0x65, 0x87, 0x7a, 0x7e, 0xcf, 0xf5, 0xe4, 0xba, 0x3a,
+	0x3f, 0xc9, 0x29, 0xfb, 0xaf, 0x19, 0x30, 0xef, 0xc5, 0x75, 0x75, 0x9c, 0x31, 0x14, 0xa7, 0x43,
+	0x19, 0x43, 0x1f, 0x40, 0x05, 0xa1, 0x17, 0xc0, 0xec, 0x91, 0xdd, 0xe6, 0x20, 0xc7, 0x54, 0x70,
+	0xa9, 0x47, 0x76, 0x3f, 0x95, 0x44, 0x17, 0x01, 0xc4, 0x62, 0x22, 0xd5, 0x54, 0xb0, 0x40, 0xbf,
+	0xaf, 0x68, 0x2f, 0x02, 0x30, 0x4e, 0x03, 0x4d, 0x9c, 0x57, 0xcb, 0x62, 0x46, 0x51, 0xd7, 0xa0,
+	0x2c, 0x97, 0x35, 0x79, 0x41, 0xae, 0x4b, 0x0a, 0x4d, 0xbf, 0x0a, 0xa6, 0xab, 0x6d, 0x20, 0x5a,
+	0x7f, 0x22, 0x3a, 0x5f, 0x4f, 0x1b, 0x9d, 0x43, 0x16, 0xc4, 0x03, 0x3e, 0xa8, 0x0e, 0x48, 0x6c,
+	0xb1, 0xe3, 0x32, 0xda, 0x1c, 0x70, 0x2f, 0xc9, 0x12, 0xee, 0x74, 0xb4, 0x12, 0x11, 0x32, 0xfb,
+	0x6f, 0x06, 0x4c, 0xbd, 0x43, 0x05, 0xe7, 0x7b, 0xea, 0x81, 0xfc, 0x12, 0x4c, 0x05, 0xee, 0x2e,
+	0xed, 0x36, 0x93, 0xcd, 0x10, 0x5c, 0x96, 0x73, 0x1a, 0x45, 0x7a, 0x84, 0xb5, 0x43, 0x37, 0x90,
+	0x25, 0xa8, 0x0a, 0xae, 0xe4, 0x14, 0x7a, 0x11, 0x40, 0x48, 0x1e, 0x52, 0xc6, 0xa8, 0xea, 0xd9,
+	0x95, 0x70, 0x62, 0x06, 0x61, 0x28, 0x0f, 0xda, 0x27, 0xd1, 0x2f, 0xc2, 0xd5, 0xb1, 0x74, 0x17,
+	0xbe, 0xc6, 0x10, 0xb7, 0x53, 0x98, 0xfd, 0x24, 0x0b, 0x05, 0xa5, 0xc9, 0x91, 0xe5, 0x39, 0x82,
+	0x5c, 0x40, 0xf8, 0x96, 0x96, 0x56, 0x8e, 0xc5, 0x5c, 0x9b, 0x84, 0xd1, 0x07, 0x87, 0x1c, 0x27,
+	0x7e, 0x6e, 0x72, 0x43, 0x3f, 0x37, 0xa2, 0x17, 0xdc, 0x67, 0x4d, 0xd7, 0xdb, 0xf4, 0x75, 0x92,
+	0x2e, 0xb6, 0xfa, 0xec, 0x81, 0xb7, 0xe9, 0xcb, 0x86, 0x09, 0x09, 0x78, 0x3f, 0xa4, 0xd2, 0xc9,
+	0x25, 0x1c, 0x81, 0xe8, 0x43, 0x28, 0x2a, 0x33, 0x46, 0xfe, 0xbd, 0x9e, 0x56, 0xc7, 0xa4, 0x4f,
+	0x70, 0xc4, 0x44, 0xc4, 0xb6, 0x6a, 0x2f, 0xaa, 0xce, 0xa1, 0x02, 0xc4, 0xac, 0xeb, 0x39, 0x74,
+	0x57, 0x36, 0x0c, 0xf3, 0x58, 0x01, 0x42, 0x91, 0x6d, 0xea, 0x39, 0x7e, 0x68, 0x81, 0x52, 0x44,
+	0x41, 0x42, 0xda, 0x20, 0xf4, 0x9d, 0x7e, 0x9b, 0xcb, 0x1e, 0xa0, 0x89, 0x23, 0x50, 0x50, 0x30,
+	0x1a, 0xba, 0xa4, 0x2b, 0xfb, 0x7f, 0x26, 0xd6, 0x10, 0x3a, 0x03, 0xf9, 0xd6, 0x9e, 0xf8, 0x08,
+	0xaa, 0x28, 0x3b, 0xb5, 0xf6, 0x1e, 0x38, 0xe2, 0x7f, 0xa8, 0xb5, 0xd7, 0x94, 0x26, 0x9d, 0x56,
+	0xd8, 0xad, 0xbd, 0x8f, 0x09, 0xdf, 0x8a, 0x3e, 0xb3, 0x94, 0x02, 0x43, 0x9f, 0x59, 0x8e, 0x9a,
+	0x1a, 0xf7, 0x22, 0x56, 0x9c, 0x70, 0x44, 0x7e, 0xed, 0x17, 0xa7, 0xa1, 0xa2, 0x3e, 0xb8, 0x56,
+	0x15, 0x22, 0x72, 0xa0, 0x9c, 0xf8, 0x3f, 0x43, 0xb3, 0x87, 0x9e, 0x2f, 0x77, 0xc5, 0x8f, 0x62,
+	0xf5, 0xad, 0xb4, 0x3b, 0x1e, 0xf1, 0x19, 0x67, 0x3f, 0x87, 0xb6, 0xe5, 0xf7, 0x41, 0xc8, 0xd1,
+	0xf5, 0x31, 0x3a, 0xb2, 0xf1, 0x5f, 0x54, 0xf5, 0xf5, 0x31, 0xa9, 0xe2, 0x7d, 0x7f, 0x00, 0x39,
+	0xd1, 0xc2, 0x42, 0xaf, 0xa5, 0x67, 0x10, 0x7f, 0x92, 0x54, 0x8f, 0xb1, 0x85, 0xfd, 0x1c, 0xfa,
+	0xb9, 0x01, 0x66, 0xdc, 0xc6, 0x47, 0x8b, 0x69, 0x99, 0x8f, 0x7e, 0x1f, 0x54, 0x6f, 0x4c, 0x40,
+	0x19, 0xeb, 0xf6, 0x33, 0x28, 0x45, 0xfd, 0x47, 0xf4, 0x66, 0x6a, 0xfd, 0x86, 0x3b, 0xb7, 0xd5,
+	0xc5, 0xf1, 0x09, 0x63, 0x01, 0x5c, 0x98, 0x1e, 0x6e, 0x34, 0xa2, 0xdb, 0x63, 0xfa, 0x69, 0xb8,
+	0xff, 0xf2, 0x14, 0x83, 0x77, 0xa0, 0xa2, 0x3c, 0x13, 0xed, 0x74, 0x6b, 0x3c, 0x87, 0xa6, 0xde,
+	0xe8, 0x37, 0x06, 0x4c, 0x0f, 0xf7, 0xab, 0xd2, 0x2b, 0x75, 0x64, 0x5b, 0xaf, 0xfa, 0xdd, 0x49,
+	0xc9, 0x63, 0x3b, 0xff, 0xca, 0x80, 0xa9, 0x64, 0x13, 0x09, 0xbd, 0x35, 0x46, 0xd8, 0x1c, 0xd2,
+	0xfd, 0xd6, 0x64, 0xc4, 0xb1, 0x34, 0x4f, 0x0c, 0x80, 0x41, 0x9b, 0x03, 0xa5, 0x0e, 0xe1, 0x43,
+	0xfd, 0x9c, 0xea, 0xcd, 0x49, 0x48, 0x87, 0xac, 0x92, 0xac, 0xb0, 0xd3, 0x5b, 0xe5, 0x88, 0x76,
+	0x43, 0xf5, 0xd6, 0x64, 0xc4, 0xb1, 0x34, 0x7f, 0x34, 0x60, 0x66, 0xb4, 0xc0, 0x42, 0x77, 0xc6,
+	0x71, 0xfd, 0x11, 0xb5, 0x68, 0xf5, 0xed, 0xc9, 0x19, 0xc4, 0x92, 0xfd, 0xc9, 0x80, 0xd3, 0x87,
+	0x2a, 0x13, 0x94, 0x9a, 0xf3, 0x71, 0x05, 0x52, 0x75, 0xe9, 0x19, 0x38, 0xc4, 0xc2, 0xfd, 0xce,
+	0x80, 0x53, 0x23, 0xe5, 0x07, 0x4a, 0x7d, 0x60, 0x8e, 0xae, 0x80, 0xaa, 0x77, 0x26, 0xa6, 0x1f,
+	0x12, 0x6b, 0xa4, 0x64, 0x48, 0x2f, 0xd6, 0xd1, 0x95, 0x4e, 0xf5, 0xce, 0xc4, 0xf4, 0xb1, 0x58,
+	0x6d, 0x28, 0x27, 0xaa, 0x0c, 0x74, 0x33, 0x7d, 0xc9, 0x3b, 0x5a, 0x9a, 0x3c, 0xe5, 0x06, 0xd4,
+	0x0f, 0x02, 0xfd, 0x06, 0xf9, 0x66, 0x1e, 0x04, 0x23, 0x0f, 0x1a, 0xfb, 0xb9, 0xe5, 0xd2, 0x67,
+	0xba, 0xa4, 0x69, 0x15, 0x24, 0xe3, 0xd7, 0xfe, 0x37, 0x00, 0x75, 0x77, 0xee, 0xf2, 0x02, 0x25,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -53,30 +2813,180 @@ const _ = grpc.SupportPackageIsVersion4
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type CameraServiceClient interface {
-	Start(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
-	Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListCameras(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListCamerasResponse, error)
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
+	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error)
+	StartRecording(ctx context.Context, in *StartRecordingRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	StopRecording(ctx context.Context, in *StopRecordingRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListRecordings(ctx context.Context, in *ListRecordingsRequest, opts ...grpc.CallOption) (*ListRecordingsResponse, error)
+	GetRecording(ctx context.Context, in *GetRecordingRequest, opts ...grpc.CallOption) (*GetRecordingResponse, error)
+	ExportClip(ctx context.Context, in *ExportClipRequest, opts ...grpc.CallOption) (*ExportClipResponse, error)
+	TriggerEvent(ctx context.Context, in *TriggerEventRequest, opts ...grpc.CallOption) (*TriggerEventResponse, error)
+	ListMotionEvents(ctx context.Context, in *ListMotionEventsRequest, opts ...grpc.CallOption) (*ListMotionEventsResponse, error)
+	AssembleTimelapse(ctx context.Context, in *AssembleTimelapseRequest, opts ...grpc.CallOption) (*AssembleTimelapseResponse, error)
+	RotateStreamKey(ctx context.Context, in *RotateStreamKeyRequest, opts ...grpc.CallOption) (*RotateStreamKeyResponse, error)
+	NegotiateWebrtc(ctx context.Context, in *NegotiateWebrtcRequest, opts ...grpc.CallOption) (*NegotiateWebrtcResponse, error)
+	CloseWebrtc(ctx context.Context, in *CloseWebrtcRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListDevices(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListDevicesResponse, error)
 }
 
 type cameraServiceClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewCameraServiceClient(cc *grpc.ClientConn) CameraServiceClient {
-	return &cameraServiceClient{cc}
+func NewCameraServiceClient(cc *grpc.ClientConn) CameraServiceClient {
+	return &cameraServiceClient{cc}
+}
+
+func (c *cameraServiceClient) ListCameras(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListCamerasResponse, error) {
+	out := new(ListCamerasResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/ListCameras", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error) {
+	out := new(StartResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/Start", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/Stop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) Snapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error) {
+	out := new(SnapshotResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/Snapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) StartRecording(ctx context.Context, in *StartRecordingRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/StartRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) StopRecording(ctx context.Context, in *StopRecordingRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/StopRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) ListRecordings(ctx context.Context, in *ListRecordingsRequest, opts ...grpc.CallOption) (*ListRecordingsResponse, error) {
+	out := new(ListRecordingsResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/ListRecordings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) GetRecording(ctx context.Context, in *GetRecordingRequest, opts ...grpc.CallOption) (*GetRecordingResponse, error) {
+	out := new(GetRecordingResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/GetRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) ExportClip(ctx context.Context, in *ExportClipRequest, opts ...grpc.CallOption) (*ExportClipResponse, error) {
+	out := new(ExportClipResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/ExportClip", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) TriggerEvent(ctx context.Context, in *TriggerEventRequest, opts ...grpc.CallOption) (*TriggerEventResponse, error) {
+	out := new(TriggerEventResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/TriggerEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) ListMotionEvents(ctx context.Context, in *ListMotionEventsRequest, opts ...grpc.CallOption) (*ListMotionEventsResponse, error) {
+	out := new(ListMotionEventsResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/ListMotionEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) AssembleTimelapse(ctx context.Context, in *AssembleTimelapseRequest, opts ...grpc.CallOption) (*AssembleTimelapseResponse, error) {
+	out := new(AssembleTimelapseResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/AssembleTimelapse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) RotateStreamKey(ctx context.Context, in *RotateStreamKeyRequest, opts ...grpc.CallOption) (*RotateStreamKeyResponse, error) {
+	out := new(RotateStreamKeyResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/RotateStreamKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cameraServiceClient) NegotiateWebrtc(ctx context.Context, in *NegotiateWebrtcRequest, opts ...grpc.CallOption) (*NegotiateWebrtcResponse, error) {
+	out := new(NegotiateWebrtcResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/NegotiateWebrtc", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *cameraServiceClient) Start(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+func (c *cameraServiceClient) CloseWebrtc(ctx context.Context, in *CloseWebrtcRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/Start", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/CloseWebrtc", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *cameraServiceClient) Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/Stop", in, out, opts...)
+func (c *cameraServiceClient) ListDevices(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, "/ai.metathings.component.service.camera.CameraService/ListDevices", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -85,30 +2995,108 @@ func (c *cameraServiceClient) Stop(ctx context.Context, in *empty.Empty, opts ..
 
 // CameraServiceServer is the server API for CameraService service.
 type CameraServiceServer interface {
-	Start(context.Context, *empty.Empty) (*empty.Empty, error)
-	Stop(context.Context, *empty.Empty) (*empty.Empty, error)
+	ListCameras(context.Context, *empty.Empty) (*ListCamerasResponse, error)
+	Start(context.Context, *StartRequest) (*StartResponse, error)
+	Stop(context.Context, *StopRequest) (*empty.Empty, error)
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	Snapshot(context.Context, *SnapshotRequest) (*SnapshotResponse, error)
+	StartRecording(context.Context, *StartRecordingRequest) (*empty.Empty, error)
+	StopRecording(context.Context, *StopRecordingRequest) (*empty.Empty, error)
+	ListRecordings(context.Context, *ListRecordingsRequest) (*ListRecordingsResponse, error)
+	GetRecording(context.Context, *GetRecordingRequest) (*GetRecordingResponse, error)
+	ExportClip(context.Context, *ExportClipRequest) (*ExportClipResponse, error)
+	TriggerEvent(context.Context, *TriggerEventRequest) (*TriggerEventResponse, error)
+	ListMotionEvents(context.Context, *ListMotionEventsRequest) (*ListMotionEventsResponse, error)
+	AssembleTimelapse(context.Context, *AssembleTimelapseRequest) (*AssembleTimelapseResponse, error)
+	RotateStreamKey(context.Context, *RotateStreamKeyRequest) (*RotateStreamKeyResponse, error)
+	NegotiateWebrtc(context.Context, *NegotiateWebrtcRequest) (*NegotiateWebrtcResponse, error)
+	CloseWebrtc(context.Context, *CloseWebrtcRequest) (*empty.Empty, error)
+	ListDevices(context.Context, *empty.Empty) (*ListDevicesResponse, error)
 }
 
 // UnimplementedCameraServiceServer can be embedded to have forward compatible implementations.
 type UnimplementedCameraServiceServer struct {
 }
 
-func (*UnimplementedCameraServiceServer) Start(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+func (*UnimplementedCameraServiceServer) ListCameras(ctx context.Context, req *empty.Empty) (*ListCamerasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCameras not implemented")
+}
+func (*UnimplementedCameraServiceServer) Start(ctx context.Context, req *StartRequest) (*StartResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Start not implemented")
 }
-func (*UnimplementedCameraServiceServer) Stop(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+func (*UnimplementedCameraServiceServer) Stop(ctx context.Context, req *StopRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
 }
+func (*UnimplementedCameraServiceServer) GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (*UnimplementedCameraServiceServer) Snapshot(ctx context.Context, req *SnapshotRequest) (*SnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Snapshot not implemented")
+}
+func (*UnimplementedCameraServiceServer) StartRecording(ctx context.Context, req *StartRecordingRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRecording not implemented")
+}
+func (*UnimplementedCameraServiceServer) StopRecording(ctx context.Context, req *StopRecordingRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopRecording not implemented")
+}
+func (*UnimplementedCameraServiceServer) ListRecordings(ctx context.Context, req *ListRecordingsRequest) (*ListRecordingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecordings not implemented")
+}
+func (*UnimplementedCameraServiceServer) GetRecording(ctx context.Context, req *GetRecordingRequest) (*GetRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecording not implemented")
+}
+func (*UnimplementedCameraServiceServer) ExportClip(ctx context.Context, req *ExportClipRequest) (*ExportClipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportClip not implemented")
+}
+func (*UnimplementedCameraServiceServer) TriggerEvent(ctx context.Context, req *TriggerEventRequest) (*TriggerEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerEvent not implemented")
+}
+func (*UnimplementedCameraServiceServer) ListMotionEvents(ctx context.Context, req *ListMotionEventsRequest) (*ListMotionEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMotionEvents not implemented")
+}
+func (*UnimplementedCameraServiceServer) AssembleTimelapse(ctx context.Context, req *AssembleTimelapseRequest) (*AssembleTimelapseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssembleTimelapse not implemented")
+}
+func (*UnimplementedCameraServiceServer) RotateStreamKey(ctx context.Context, req *RotateStreamKeyRequest) (*RotateStreamKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateStreamKey not implemented")
+}
+func (*UnimplementedCameraServiceServer) NegotiateWebrtc(ctx context.Context, req *NegotiateWebrtcRequest) (*NegotiateWebrtcResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NegotiateWebrtc not implemented")
+}
+func (*UnimplementedCameraServiceServer) CloseWebrtc(ctx context.Context, req *CloseWebrtcRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseWebrtc not implemented")
+}
+func (*UnimplementedCameraServiceServer) ListDevices(ctx context.Context, req *empty.Empty) (*ListDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDevices not implemented")
+}
 
 func RegisterCameraServiceServer(s *grpc.Server, srv CameraServiceServer) {
 	s.RegisterService(&_CameraService_serviceDesc, srv)
 }
 
-func _CameraService_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _CameraService_ListCameras_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).ListCameras(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/ListCameras",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).ListCameras(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
 	if interceptor == nil {
 		return srv.(CameraServiceServer).Start(ctx, in)
 	}
@@ -117,13 +3105,13 @@ func _CameraService_Start_Handler(srv interface{}, ctx context.Context, dec func
 		FullMethod: "/ai.metathings.component.service.camera.CameraService/Start",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CameraServiceServer).Start(ctx, req.(*empty.Empty))
+		return srv.(CameraServiceServer).Start(ctx, req.(*StartRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
 func _CameraService_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+	in := new(StopRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -135,7 +3123,259 @@ func _CameraService_Stop_Handler(srv interface{}, ctx context.Context, dec func(
 		FullMethod: "/ai.metathings.component.service.camera.CameraService/Stop",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CameraServiceServer).Stop(ctx, req.(*empty.Empty))
+		return srv.(CameraServiceServer).Stop(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_Snapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).Snapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/Snapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).Snapshot(ctx, req.(*SnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_StartRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).StartRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/StartRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).StartRecording(ctx, req.(*StartRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_StopRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).StopRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/StopRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).StopRecording(ctx, req.(*StopRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_ListRecordings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecordingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).ListRecordings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/ListRecordings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).ListRecordings(ctx, req.(*ListRecordingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_GetRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).GetRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/GetRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).GetRecording(ctx, req.(*GetRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_ExportClip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportClipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).ExportClip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/ExportClip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).ExportClip(ctx, req.(*ExportClipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_TriggerEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).TriggerEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/TriggerEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).TriggerEvent(ctx, req.(*TriggerEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_ListMotionEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMotionEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).ListMotionEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/ListMotionEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).ListMotionEvents(ctx, req.(*ListMotionEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_AssembleTimelapse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssembleTimelapseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).AssembleTimelapse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/AssembleTimelapse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).AssembleTimelapse(ctx, req.(*AssembleTimelapseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_RotateStreamKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateStreamKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).RotateStreamKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/RotateStreamKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).RotateStreamKey(ctx, req.(*RotateStreamKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_NegotiateWebrtc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NegotiateWebrtcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).NegotiateWebrtc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/NegotiateWebrtc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).NegotiateWebrtc(ctx, req.(*NegotiateWebrtcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_CloseWebrtc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseWebrtcRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).CloseWebrtc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/CloseWebrtc",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).CloseWebrtc(ctx, req.(*CloseWebrtcRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CameraService_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CameraServiceServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ai.metathings.component.service.camera.CameraService/ListDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CameraServiceServer).ListDevices(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -144,6 +3384,10 @@ var _CameraService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "ai.metathings.component.service.camera.CameraService",
 	HandlerType: (*CameraServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCameras",
+			Handler:    _CameraService_ListCameras_Handler,
+		},
 		{
 			MethodName: "Start",
 			Handler:    _CameraService_Start_Handler,
@@ -152,6 +3396,62 @@ var _CameraService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Stop",
 			Handler:    _CameraService_Stop_Handler,
 		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _CameraService_GetStatus_Handler,
+		},
+		{
+			MethodName: "Snapshot",
+			Handler:    _CameraService_Snapshot_Handler,
+		},
+		{
+			MethodName: "StartRecording",
+			Handler:    _CameraService_StartRecording_Handler,
+		},
+		{
+			MethodName: "StopRecording",
+			Handler:    _CameraService_StopRecording_Handler,
+		},
+		{
+			MethodName: "ListRecordings",
+			Handler:    _CameraService_ListRecordings_Handler,
+		},
+		{
+			MethodName: "GetRecording",
+			Handler:    _CameraService_GetRecording_Handler,
+		},
+		{
+			MethodName: "ExportClip",
+			Handler:    _CameraService_ExportClip_Handler,
+		},
+		{
+			MethodName: "TriggerEvent",
+			Handler:    _CameraService_TriggerEvent_Handler,
+		},
+		{
+			MethodName: "ListMotionEvents",
+			Handler:    _CameraService_ListMotionEvents_Handler,
+		},
+		{
+			MethodName: "AssembleTimelapse",
+			Handler:    _CameraService_AssembleTimelapse_Handler,
+		},
+		{
+			MethodName: "RotateStreamKey",
+			Handler:    _CameraService_RotateStreamKey_Handler,
+		},
+		{
+			MethodName: "NegotiateWebrtc",
+			Handler:    _CameraService_NegotiateWebrtc_Handler,
+		},
+		{
+			MethodName: "CloseWebrtc",
+			Handler:    _CameraService_CloseWebrtc_Handler,
+		},
+		{
+			MethodName: "ListDevices",
+			Handler:    _CameraService_ListDevices_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
@@ -7,10 +7,520 @@ import (
 	fmt "fmt"
 	math "math"
 	proto "github.com/golang/protobuf/proto"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
+	_ "github.com/golang/protobuf/ptypes/duration"
+	_ "github.com/golang/protobuf/ptypes/wrappers"
+	_ "github.com/mwitkow/go-proto-validators"
 	_ "github.com/golang/protobuf/ptypes/empty"
+	regexp "regexp"
+	github_com_mwitkow_go_proto_validators "github.com/mwitkow/go-proto-validators"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
+
+func (this *Camera) Validate() error {
+	return nil
+}
+func (this *ListCamerasResponse) Validate() error {
+	for _, item := range this.Cameras {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Cameras", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *Output) Validate() error {
+	// Validation of proto3 map<> fields is unsupported.
+	return nil
+}
+
+var _regex_StartRequest_FrameSize = regexp.MustCompile(`^([1-9][0-9]*x[1-9][0-9]*)?$`)
+var _regex_StartRequest_BitRate = regexp.MustCompile(`^([1-9][0-9]*[kKmM]?)?$`)
+var _regex_StartRequest_Codec = regexp.MustCompile(`^[A-Za-z0-9_]*$`)
+var _regex_StartRequest_Output = regexp.MustCompile(`^([a-z][a-z0-9+.-]*://[^\s]+)?$`)
+var _regex_StartRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *StartRequest) Validate() error {
+	if !_regex_StartRequest_FrameSize.MatchString(this.FrameSize) {
+		return github_com_mwitkow_go_proto_validators.FieldError("FrameSize", fmt.Errorf(`value '%v' must be a string conforming to regex "^([1-9][0-9]*x[1-9][0-9]*)?$"`, this.FrameSize))
+	}
+	if !(this.FrameRate < 241) {
+		return github_com_mwitkow_go_proto_validators.FieldError("FrameRate", fmt.Errorf(`value '%v' must be less than '241'`, this.FrameRate))
+	}
+	if !_regex_StartRequest_BitRate.MatchString(this.BitRate) {
+		return github_com_mwitkow_go_proto_validators.FieldError("BitRate", fmt.Errorf(`value '%v' must be a string conforming to regex "^([1-9][0-9]*[kKmM]?)?$"`, this.BitRate))
+	}
+	if !_regex_StartRequest_Codec.MatchString(this.Codec) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Codec", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_]*$"`, this.Codec))
+	}
+	if !_regex_StartRequest_Output.MatchString(this.Output) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Output", fmt.Errorf(`value '%v' must be a string conforming to regex "^([a-z][a-z0-9+.-]*://[^\\s]+)?$"`, this.Output))
+	}
+	if this.Audio != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Audio); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Audio", err)
+		}
+	}
+	if !_regex_StartRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *StartResponse) Validate() error {
+	for _, item := range this.Outputs {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Outputs", err)
+			}
+		}
+	}
+	return nil
+}
+
+var _regex_StopRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *StopRequest) Validate() error {
+	if !_regex_StopRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *Stats) Validate() error {
+	if this.UpdateAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.UpdateAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("UpdateAt", err)
+		}
+	}
+	return nil
+}
+
+var _regex_GetStatusRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *GetStatusRequest) Validate() error {
+	if !_regex_GetStatusRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *GetStatusResponse) Validate() error {
+	for _, item := range this.Outputs {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Outputs", err)
+			}
+		}
+	}
+	if this.StartAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.StartAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("StartAt", err)
+		}
+	}
+	if this.Uptime != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Uptime); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Uptime", err)
+		}
+	}
+	if this.Stats != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Stats); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Stats", err)
+		}
+	}
+	if this.Schedule != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Schedule); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Schedule", err)
+		}
+	}
+	return nil
+}
+func (this *ScheduleStatus) Validate() error {
+	if this.NextTransitionAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.NextTransitionAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("NextTransitionAt", err)
+		}
+	}
+	return nil
+}
+
+var _regex_SnapshotRequest_Format = regexp.MustCompile(`^(jpeg|png)?$`)
+var _regex_SnapshotRequest_FrameSize = regexp.MustCompile(`^([1-9][0-9]*x[1-9][0-9]*)?$`)
+var _regex_SnapshotRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *SnapshotRequest) Validate() error {
+	if !_regex_SnapshotRequest_Format.MatchString(this.Format) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Format", fmt.Errorf(`value '%v' must be a string conforming to regex "^(jpeg|png)?$"`, this.Format))
+	}
+	if !_regex_SnapshotRequest_FrameSize.MatchString(this.FrameSize) {
+		return github_com_mwitkow_go_proto_validators.FieldError("FrameSize", fmt.Errorf(`value '%v' must be a string conforming to regex "^([1-9][0-9]*x[1-9][0-9]*)?$"`, this.FrameSize))
+	}
+	if !(this.Quality < 101) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Quality", fmt.Errorf(`value '%v' must be less than '101'`, this.Quality))
+	}
+	if this.Store != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Store); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Store", err)
+		}
+	}
+	if !_regex_SnapshotRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *SnapshotResponse) Validate() error {
+	if this.Timestamp != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Timestamp); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Timestamp", err)
+		}
+	}
+	return nil
+}
+
+var _regex_StartRecordingRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *StartRecordingRequest) Validate() error {
+	if !_regex_StartRecordingRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+
+var _regex_StopRecordingRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *StopRecordingRequest) Validate() error {
+	if !_regex_StopRecordingRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *Recording) Validate() error {
+	if this.StartAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.StartAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("StartAt", err)
+		}
+	}
+	if this.EndAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.EndAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("EndAt", err)
+		}
+	}
+	if this.Duration != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Duration); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Duration", err)
+		}
+	}
+	return nil
+}
+
+var _regex_ListRecordingsRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *ListRecordingsRequest) Validate() error {
+	if this.Begin != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Begin); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Begin", err)
+		}
+	}
+	if this.End != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.End); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("End", err)
+		}
+	}
+	if !(this.PageSize < 1001) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be less than '1001'`, this.PageSize))
+	}
+	if !_regex_ListRecordingsRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *ListRecordingsResponse) Validate() error {
+	for _, item := range this.Recordings {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Recordings", err)
+			}
+		}
+	}
+	return nil
+}
+
+var _regex_GetRecordingRequest_Name = regexp.MustCompile(`^[^/]+$`)
+var _regex_GetRecordingRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *GetRecordingRequest) Validate() error {
+	if !_regex_GetRecordingRequest_Name.MatchString(this.Name) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Name", fmt.Errorf(`value '%v' must be a string conforming to regex "^[^/]+$"`, this.Name))
+	}
+	if !_regex_GetRecordingRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *GetRecordingResponse) Validate() error {
+	if this.Recording != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Recording); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Recording", err)
+		}
+	}
+	return nil
+}
+func (this *Clip) Validate() error {
+	if this.StartAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.StartAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("StartAt", err)
+		}
+	}
+	if this.EndAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.EndAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("EndAt", err)
+		}
+	}
+	return nil
+}
+
+var _regex_ExportClipRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *ExportClipRequest) Validate() error {
+	if nil == this.Begin {
+		return github_com_mwitkow_go_proto_validators.FieldError("Begin", fmt.Errorf("message must exist"))
+	}
+	if this.Begin != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Begin); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Begin", err)
+		}
+	}
+	if nil == this.End {
+		return github_com_mwitkow_go_proto_validators.FieldError("End", fmt.Errorf("message must exist"))
+	}
+	if this.End != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.End); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("End", err)
+		}
+	}
+	if !_regex_ExportClipRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *ExportClipResponse) Validate() error {
+	if this.Clip != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Clip); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Clip", err)
+		}
+	}
+	return nil
+}
+func (this *Event) Validate() error {
+	if this.TriggerAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.TriggerAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("TriggerAt", err)
+		}
+	}
+	if this.StartAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.StartAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("StartAt", err)
+		}
+	}
+	if this.EndAt != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.EndAt); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("EndAt", err)
+		}
+	}
+	return nil
+}
+
+var _regex_TriggerEventRequest_Label = regexp.MustCompile(`^[A-Za-z0-9_.-]{0,64}$`)
+var _regex_TriggerEventRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *TriggerEventRequest) Validate() error {
+	if !_regex_TriggerEventRequest_Label.MatchString(this.Label) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Label", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_.-]{0,64}$"`, this.Label))
+	}
+	if this.Pre != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Pre); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Pre", err)
+		}
+	}
+	if this.Post != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Post); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Post", err)
+		}
+	}
+	if !_regex_TriggerEventRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *TriggerEventResponse) Validate() error {
+	if this.Event != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Event); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Event", err)
+		}
+	}
+	return nil
+}
+func (this *Region) Validate() error {
+	return nil
+}
+func (this *MotionEvent) Validate() error {
+	if this.Timestamp != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Timestamp); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Timestamp", err)
+		}
+	}
+	if this.Region != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Region); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Region", err)
+		}
+	}
+	return nil
+}
+
+var _regex_ListMotionEventsRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *ListMotionEventsRequest) Validate() error {
+	if this.Begin != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Begin); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Begin", err)
+		}
+	}
+	if this.End != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.End); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("End", err)
+		}
+	}
+	if !(this.PageSize < 1001) {
+		return github_com_mwitkow_go_proto_validators.FieldError("PageSize", fmt.Errorf(`value '%v' must be less than '1001'`, this.PageSize))
+	}
+	if !_regex_ListMotionEventsRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *ListMotionEventsResponse) Validate() error {
+	for _, item := range this.Events {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Events", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *Timelapse) Validate() error {
+	if this.Clip != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Clip); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Clip", err)
+		}
+	}
+	return nil
+}
+
+var _regex_AssembleTimelapseRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *AssembleTimelapseRequest) Validate() error {
+	if !_regex_AssembleTimelapseRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *AssembleTimelapseResponse) Validate() error {
+	if this.Timelapse != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Timelapse); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Timelapse", err)
+		}
+	}
+	return nil
+}
+
+var _regex_RotateStreamKeyRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *RotateStreamKeyRequest) Validate() error {
+	if !_regex_RotateStreamKeyRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *RotateStreamKeyResponse) Validate() error {
+	for _, item := range this.Outputs {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Outputs", err)
+			}
+		}
+	}
+	return nil
+}
+
+var _regex_NegotiateWebrtcRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *NegotiateWebrtcRequest) Validate() error {
+	if this.Offer == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Offer", fmt.Errorf(`value '%v' must not be an empty string`, this.Offer))
+	}
+	if !_regex_NegotiateWebrtcRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *NegotiateWebrtcResponse) Validate() error {
+	return nil
+}
+
+var _regex_CloseWebrtcRequest_Camera = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func (this *CloseWebrtcRequest) Validate() error {
+	if this.Session == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Session", fmt.Errorf(`value '%v' must not be an empty string`, this.Session))
+	}
+	if !_regex_CloseWebrtcRequest_Camera.MatchString(this.Camera) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Camera", fmt.Errorf(`value '%v' must be a string conforming to regex "^[A-Za-z0-9_-]*$"`, this.Camera))
+	}
+	return nil
+}
+func (this *FrameInterval) Validate() error {
+	return nil
+}
+func (this *FrameSize) Validate() error {
+	for _, item := range this.Intervals {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Intervals", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *DeviceFormat) Validate() error {
+	for _, item := range this.FrameSizes {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("FrameSizes", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *Device) Validate() error {
+	for _, item := range this.Formats {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Formats", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *ListDevicesResponse) Validate() error {
+	for _, item := range this.Devices {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Devices", err)
+			}
+		}
+	}
+	return nil
+}